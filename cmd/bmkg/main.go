@@ -2,21 +2,50 @@ package main
 
 import (
 	_ "bmkg/migrations"
+	"bmkg/src/config"
 	"bmkg/src/handler"
+	"bmkg/src/mail"
+	"bmkg/src/messages"
 	"bmkg/src/repository"
+	"bmkg/src/utils"
+	"bmkg/src/utils/ngitung"
+	"bmkg/src/utils/notify"
 	"bmkg/src/worker/bmkg"
+	"bmkg/src/worker/felt"
 	"bmkg/src/worker/mqtt"
+	"bmkg/src/worker/outbox"
+	"bmkg/src/worker/safety"
 	"bmkg/src/worker/telegram"
+	"errors"
 	"github.com/pocketbase/pocketbase"
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/plugins/migratecmd"
 	"log"
 	"os"
 	"strings"
+	// zona waktu subscriber untuk quiet hours, juga di image tanpa zoneinfo
+	_ "time/tzdata"
 )
 
 func main() {
 	app := pocketbase.New()
+	cfg := config.LoadConfig()
+
+	// model ground-motion untuk estimasi intensitas, default Fukushima-Tanaka
+	if err := ngitung.SetModel(cfg.GMPEModel); err != nil {
+		log.Fatal(err)
+	}
+
+	// tier peringatan (JSON), default informational/warning/severe
+	if err := bmkg.SetTiers(cfg.AlertTiers); err != nil {
+		log.Fatal(err)
+	}
+
+	// check-in keselamatan anggota grup mulai MMI ini, default VI
+	safety.SetCheckinMMI(cfg.SafetyCheckinMMI)
+
+	// template pesan (message_template), bisa diubah admin tanpa redeploy
+	messages.Bind(app)
 
 	// loosely check if it was executed using "go run"
 	isGoRun := strings.HasPrefix(os.Args[0], os.TempDir())
@@ -37,36 +66,125 @@ func main() {
 	// Initialize MQTT client
 	mqttClient := mqtt.NewMQTTClient(mqttconfig)
 	mqttClient.Connect()
-	bot, err := telegram.NewBot(app)
+
+	// bot Telegram, webhook bila TELEGRAM_WEBHOOK_URL diisi, selain itu long polling.
+	// Tanpa TELEGRAM_BOT_TOKEN bot dimatikan, serve dan migrate tetap jalan.
+	bot, err := telegram.NewBot(app, telegram.Config{
+		Token:         cfg.TelegramBotToken,
+		WebhookURL:    cfg.TelegramWebhookURL,
+		WebhookSecret: cfg.TelegramWebhookSecret,
+	})
+	if errors.Is(err, telegram.ErrNoToken) {
+		log.Printf("Telegram bot disabled: %v", err)
+	} else if err != nil {
+		log.Fatalf("Failed to start Telegram bot: %v", err)
+	}
 
 	// new repo
 	bmkgRepo := repository.NewBMKGRepository(app)
 	iotRepo := repository.NewIotRepository(app)
-
-	bmkgWorker := bmkg.NewBMKGWorker(bmkgRepo, app, mqttClient, bot)
-	if err != nil {
-		return
+	stateRepo := repository.NewStateRepository(app)
+	outboxRepo := repository.NewOutboxRepository(app)
+	pushTokenRepo := repository.NewPushTokenRepository(app)
+	webhookRepo := repository.NewWebhookRepository(app)
+	subscriberRepo := repository.NewSubscriberRepository(app)
+	feltRepo := repository.NewFeltRepository(app)
+	safetyRepo := repository.NewSafetyRepository(app)
+
+	outboxWorker := outbox.NewWorker(outboxRepo)
+	safetyWorker := safety.NewWorker(app, safetyRepo, bmkgRepo, subscriberRepo, outboxWorker)
+	bmkgWorker := bmkg.NewBMKGWorker(bmkgRepo, stateRepo, webhookRepo, app, outboxWorker, safetyWorker)
+	feltWorker := felt.NewWorker(feltRepo, stateRepo)
+
+	// notification channels, the worker dispatches alerts through this registry
+	notify.Register(notify.NewMQTTNotifier(mqttClient))
+	if bot != nil {
+		notify.Register(notify.NewTelegramNotifier(bot))
+	}
+	notify.Register(notify.NewWhatsAppNotifier(notify.WhatsAppConfig{
+		PhoneNumberID: cfg.WhatsAppPhoneNumberID,
+		AccessToken:   cfg.WhatsAppAccessToken,
+		Template:      cfg.WhatsAppTemplate,
+		// template dengan header IMAGE untuk shakemap
+		TemplateHeaderImage: cfg.WhatsAppTemplateImage,
+	}, utils.NewHTTPClient()))
+
+	// email lewat SMTP_HOST, atau pengaturan SMTP PocketBase bila kosong
+	mailer := mail.NewMailer(app, mail.Config{
+		Host:     cfg.SMTPHost,
+		Port:     cfg.SMTPPort,
+		Username: cfg.SMTPUsername,
+		Password: cfg.SMTPPassword,
+		TLS:      cfg.SMTPTLS,
+		From:     cfg.MailFrom,
+		FromName: cfg.MailFromName,
+	})
+	mail.SetDefault(mailer)
+	notify.Register(notify.NewEmailNotifier(mailer, utils.NewHTTPClient()))
+
+	// FCM service account, tanpa file ini notifikasi android gagal permanen
+	var fcmAccount notify.FCMServiceAccount
+	if cfg.FCMCredentialsFile != "" {
+		if fcmAccount, err = notify.LoadFCMServiceAccount(cfg.FCMCredentialsFile); err != nil {
+			log.Printf("Android push disabled: %v", err)
+		}
 	}
+	notify.Register(notify.NewAndroidNotifier(fcmAccount, pushTokenRepo, utils.NewHTTPClient()))
+	notify.Register(notify.NewWebhookNotifier(webhookRepo, utils.NewHTTPClient()))
 
-	go bot.Start()
+	if bot != nil {
+		go bot.Start()
+	}
 
 	// handler
 	iotHandler := handler.NewIotHandler(mqttClient, iotRepo, app)
 	bmkgHandler := handler.NewBMKGHandler()
+	outboxHandler := handler.NewOutboxHandler(outboxWorker)
+	whatsAppHandler := handler.NewWhatsAppHandler(outboxRepo, cfg.WhatsAppVerifyToken, cfg.WhatsAppAppSecret)
+	pushHandler := handler.NewPushHandler(pushTokenRepo, subscriberRepo)
+	webhookHandler := handler.NewWebhookHandler(webhookRepo, outboxRepo)
+	preferencesHandler := handler.NewPreferencesHandler(subscriberRepo)
+	locationHandler := handler.NewLocationHandler(subscriberRepo)
+	feltHandler := handler.NewFeltHandler(feltRepo, subscriberRepo, bmkgRepo)
+	safetyHandler := handler.NewSafetyHandler(safetyRepo, subscriberRepo, bmkgRepo, safetyWorker)
 
 	//
 
 	app.OnTerminate().BindFunc(func(e *core.TerminateEvent) error {
+		bmkgWorker.StopWorker()
+		outboxWorker.StopWorker()
+		feltWorker.StopWorker()
+		safetyWorker.StopWorker()
+		if bot != nil {
+			bot.Stop()
+		}
 		mqttClient.Disconnect()
-		return nil
+		return e.Next()
 	})
 
 	app.OnServe().BindFunc(func(se *core.ServeEvent) error {
+		if err := messages.Load(app); err != nil {
+			log.Printf("Using built-in message templates: %v", err)
+		}
+
+		outboxWorker.StartWorker()
 		bmkgWorker.StartWorker()
-		bmkgWorker.StopWorker()
+		feltWorker.StartWorker()
+		safetyWorker.StartWorker()
 
 		bmkgHandler.AddBMKGHandler(se.Router)
 		handler.AddAdminHandler(se.Router)
+		outboxHandler.AddOutboxHandler(se.Router)
+		whatsAppHandler.AddWhatsAppHandler(se.Router)
+		pushHandler.AddPushHandler(se.Router)
+		webhookHandler.AddWebhookHandler(se.Router)
+		preferencesHandler.AddPreferencesHandler(se.Router)
+		locationHandler.AddLocationHandler(se.Router)
+		feltHandler.AddFeltHandler(se.Router)
+		safetyHandler.AddSafetyHandler(se.Router)
+		if bot != nil {
+			bot.AddWebhookHandler(se.Router)
+		}
 		iotHandler.AddIotHandler(se.Router)
 
 		return se.Next()
@@ -0,0 +1,314 @@
+package migrations
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("pbc_428798634")
+		if err != nil {
+			return err
+		}
+
+		// the numeric field takes the name "magnitude", field names are case-insensitive
+
+		// update field
+		if err := collection.Fields.AddMarshaledJSONAt(4, []byte(`{
+			"autogeneratePattern": "",
+			"hidden": false,
+			"id": "text3078029218",
+			"max": 0,
+			"min": 0,
+			"name": "MagnitudeText",
+			"pattern": "",
+			"presentable": false,
+			"primaryKey": false,
+			"required": false,
+			"system": false,
+			"type": "text"
+		}`)); err != nil {
+			return err
+		}
+
+		if err := app.Save(collection); err != nil {
+			return err
+		}
+
+		// update collection data
+		if err := json.Unmarshal([]byte(`{
+			"indexes": [
+				"CREATE UNIQUE INDEX idx_earthquake_fingerprint ON earthquake (fingerprint) WHERE fingerprint != ''",
+				"CREATE INDEX idx_earthquake_origin_time ON earthquake (origin_time)",
+				"CREATE INDEX idx_earthquake_magnitude ON earthquake (magnitude)"
+			]
+		}`), &collection); err != nil {
+			return err
+		}
+
+		// add field
+		if err := collection.Fields.AddMarshaledJSONAt(15, []byte(`{
+			"hidden": false,
+			"id": "number898071809",
+			"max": null,
+			"min": null,
+			"name": "magnitude",
+			"onlyInt": false,
+			"presentable": false,
+			"required": false,
+			"system": false,
+			"type": "number"
+		}`)); err != nil {
+			return err
+		}
+
+		// add field
+		if err := collection.Fields.AddMarshaledJSONAt(16, []byte(`{
+			"hidden": false,
+			"id": "number3271901260",
+			"max": null,
+			"min": null,
+			"name": "depth_km",
+			"onlyInt": false,
+			"presentable": false,
+			"required": false,
+			"system": false,
+			"type": "number"
+		}`)); err != nil {
+			return err
+		}
+
+		// add field
+		if err := collection.Fields.AddMarshaledJSONAt(17, []byte(`{
+			"hidden": false,
+			"id": "number2499937429",
+			"max": 90,
+			"min": -90,
+			"name": "lat",
+			"onlyInt": false,
+			"presentable": false,
+			"required": false,
+			"system": false,
+			"type": "number"
+		}`)); err != nil {
+			return err
+		}
+
+		// add field
+		if err := collection.Fields.AddMarshaledJSONAt(18, []byte(`{
+			"hidden": false,
+			"id": "number4142125153",
+			"max": 180,
+			"min": -180,
+			"name": "lon",
+			"onlyInt": false,
+			"presentable": false,
+			"required": false,
+			"system": false,
+			"type": "number"
+		}`)); err != nil {
+			return err
+		}
+
+		// add field
+		if err := collection.Fields.AddMarshaledJSONAt(19, []byte(`{
+			"hidden": false,
+			"id": "date2830786741",
+			"max": "",
+			"min": "",
+			"name": "origin_time",
+			"presentable": false,
+			"required": false,
+			"system": false,
+			"type": "date"
+		}`)); err != nil {
+			return err
+		}
+
+		if err := app.Save(collection); err != nil {
+			return err
+		}
+
+		return backfillEarthquakeTypedFields(app)
+	}, func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("pbc_428798634")
+		if err != nil {
+			return err
+		}
+
+		// update collection data
+		if err := json.Unmarshal([]byte(`{
+			"indexes": [
+				"CREATE UNIQUE INDEX idx_earthquake_fingerprint ON earthquake (fingerprint) WHERE fingerprint != ''"
+			]
+		}`), &collection); err != nil {
+			return err
+		}
+
+		// remove field
+		collection.Fields.RemoveById("number898071809")
+
+		// remove field
+		collection.Fields.RemoveById("number3271901260")
+
+		// remove field
+		collection.Fields.RemoveById("number2499937429")
+
+		// remove field
+		collection.Fields.RemoveById("number4142125153")
+
+		// remove field
+		collection.Fields.RemoveById("date2830786741")
+
+		if err := app.Save(collection); err != nil {
+			return err
+		}
+
+		// update field
+		if err := collection.Fields.AddMarshaledJSONAt(4, []byte(`{
+			"autogeneratePattern": "",
+			"hidden": false,
+			"id": "text3078029218",
+			"max": 0,
+			"min": 0,
+			"name": "Magnitude",
+			"pattern": "",
+			"presentable": false,
+			"primaryKey": false,
+			"required": false,
+			"system": false,
+			"type": "text"
+		}`)); err != nil {
+			return err
+		}
+
+		return app.Save(collection)
+	})
+}
+
+// backfillEarthquakeTypedFields parses the text columns of the existing rows into the typed ones.
+// Rows that cannot be parsed keep their typed fields empty. The parsers below are frozen
+// copies of the utils ones, so later changes to utils do not change what this migration stores.
+func backfillEarthquakeTypedFields(app core.App) error {
+	records, err := app.FindAllRecords("earthquake")
+	if err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		if magnitude, err := strconv.ParseFloat(record.GetString("MagnitudeText"), 64); err == nil {
+			record.Set("magnitude", magnitude)
+		}
+
+		if depth, err := strconv.ParseFloat(backfillExtractNumber(record.GetString("Kedalaman")), 64); err == nil {
+			record.Set("depth_km", depth)
+		}
+
+		if lat, lon, err := backfillParseCoordinates(record.GetString("Coordinates")); err == nil {
+			record.Set("lat", lat)
+			record.Set("lon", lon)
+		} else {
+			if lat, err := backfillParseCoordinate(record.GetString("Lintang")); err == nil {
+				record.Set("lat", lat)
+			}
+			if lon, err := backfillParseCoordinate(record.GetString("Bujur")); err == nil {
+				record.Set("lon", lon)
+			}
+		}
+
+		var originTime time.Time
+		if t, err := backfillParseWIB(record.GetString("Tanggal"), record.GetString("Jam")); err == nil {
+			originTime = t
+		} else if dateTime := record.GetDateTime("DateTime"); !dateTime.IsZero() {
+			originTime = dateTime.Time().UTC()
+		}
+		if !originTime.IsZero() {
+			record.Set("origin_time", originTime)
+		}
+
+		if err := app.SaveNoValidate(record); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// backfillWIB is Western Indonesia Time (UTC+7), the zone BMKG publishes Tanggal and Jam in
+var backfillWIB = time.FixedZone("WIB", 7*60*60)
+
+// backfillMonths maps the Indonesian month abbreviations used by BMKG to the ones time.Parse knows
+var backfillMonths = strings.NewReplacer(
+	"Mei", "May",
+	"Agu", "Aug",
+	"Agt", "Aug",
+	"Okt", "Oct",
+	"Des", "Dec",
+)
+
+// backfillExtractNumber keeps only the digits, dots and minus signs of input, e.g. "10 km" -> "10"
+func backfillExtractNumber(input string) string {
+	var result strings.Builder
+	for _, char := range input {
+		if unicode.IsDigit(char) || char == '.' || char == '-' {
+			result.WriteRune(char)
+		}
+	}
+	return result.String()
+}
+
+// backfillParseCoordinate converts a BMKG coordinate string such as "6.77 LS" into a signed decimal degree
+func backfillParseCoordinate(input string) (float64, error) {
+	value, err := strconv.ParseFloat(backfillExtractNumber(input), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid coordinate %q: %w", input, err)
+	}
+
+	upper := strings.ToUpper(input)
+	if (strings.HasSuffix(upper, "LS") || strings.HasSuffix(upper, "BB")) && value > 0 {
+		value = -value
+	}
+
+	return value, nil
+}
+
+// backfillParseCoordinates parses the BMKG "lat,lon" pair, e.g. "-6.77,105.51"
+func backfillParseCoordinates(input string) (float64, float64, error) {
+	parts := strings.Split(input, ",")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid coordinates %q", input)
+	}
+
+	lat, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid latitude %q: %w", parts[0], err)
+	}
+
+	lon, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid longitude %q: %w", parts[1], err)
+	}
+
+	return lat, lon, nil
+}
+
+// backfillParseWIB parses the BMKG Tanggal and Jam pair, e.g. "03 Apr 2025" and "12:12:34 WIB",
+// and returns the moment in UTC
+func backfillParseWIB(tanggal, jam string) (time.Time, error) {
+	value := backfillMonths.Replace(strings.TrimSpace(tanggal)) + " " + strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(jam), "WIB"))
+
+	t, err := time.ParseInLocation("02 Jan 2006 15:04:05", value, backfillWIB)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid WIB time %q: %w", value, err)
+	}
+
+	return t.UTC(), nil
+}
@@ -0,0 +1,84 @@
+package migrations
+
+import (
+	"encoding/json"
+
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("pbc_4009037223")
+		if err != nil {
+			return err
+		}
+
+		// update collection data
+		if err := json.Unmarshal([]byte(`{
+			"indexes": [
+				"CREATE UNIQUE INDEX idx_notification_outbox_dedupe_key ON notification_outbox (dedupe_key)",
+				"CREATE INDEX idx_notification_outbox_due ON notification_outbox (status, channel, next_attempt_at)"
+			]
+		}`), &collection); err != nil {
+			return err
+		}
+
+		// add field
+		if err := collection.Fields.AddMarshaledJSONAt(12, []byte(`{
+			"hidden": false,
+			"id": "number1534283127",
+			"max": null,
+			"min": 0,
+			"name": "mmi",
+			"onlyInt": false,
+			"presentable": false,
+			"required": false,
+			"system": false,
+			"type": "number"
+		}`)); err != nil {
+			return err
+		}
+
+		// add field
+		if err := collection.Fields.AddMarshaledJSONAt(13, []byte(`{
+			"hidden": false,
+			"id": "number1492385349",
+			"max": null,
+			"min": 0,
+			"name": "distance_km",
+			"onlyInt": false,
+			"presentable": false,
+			"required": false,
+			"system": false,
+			"type": "number"
+		}`)); err != nil {
+			return err
+		}
+
+		return app.Save(collection)
+	}, func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("pbc_4009037223")
+		if err != nil {
+			return err
+		}
+
+		// update collection data
+		if err := json.Unmarshal([]byte(`{
+			"indexes": [
+				"CREATE UNIQUE INDEX idx_notification_outbox_dedupe_key ON notification_outbox (dedupe_key)",
+				"CREATE INDEX idx_notification_outbox_due ON notification_outbox (status, next_attempt_at)"
+			]
+		}`), &collection); err != nil {
+			return err
+		}
+
+		// remove field
+		collection.Fields.RemoveById("number1534283127")
+
+		// remove field
+		collection.Fields.RemoveById("number1492385349")
+
+		return app.Save(collection)
+	})
+}
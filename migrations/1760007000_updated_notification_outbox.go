@@ -0,0 +1,89 @@
+package migrations
+
+import (
+	"encoding/json"
+
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("pbc_4009037223")
+		if err != nil {
+			return err
+		}
+
+		// update collection data
+		if err := json.Unmarshal([]byte(`{
+			"indexes": [
+				"CREATE UNIQUE INDEX idx_notification_outbox_dedupe_key ON notification_outbox (dedupe_key)",
+				"CREATE INDEX idx_notification_outbox_due ON notification_outbox (status, channel, next_attempt_at)",
+				"CREATE INDEX idx_notification_outbox_provider_message_id ON notification_outbox (provider_message_id) WHERE provider_message_id != ''"
+			]
+		}`), &collection); err != nil {
+			return err
+		}
+
+		// add field
+		if err := collection.Fields.AddMarshaledJSONAt(14, []byte(`{
+			"autogeneratePattern": "",
+			"hidden": false,
+			"id": "text1993105297",
+			"max": 0,
+			"min": 0,
+			"name": "provider_message_id",
+			"pattern": "",
+			"presentable": false,
+			"primaryKey": false,
+			"required": false,
+			"system": false,
+			"type": "text"
+		}`)); err != nil {
+			return err
+		}
+
+		// add field
+		if err := collection.Fields.AddMarshaledJSONAt(15, []byte(`{
+			"autogeneratePattern": "",
+			"hidden": false,
+			"id": "text741443212",
+			"max": 0,
+			"min": 0,
+			"name": "provider_status",
+			"pattern": "",
+			"presentable": false,
+			"primaryKey": false,
+			"required": false,
+			"system": false,
+			"type": "text"
+		}`)); err != nil {
+			return err
+		}
+
+		return app.Save(collection)
+	}, func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("pbc_4009037223")
+		if err != nil {
+			return err
+		}
+
+		// update collection data
+		if err := json.Unmarshal([]byte(`{
+			"indexes": [
+				"CREATE UNIQUE INDEX idx_notification_outbox_dedupe_key ON notification_outbox (dedupe_key)",
+				"CREATE INDEX idx_notification_outbox_due ON notification_outbox (status, channel, next_attempt_at)"
+			]
+		}`), &collection); err != nil {
+			return err
+		}
+
+		// remove field
+		collection.Fields.RemoveById("text1993105297")
+
+		// remove field
+		collection.Fields.RemoveById("text741443212")
+
+		return app.Save(collection)
+	})
+}
@@ -0,0 +1,125 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("pbc_3034109313")
+		if err != nil {
+			return err
+		}
+
+		// add field
+		if err := collection.Fields.AddMarshaledJSONAt(6, []byte(`{
+			"hidden": false,
+			"id": "number1784914799",
+			"max": null,
+			"min": 0,
+			"name": "min_magnitude",
+			"onlyInt": false,
+			"presentable": false,
+			"required": false,
+			"system": false,
+			"type": "number"
+		}`)); err != nil {
+			return err
+		}
+
+		// add field
+		if err := collection.Fields.AddMarshaledJSONAt(7, []byte(`{
+			"hidden": false,
+			"id": "number1235807860",
+			"max": 12,
+			"min": 0,
+			"name": "min_mmi",
+			"onlyInt": false,
+			"presentable": false,
+			"required": false,
+			"system": false,
+			"type": "number"
+		}`)); err != nil {
+			return err
+		}
+
+		// add field
+		if err := collection.Fields.AddMarshaledJSONAt(8, []byte(`{
+			"autogeneratePattern": "",
+			"hidden": false,
+			"id": "text2418280363",
+			"max": 0,
+			"min": 0,
+			"name": "quiet_start",
+			"pattern": "^([01][0-9]|2[0-3]):[0-5][0-9]$",
+			"presentable": false,
+			"primaryKey": false,
+			"required": false,
+			"system": false,
+			"type": "text"
+		}`)); err != nil {
+			return err
+		}
+
+		// add field
+		if err := collection.Fields.AddMarshaledJSONAt(9, []byte(`{
+			"autogeneratePattern": "",
+			"hidden": false,
+			"id": "text3287775307",
+			"max": 0,
+			"min": 0,
+			"name": "quiet_end",
+			"pattern": "^([01][0-9]|2[0-3]):[0-5][0-9]$",
+			"presentable": false,
+			"primaryKey": false,
+			"required": false,
+			"system": false,
+			"type": "text"
+		}`)); err != nil {
+			return err
+		}
+
+		// add field
+		if err := collection.Fields.AddMarshaledJSONAt(10, []byte(`{
+			"autogeneratePattern": "",
+			"hidden": false,
+			"id": "text922858135",
+			"max": 0,
+			"min": 0,
+			"name": "timezone",
+			"pattern": "",
+			"presentable": false,
+			"primaryKey": false,
+			"required": false,
+			"system": false,
+			"type": "text"
+		}`)); err != nil {
+			return err
+		}
+
+		return app.Save(collection)
+	}, func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("pbc_3034109313")
+		if err != nil {
+			return err
+		}
+
+		// remove field
+		collection.Fields.RemoveById("number1784914799")
+
+		// remove field
+		collection.Fields.RemoveById("number1235807860")
+
+		// remove field
+		collection.Fields.RemoveById("text2418280363")
+
+		// remove field
+		collection.Fields.RemoveById("text3287775307")
+
+		// remove field
+		collection.Fields.RemoveById("text922858135")
+
+		return app.Save(collection)
+	})
+}
@@ -0,0 +1,65 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+// primaryLocationLabel is the label of the location copied from user_notify
+const primaryLocationLabel = "Lokasi utama"
+
+func init() {
+	m.Register(func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("pbc_1843736702")
+		if err != nil {
+			return err
+		}
+
+		subscribers, err := app.FindAllRecords("user_notify")
+		if err != nil {
+			return err
+		}
+
+		// lintang/bujur of user_notify become the first saved location of the subscriber
+		for _, subscriber := range subscribers {
+			if subscriber.GetString("lintang") == "" || subscriber.GetString("bujur") == "" {
+				continue
+			}
+
+			location := core.NewRecord(collection)
+			location.Set("subscriber", subscriber.Id)
+			location.Set("label", primaryLocationLabel)
+			location.Set("lintang", subscriber.GetString("lintang"))
+			location.Set("bujur", subscriber.GetString("bujur"))
+			if err := app.Save(location); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}, func(app core.App) error {
+		locations, err := app.FindAllRecords("pbc_1843736702")
+		if err != nil {
+			return err
+		}
+
+		// Write the first location back so targeting keeps working on the old code
+		for _, location := range locations {
+			subscriber, err := app.FindRecordById("user_notify", location.GetString("subscriber"))
+			if err != nil {
+				continue
+			}
+			if subscriber.GetString("lintang") != "" && location.GetString("label") != primaryLocationLabel {
+				continue
+			}
+
+			subscriber.Set("lintang", location.GetString("lintang"))
+			subscriber.Set("bujur", location.GetString("bujur"))
+			if err := app.Save(subscriber); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,168 @@
+package migrations
+
+import (
+	"bmkg/src/messages"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("pbc_2150196503")
+		if err != nil {
+			return err
+		}
+
+		// the built-in wording becomes editable in the dashboard. The bodies are frozen
+		// here, later wording changes come with their own migration.
+		for _, item := range []messages.Template{
+			{Key: messages.KeyAlert, Tier: "informational", Language: messages.LanguageIndonesian, Body: "Info Gempa M{{.Magnitude}}" +
+				"\nLokasi: {{.Lintang}}, {{.Bujur}} ({{.Wilayah}})" +
+				"\nKedalaman: {{.Kedalaman}}" +
+				"\nWaktu: {{.Tanggal}} {{.Jam}}" +
+				"\nPerkiraan di lokasi Anda: MMI {{.MMIRoman}}, {{printf \"%.0f\" .Distance}} km dari episenter"},
+			{Key: messages.KeyAlert, Tier: "informational", Language: messages.LanguageEnglish, Body: "Earthquake Info M{{.Magnitude}}" +
+				"\nLocation: {{.Lintang}}, {{.Bujur}} ({{.Wilayah}})" +
+				"\nDepth: {{.Kedalaman}}" +
+				"\nTime: {{.Tanggal}} {{.Jam}}" +
+				"\nEstimate at your location: MMI {{.MMIRoman}}, {{printf \"%.0f\" .Distance}} km from the epicenter"},
+			{Key: messages.KeyAlert, Tier: "warning", Language: messages.LanguageIndonesian, Body: "PERINGATAN Gempa M{{.Magnitude}}" +
+				"\nGuncangan kuat diperkirakan di lokasi Anda: MMI {{.MMIRoman}}, {{printf \"%.0f\" .Distance}} km dari episenter" +
+				"\nLokasi: {{.Lintang}}, {{.Bujur}} ({{.Wilayah}})" +
+				"\nKedalaman: {{.Kedalaman}}" +
+				"\nWaktu: {{.Tanggal}} {{.Jam}}" +
+				"\nWaspada gempa susulan."},
+			{Key: messages.KeyAlert, Tier: "warning", Language: messages.LanguageEnglish, Body: "WARNING Earthquake M{{.Magnitude}}" +
+				"\nStrong shaking expected at your location: MMI {{.MMIRoman}}, {{printf \"%.0f\" .Distance}} km from the epicenter" +
+				"\nLocation: {{.Lintang}}, {{.Bujur}} ({{.Wilayah}})" +
+				"\nDepth: {{.Kedalaman}}" +
+				"\nTime: {{.Tanggal}} {{.Jam}}" +
+				"\nBe alert for aftershocks."},
+			{Key: messages.KeyAlert, Tier: "severe", Language: messages.LanguageIndonesian, Body: "BAHAYA Gempa M{{.Magnitude}}" +
+				"\nGuncangan sangat kuat diperkirakan di lokasi Anda: MMI {{.MMIRoman}}, {{printf \"%.0f\" .Distance}} km dari episenter" +
+				"\nSegera lindungi diri dan jauhi bangunan." +
+				"\nLokasi: {{.Lintang}}, {{.Bujur}} ({{.Wilayah}})" +
+				"\nKedalaman: {{.Kedalaman}}" +
+				"\nWaktu: {{.Tanggal}} {{.Jam}}"},
+			{Key: messages.KeyAlert, Tier: "severe", Language: messages.LanguageEnglish, Body: "DANGER Earthquake M{{.Magnitude}}" +
+				"\nVery strong shaking expected at your location: MMI {{.MMIRoman}}, {{printf \"%.0f\" .Distance}} km from the epicenter" +
+				"\nProtect yourself now and stay away from buildings." +
+				"\nLocation: {{.Lintang}}, {{.Bujur}} ({{.Wilayah}})" +
+				"\nDepth: {{.Kedalaman}}" +
+				"\nTime: {{.Tanggal}} {{.Jam}}"},
+			{Key: messages.KeyAlert, Language: messages.LanguageIndonesian, Body: "Gempa M{{.Magnitude}} ({{.Tier}})" +
+				"\nLokasi: {{.Lintang}}, {{.Bujur}} ({{.Wilayah}})" +
+				"\nKedalaman: {{.Kedalaman}}" +
+				"\nWaktu: {{.Tanggal}} {{.Jam}}" +
+				"\nPerkiraan di lokasi Anda: MMI {{.MMIRoman}}, {{printf \"%.0f\" .Distance}} km dari episenter"},
+			{Key: messages.KeyAlert, Language: messages.LanguageEnglish, Body: "Earthquake M{{.Magnitude}} ({{.Tier}})" +
+				"\nLocation: {{.Lintang}}, {{.Bujur}} ({{.Wilayah}})" +
+				"\nDepth: {{.Kedalaman}}" +
+				"\nTime: {{.Tanggal}} {{.Jam}}" +
+				"\nEstimate at your location: MMI {{.MMIRoman}}, {{printf \"%.0f\" .Distance}} km from the epicenter"},
+			{Key: messages.KeyCorrection, Language: messages.LanguageIndonesian, Body: "[KOREKSI] Magnitudo: {{.PreviousMagnitude}} -> {{.Magnitude}}, Kedalaman: {{.PreviousKedalaman}} -> {{.Kedalaman}}"},
+			{Key: messages.KeyCorrection, Language: messages.LanguageEnglish, Body: "[CORRECTION] Magnitude: {{.PreviousMagnitude}} -> {{.Magnitude}}, Depth: {{.PreviousKedalaman}} -> {{.Kedalaman}}"},
+			{Key: messages.KeyPlaces, Language: messages.LanguageIndonesian, Body: "Lokasi tersimpan:{{range .Places}}" +
+				"\n{{.Label}}: MMI {{.MMIRoman}}, {{printf \"%.0f\" .Distance}} km{{end}}"},
+			{Key: messages.KeyPlaces, Language: messages.LanguageEnglish, Body: "Saved places:{{range .Places}}" +
+				"\n{{.Label}}: MMI {{.MMIRoman}}, {{printf \"%.0f\" .Distance}} km{{end}}"},
+			{Key: messages.KeyBotWelcome, Language: messages.LanguageIndonesian, Body: "Selamat datang di bot ini!" +
+				"\n" +
+				"\nAnda dapat mengirimkan lokasi dengan menekan tombol 'Kirim Lokasi'."},
+			{Key: messages.KeyBotWelcome, Language: messages.LanguageEnglish, Body: "Welcome to this bot!" +
+				"\n" +
+				"\nYou can send your location with the 'Send Location' button."},
+			{Key: messages.KeyBotMenu, Language: messages.LanguageIndonesian, Body: "Silakan kirim lokasi Anda atau pilih opsi lainnya:"},
+			{Key: messages.KeyBotMenu, Language: messages.LanguageEnglish, Body: "Please send your location or choose another option:"},
+			{Key: messages.KeyBotButtonLocation, Language: messages.LanguageIndonesian, Body: "Kirim Lokasi"},
+			{Key: messages.KeyBotButtonLocation, Language: messages.LanguageEnglish, Body: "Send Location"},
+			{Key: messages.KeyBotButtonHelp, Language: messages.LanguageIndonesian, Body: "Bantuan"},
+			{Key: messages.KeyBotButtonHelp, Language: messages.LanguageEnglish, Body: "Help"},
+			{Key: messages.KeyBotError, Language: messages.LanguageIndonesian, Body: "Terjadi kesalahan. Silakan coba lagi nanti."},
+			{Key: messages.KeyBotError, Language: messages.LanguageEnglish, Body: "Something went wrong. Please try again later."},
+			{Key: messages.KeyBotNoSubscriber, Language: messages.LanguageIndonesian, Body: "Kirim lokasi Anda terlebih dahulu dengan tombol 'Kirim Lokasi'."},
+			{Key: messages.KeyBotNoSubscriber, Language: messages.LanguageEnglish, Body: "Send your location first with the 'Send Location' button."},
+			{Key: messages.KeyBotLocationAsk, Language: messages.LanguageIndonesian, Body: "Beri nama lokasi ini. Pilih salah satu atau ketik nama sendiri:"},
+			{Key: messages.KeyBotLocationAsk, Language: messages.LanguageEnglish, Body: "Name this location. Pick one or type your own name:"},
+			{Key: messages.KeyBotLocationLabels, Language: messages.LanguageIndonesian, Body: "Rumah" +
+				"\nKantor" +
+				"\nRumah Orang Tua"},
+			{Key: messages.KeyBotLocationLabels, Language: messages.LanguageEnglish, Body: "Home" +
+				"\nOffice" +
+				"\nParents' Home"},
+			{Key: messages.KeyBotLocationSaved, Language: messages.LanguageIndonesian, Body: "Lokasi \"{{.Label}}\" telah disimpan! Anda akan menerima notifikasi gempa bumi yang terasa di lokasi tersebut."},
+			{Key: messages.KeyBotLocationSaved, Language: messages.LanguageEnglish, Body: "Location \"{{.Label}}\" saved! You will be notified of earthquakes felt at this location."},
+			{Key: messages.KeyBotLocationLimit, Language: messages.LanguageIndonesian, Body: "Anda sudah menyimpan {{.Max}} lokasi. Hapus salah satu dengan /hapuslokasi terlebih dahulu."},
+			{Key: messages.KeyBotLocationLimit, Language: messages.LanguageEnglish, Body: "You already saved {{.Max}} locations. Remove one with /hapuslokasi first."},
+			{Key: messages.KeyBotLocationInvalid, Language: messages.LanguageIndonesian, Body: "Nama lokasi maksimal 40 karakter. Silakan kirim lokasi Anda lagi."},
+			{Key: messages.KeyBotLocationInvalid, Language: messages.LanguageEnglish, Body: "A location name has at most 40 characters. Please send your location again."},
+			{Key: messages.KeyBotLocationUsage, Language: messages.LanguageIndonesian, Body: "Tulis nama lokasi, misalnya /hapuslokasi Kantor"},
+			{Key: messages.KeyBotLocationUsage, Language: messages.LanguageEnglish, Body: "Write the location name, e.g. /hapuslokasi Office"},
+			{Key: messages.KeyBotLocationNotFound, Language: messages.LanguageIndonesian, Body: "Lokasi \"{{.Label}}\" tidak ditemukan."},
+			{Key: messages.KeyBotLocationNotFound, Language: messages.LanguageEnglish, Body: "Location \"{{.Label}}\" not found."},
+			{Key: messages.KeyBotLocations, Language: messages.LanguageIndonesian, Body: "{{if .Locations}}Lokasi tersimpan:{{range .Locations}}" +
+				"\n- {{.Label}} ({{.Lintang}}, {{.Bujur}}){{end}}{{else}}Belum ada lokasi tersimpan. Kirim lokasi dengan tombol 'Kirim Lokasi'.{{end}}"},
+			{Key: messages.KeyBotLocations, Language: messages.LanguageEnglish, Body: "{{if .Locations}}Saved locations:{{range .Locations}}" +
+				"\n- {{.Label}} ({{.Lintang}}, {{.Bujur}}){{end}}{{else}}No saved locations yet. Send one with the 'Send Location' button.{{end}}"},
+			{Key: messages.KeyBotLocationsHelp, Language: messages.LanguageIndonesian, Body: "Lokasi tersimpan (maksimal {{.Max}}):" +
+				"\n/lokasi - lihat lokasi tersimpan" +
+				"\n/hapuslokasi Kantor - hapus lokasi dengan nama tersebut"},
+			{Key: messages.KeyBotLocationsHelp, Language: messages.LanguageEnglish, Body: "Saved locations (at most {{.Max}}):" +
+				"\n/lokasi - show saved locations" +
+				"\n/hapuslokasi Office - remove the location with that name"},
+			{Key: messages.KeyBotPreferences, Language: messages.LanguageIndonesian, Body: "Pengaturan notifikasi Anda:" +
+				"\nMagnitudo: {{if gt .MinMagnitude 0.0}}M{{printf \"%.1f\" .MinMagnitude}} ke atas{{else}}semua{{end}}" +
+				"\nGuncangan di lokasi: {{if gt .MinMMI 0.0}}MMI {{printf \"%.1f\" .MinMMI}} ke atas{{else}}semua{{end}}" +
+				"\nJam tenang: {{if .QuietStart}}{{.QuietStart}}-{{.QuietEnd}} (peringatan bahaya tetap dikirim){{else}}tidak aktif{{end}}" +
+				"\nZona waktu: {{.Timezone}}" +
+				"\nBahasa: {{.Language}}"},
+			{Key: messages.KeyBotPreferences, Language: messages.LanguageEnglish, Body: "Your notification settings:" +
+				"\nMagnitude: {{if gt .MinMagnitude 0.0}}M{{printf \"%.1f\" .MinMagnitude}} and above{{else}}all{{end}}" +
+				"\nShaking at your location: {{if gt .MinMMI 0.0}}MMI {{printf \"%.1f\" .MinMMI}} and above{{else}}all{{end}}" +
+				"\nQuiet hours: {{if .QuietStart}}{{.QuietStart}}-{{.QuietEnd}} (danger alerts are still sent){{else}}off{{end}}" +
+				"\nTime zone: {{.Timezone}}" +
+				"\nLanguage: {{.Language}}"},
+			{Key: messages.KeyBotPreferencesHelp, Language: messages.LanguageIndonesian, Body: "Atur notifikasi Anda:" +
+				"\n/preferensi - lihat pengaturan" +
+				"\n/minmag 5 - hanya gempa M5 ke atas (off untuk semua)" +
+				"\n/minmmi 4 - hanya guncangan MMI IV ke atas di lokasi Anda" +
+				"\n/sunyi 22:00-06:00 - jam tenang, kecuali peringatan bahaya (off untuk mematikan)" +
+				"\n/zonawaktu WITA - zona waktu jam tenang (WIB, WITA, WIT)" +
+				"\n/bahasa en - bahasa pesan (id, en, jv, su)"},
+			{Key: messages.KeyBotPreferencesHelp, Language: messages.LanguageEnglish, Body: "Manage your notifications:" +
+				"\n/preferensi - show settings" +
+				"\n/minmag 5 - only M5 and above (off for all)" +
+				"\n/minmmi 4 - only shaking of MMI IV and above at your location" +
+				"\n/sunyi 22:00-06:00 - quiet hours, except danger alerts (off to disable)" +
+				"\n/zonawaktu WITA - time zone of the quiet hours (WIB, WITA, WIT)" +
+				"\n/bahasa id - message language (id, en, jv, su)"},
+			{Key: messages.KeyBotPreferencesError, Language: messages.LanguageIndonesian, Body: "Pengaturan tidak valid: {{.Error}}"},
+			{Key: messages.KeyBotPreferencesError, Language: messages.LanguageEnglish, Body: "Invalid setting: {{.Error}}"},
+			{Key: messages.KeyBotUsageNumber, Language: messages.LanguageIndonesian, Body: "Tulis angka, misalnya /{{.Command}} 5"},
+			{Key: messages.KeyBotUsageNumber, Language: messages.LanguageEnglish, Body: "Write a number, e.g. /{{.Command}} 5"},
+			{Key: messages.KeyBotUsageQuiet, Language: messages.LanguageIndonesian, Body: "Tulis jam tenang, misalnya /sunyi 22:00-06:00"},
+			{Key: messages.KeyBotUsageQuiet, Language: messages.LanguageEnglish, Body: "Write the quiet hours, e.g. /sunyi 22:00-06:00"},
+			{Key: messages.KeyBotUsageTimezone, Language: messages.LanguageIndonesian, Body: "Tulis zona waktu, misalnya /zonawaktu WITA"},
+			{Key: messages.KeyBotUsageTimezone, Language: messages.LanguageEnglish, Body: "Write a time zone, e.g. /zonawaktu WITA"},
+			{Key: messages.KeyBotUsageLanguage, Language: messages.LanguageIndonesian, Body: "Tulis kode bahasa: id, en, jv atau su, misalnya /bahasa jv"},
+			{Key: messages.KeyBotUsageLanguage, Language: messages.LanguageEnglish, Body: "Write a language code: id, en, jv or su, e.g. /bahasa en"},
+		} {
+			record := core.NewRecord(collection)
+			record.Set("key", item.Key)
+			record.Set("channel", item.Channel)
+			record.Set("tier", item.Tier)
+			record.Set("language", item.Language)
+			record.Set("body", item.Body)
+			if err := app.Save(record); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}, func(app core.App) error {
+		_, err := app.DB().Delete("message_template", dbx.HashExp{}).Execute()
+		return err
+	})
+}
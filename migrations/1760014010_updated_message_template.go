@@ -0,0 +1,121 @@
+package migrations
+
+import (
+	"bmkg/src/messages"
+
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		// bot commands; /radius in the preferences
+		templates := []messages.Template{
+			{Key: messages.KeyBotPreferences, Language: messages.LanguageIndonesian, Body: "Pengaturan notifikasi Anda:" +
+				"\nMagnitudo: {{if gt .MinMagnitude 0.0}}M{{printf \"%.1f\" .MinMagnitude}} ke atas{{else}}semua{{end}}" +
+				"\nGuncangan di lokasi: {{if gt .MinMMI 0.0}}MMI {{printf \"%.1f\" .MinMMI}} ke atas{{else}}semua{{end}}" +
+				"\nRadius: {{if gt .RadiusKm 0.0}}{{printf \"%.0f\" .RadiusKm}} km dari lokasi tersimpan{{else}}semua jarak{{end}}" +
+				"\nJam tenang: {{if .QuietStart}}{{.QuietStart}}-{{.QuietEnd}} (peringatan bahaya tetap dikirim){{else}}tidak aktif{{end}}" +
+				"\nZona waktu: {{.Timezone}}" +
+				"\nBahasa: {{.Language}}"},
+			{Key: messages.KeyBotPreferences, Language: messages.LanguageEnglish, Body: "Your notification settings:" +
+				"\nMagnitude: {{if gt .MinMagnitude 0.0}}M{{printf \"%.1f\" .MinMagnitude}} and above{{else}}all{{end}}" +
+				"\nShaking at your location: {{if gt .MinMMI 0.0}}MMI {{printf \"%.1f\" .MinMMI}} and above{{else}}all{{end}}" +
+				"\nRadius: {{if gt .RadiusKm 0.0}}{{printf \"%.0f\" .RadiusKm}} km from your saved locations{{else}}any distance{{end}}" +
+				"\nQuiet hours: {{if .QuietStart}}{{.QuietStart}}-{{.QuietEnd}} (danger alerts are still sent){{else}}off{{end}}" +
+				"\nTime zone: {{.Timezone}}" +
+				"\nLanguage: {{.Language}}"},
+			{Key: messages.KeyBotPreferencesHelp, Language: messages.LanguageIndonesian, Body: "Atur notifikasi Anda:" +
+				"\n/preferensi - lihat pengaturan" +
+				"\n/minmag 5 - hanya gempa M5 ke atas (off untuk semua)" +
+				"\n/minmmi 4 - hanya guncangan MMI IV ke atas di lokasi Anda" +
+				"\n/radius 100 - hanya gempa dalam 100 km dari lokasi Anda (off untuk semua)" +
+				"\n/sunyi 22:00-06:00 - jam tenang, kecuali peringatan bahaya (off untuk mematikan)" +
+				"\n/zonawaktu WITA - zona waktu jam tenang (WIB, WITA, WIT)" +
+				"\n/bahasa en - bahasa pesan (id, en, jv, su)"},
+			{Key: messages.KeyBotPreferencesHelp, Language: messages.LanguageEnglish, Body: "Manage your notifications:" +
+				"\n/preferensi - show settings" +
+				"\n/minmag 5 - only M5 and above (off for all)" +
+				"\n/minmmi 4 - only shaking of MMI IV and above at your location" +
+				"\n/radius 100 - only quakes within 100 km of your locations (off for all)" +
+				"\n/sunyi 22:00-06:00 - quiet hours, except danger alerts (off to disable)" +
+				"\n/zonawaktu WITA - time zone of the quiet hours (WIB, WITA, WIT)" +
+				"\n/bahasa id - message language (id, en, jv, su)"},
+			{Key: messages.KeyBotCommands, Language: messages.LanguageIndonesian, Body: "start - Mulai dan tampilkan bantuan" +
+				"\nlokasi - Lihat lokasi tersimpan" +
+				"\nstatus - Lihat lokasi dan pengaturan" +
+				"\nterkini - Gempa terkini" +
+				"\nhariini - Gempa hari ini" +
+				"\nradius - Batasi jarak gempa, misalnya /radius 100" +
+				"\nbahasa - Ganti bahasa pesan (id, en, jv, su)" +
+				"\npreferensi - Lihat dan atur notifikasi" +
+				"\nhapuslokasi - Hapus lokasi tersimpan" +
+				"\nhapus - Berhenti berlangganan dan hapus data"},
+			{Key: messages.KeyBotCommands, Language: messages.LanguageEnglish, Body: "start - Start and show help" +
+				"\nlokasi - Show saved locations" +
+				"\nstatus - Show locations and settings" +
+				"\nterkini - Latest earthquake" +
+				"\nhariini - Today's earthquakes" +
+				"\nradius - Limit the quake distance, e.g. /radius 100" +
+				"\nbahasa - Change the message language (id, en, jv, su)" +
+				"\npreferensi - Show and manage notifications" +
+				"\nhapuslokasi - Remove a saved location" +
+				"\nhapus - Unsubscribe and delete your data"},
+			{Key: messages.KeyBotUnsubscribeAsk, Language: messages.LanguageIndonesian, Body: "Semua lokasi dan pengaturan Anda akan dihapus dan Anda tidak lagi menerima notifikasi gempa. Kirim /hapus ya untuk melanjutkan."},
+			{Key: messages.KeyBotUnsubscribeAsk, Language: messages.LanguageEnglish, Body: "All your locations and settings will be deleted and you will no longer receive earthquake notifications. Send /hapus ya to continue."},
+			{Key: messages.KeyBotUnsubscribed, Language: messages.LanguageIndonesian, Body: "Data Anda telah dihapus. Kirim lokasi kapan saja untuk berlangganan lagi."},
+			{Key: messages.KeyBotUnsubscribed, Language: messages.LanguageEnglish, Body: "Your data has been deleted. Send a location at any time to subscribe again."},
+			{Key: messages.KeyBotLatest, Language: messages.LanguageIndonesian, Body: "Gempa terkini M{{.Magnitude}}" +
+				"\nWaktu: {{.Tanggal}} {{.Jam}}" +
+				"\nKoordinat: {{.Coordinates}}" +
+				"\nKedalaman: {{.Kedalaman}}" +
+				"\nWilayah: {{.Wilayah}}"},
+			{Key: messages.KeyBotLatest, Language: messages.LanguageEnglish, Body: "Latest earthquake M{{.Magnitude}}" +
+				"\nTime: {{.Tanggal}} {{.Jam}}" +
+				"\nCoordinates: {{.Coordinates}}" +
+				"\nDepth: {{.Kedalaman}}" +
+				"\nRegion: {{.Wilayah}}"},
+			{Key: messages.KeyBotToday, Language: messages.LanguageIndonesian, Body: "{{if .Gempa}}Gempa hari ini ({{len .Gempa}}):{{range .Gempa}}" +
+				"\n- {{.Jam}} M{{.Magnitude}}, {{.Kedalaman}}, {{.Wilayah}}{{end}}{{else}}Belum ada gempa tercatat hari ini.{{end}}"},
+			{Key: messages.KeyBotToday, Language: messages.LanguageEnglish, Body: "{{if .Gempa}}Earthquakes today ({{len .Gempa}}):{{range .Gempa}}" +
+				"\n- {{.Jam}} M{{.Magnitude}}, {{.Kedalaman}}, {{.Wilayah}}{{end}}{{else}}No earthquakes recorded today yet.{{end}}"},
+			{Key: messages.KeyBotNoEarthquake, Language: messages.LanguageIndonesian, Body: "Belum ada data gempa."},
+			{Key: messages.KeyBotNoEarthquake, Language: messages.LanguageEnglish, Body: "No earthquake data yet."},
+		}
+
+		// the wording seeded before, replaced unless an admin edited it
+		replaced := []messages.Template{
+			{Key: messages.KeyBotPreferences, Language: messages.LanguageIndonesian, Body: "Pengaturan notifikasi Anda:" +
+				"\nMagnitudo: {{if gt .MinMagnitude 0.0}}M{{printf \"%.1f\" .MinMagnitude}} ke atas{{else}}semua{{end}}" +
+				"\nGuncangan di lokasi: {{if gt .MinMMI 0.0}}MMI {{printf \"%.1f\" .MinMMI}} ke atas{{else}}semua{{end}}" +
+				"\nJam tenang: {{if .QuietStart}}{{.QuietStart}}-{{.QuietEnd}} (peringatan bahaya tetap dikirim){{else}}tidak aktif{{end}}" +
+				"\nZona waktu: {{.Timezone}}" +
+				"\nBahasa: {{.Language}}"},
+			{Key: messages.KeyBotPreferences, Language: messages.LanguageEnglish, Body: "Your notification settings:" +
+				"\nMagnitude: {{if gt .MinMagnitude 0.0}}M{{printf \"%.1f\" .MinMagnitude}} and above{{else}}all{{end}}" +
+				"\nShaking at your location: {{if gt .MinMMI 0.0}}MMI {{printf \"%.1f\" .MinMMI}} and above{{else}}all{{end}}" +
+				"\nQuiet hours: {{if .QuietStart}}{{.QuietStart}}-{{.QuietEnd}} (danger alerts are still sent){{else}}off{{end}}" +
+				"\nTime zone: {{.Timezone}}" +
+				"\nLanguage: {{.Language}}"},
+			{Key: messages.KeyBotPreferencesHelp, Language: messages.LanguageIndonesian, Body: "Atur notifikasi Anda:\n" +
+				"/preferensi - lihat pengaturan\n" +
+				"/minmag 5 - hanya gempa M5 ke atas (off untuk semua)\n" +
+				"/minmmi 4 - hanya guncangan MMI IV ke atas di lokasi Anda\n" +
+				"/sunyi 22:00-06:00 - jam tenang, kecuali peringatan bahaya (off untuk mematikan)\n" +
+				"/zonawaktu WITA - zona waktu jam tenang (WIB, WITA, WIT)\n" +
+				"/bahasa en - bahasa pesan (id, en, jv, su)"},
+			{Key: messages.KeyBotPreferencesHelp, Language: messages.LanguageEnglish, Body: "Manage your notifications:\n" +
+				"/preferensi - show settings\n" +
+				"/minmag 5 - only M5 and above (off for all)\n" +
+				"/minmmi 4 - only shaking of MMI IV and above at your location\n" +
+				"/sunyi 22:00-06:00 - quiet hours, except danger alerts (off to disable)\n" +
+				"/zonawaktu WITA - time zone of the quiet hours (WIB, WITA, WIT)\n" +
+				"/bahasa id - message language (id, en, jv, su)"},
+		}
+
+		return upgradeMessageTemplates(app, templates, replaced)
+	}, func(app core.App) error {
+		// the previous wording is not restored
+		return nil
+	})
+}
@@ -0,0 +1,47 @@
+package migrations
+
+import (
+	"bmkg/src/messages"
+
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		// buttons and replies of the Telegram alert actions
+		templates := []messages.Template{
+			{Key: messages.KeyBotEpicenter, Language: messages.LanguageIndonesian, Body: "Episenter gempa"},
+			{Key: messages.KeyBotEpicenter, Language: messages.LanguageEnglish, Body: "Earthquake epicenter"},
+			{Key: messages.KeyBotButtonSafe, Language: messages.LanguageIndonesian, Body: "Saya aman"},
+			{Key: messages.KeyBotButtonSafe, Language: messages.LanguageEnglish, Body: "I'm safe"},
+			{Key: messages.KeyBotButtonFelt, Language: messages.LanguageIndonesian, Body: "Terasa?"},
+			{Key: messages.KeyBotButtonFelt, Language: messages.LanguageEnglish, Body: "Felt it?"},
+			{Key: messages.KeyBotButtonDetail, Language: messages.LanguageIndonesian, Body: "Detail"},
+			{Key: messages.KeyBotButtonDetail, Language: messages.LanguageEnglish, Body: "Details"},
+			{Key: messages.KeyBotSafeThanks, Language: messages.LanguageIndonesian, Body: "Terima kasih, semoga Anda tetap aman."},
+			{Key: messages.KeyBotSafeThanks, Language: messages.LanguageEnglish, Body: "Thank you, stay safe."},
+			{Key: messages.KeyBotFeltThanks, Language: messages.LanguageIndonesian, Body: "Terima kasih atas laporan Anda."},
+			{Key: messages.KeyBotFeltThanks, Language: messages.LanguageEnglish, Body: "Thank you for your report."},
+			{Key: messages.KeyBotDetail, Language: messages.LanguageIndonesian, Body: "Detail gempa M{{.Magnitude}}" +
+				"\nWaktu: {{.Tanggal}} {{.Jam}}" +
+				"\nKoordinat: {{.Coordinates}}" +
+				"\nKedalaman: {{.Kedalaman}}" +
+				"\nWilayah: {{.Wilayah}}{{if .Potensi}}" +
+				"\nPotensi: {{.Potensi}}{{end}}{{if .Dirasakan}}" +
+				"\nDirasakan: {{.Dirasakan}}{{end}}"},
+			{Key: messages.KeyBotDetail, Language: messages.LanguageEnglish, Body: "Earthquake details M{{.Magnitude}}" +
+				"\nTime: {{.Tanggal}} {{.Jam}}" +
+				"\nCoordinates: {{.Coordinates}}" +
+				"\nDepth: {{.Kedalaman}}" +
+				"\nRegion: {{.Wilayah}}{{if .Potensi}}" +
+				"\nPotential: {{.Potensi}}{{end}}{{if .Dirasakan}}" +
+				"\nFelt: {{.Dirasakan}}{{end}}"},
+		}
+
+		return upgradeMessageTemplates(app, templates, nil)
+	}, func(app core.App) error {
+		// the added templates are kept
+		return nil
+	})
+}
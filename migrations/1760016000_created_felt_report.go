@@ -0,0 +1,196 @@
+package migrations
+
+import (
+	"encoding/json"
+
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		jsonData := `{
+			"createRule": null,
+			"deleteRule": null,
+			"fields": [
+				{
+					"autogeneratePattern": "[a-z0-9]{15}",
+					"hidden": false,
+					"id": "text3208210256",
+					"max": 15,
+					"min": 15,
+					"name": "id",
+					"pattern": "^[a-z0-9]+$",
+					"presentable": false,
+					"primaryKey": true,
+					"required": true,
+					"system": true,
+					"type": "text"
+				},
+				{
+					"cascadeDelete": true,
+					"collectionId": "pbc_428798634",
+					"hidden": false,
+					"id": "relation3879649850",
+					"maxSelect": 1,
+					"minSelect": 0,
+					"name": "earthquake",
+					"presentable": false,
+					"required": true,
+					"system": false,
+					"type": "relation"
+				},
+				{
+					"cascadeDelete": true,
+					"collectionId": "pbc_3034109313",
+					"hidden": false,
+					"id": "relation2902481769",
+					"maxSelect": 1,
+					"minSelect": 0,
+					"name": "subscriber",
+					"presentable": false,
+					"required": true,
+					"system": false,
+					"type": "relation"
+				},
+				{
+					"hidden": false,
+					"id": "bool4013289350",
+					"name": "felt",
+					"presentable": false,
+					"required": false,
+					"system": false,
+					"type": "bool"
+				},
+				{
+					"hidden": false,
+					"id": "select2207315069",
+					"maxSelect": 1,
+					"name": "shaking",
+					"presentable": false,
+					"required": false,
+					"system": false,
+					"type": "select",
+					"values": [
+						"weak",
+						"moderate",
+						"strong",
+						"violent"
+					]
+				},
+				{
+					"hidden": false,
+					"id": "select3059782130",
+					"maxSelect": 1,
+					"name": "effect",
+					"presentable": false,
+					"required": false,
+					"system": false,
+					"type": "select",
+					"values": [
+						"none",
+						"swinging",
+						"falling",
+						"unsteady",
+						"damage"
+					]
+				},
+				{
+					"hidden": false,
+					"id": "number1534283127",
+					"max": 12,
+					"min": 1,
+					"name": "mmi",
+					"onlyInt": false,
+					"presentable": false,
+					"required": true,
+					"system": false,
+					"type": "number"
+				},
+				{
+					"hidden": false,
+					"id": "number2499937429",
+					"max": 90,
+					"min": -90,
+					"name": "lat",
+					"onlyInt": false,
+					"presentable": false,
+					"required": false,
+					"system": false,
+					"type": "number"
+				},
+				{
+					"hidden": false,
+					"id": "number4142125153",
+					"max": 180,
+					"min": -180,
+					"name": "lon",
+					"onlyInt": false,
+					"presentable": false,
+					"required": false,
+					"system": false,
+					"type": "number"
+				},
+				{
+					"autogeneratePattern": "",
+					"hidden": false,
+					"id": "text1624927940",
+					"max": 12,
+					"min": 0,
+					"name": "geohash",
+					"pattern": "",
+					"presentable": false,
+					"primaryKey": false,
+					"required": true,
+					"system": false,
+					"type": "text"
+				},
+				{
+					"hidden": false,
+					"id": "autodate2990389176",
+					"name": "created",
+					"onCreate": true,
+					"onUpdate": false,
+					"presentable": false,
+					"system": false,
+					"type": "autodate"
+				},
+				{
+					"hidden": false,
+					"id": "autodate3332085495",
+					"name": "updated",
+					"onCreate": true,
+					"onUpdate": true,
+					"presentable": false,
+					"system": false,
+					"type": "autodate"
+				}
+			],
+			"id": "pbc_652518260",
+			"indexes": [
+				"CREATE UNIQUE INDEX idx_felt_report_reporter ON felt_report (earthquake, subscriber)",
+				"CREATE INDEX idx_felt_report_updated ON felt_report (updated)"
+			],
+			"listRule": null,
+			"name": "felt_report",
+			"system": false,
+			"type": "base",
+			"updateRule": null,
+			"viewRule": null
+		}`
+
+		collection := &core.Collection{}
+		if err := json.Unmarshal([]byte(jsonData), &collection); err != nil {
+			return err
+		}
+
+		return app.Save(collection)
+	}, func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("pbc_652518260")
+		if err != nil {
+			return err
+		}
+
+		return app.Delete(collection)
+	})
+}
@@ -0,0 +1,57 @@
+package migrations
+
+import (
+	"bmkg/src/messages"
+
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		// "did you feel it?" questionnaire; the thanks shows the reported intensity
+		templates := []messages.Template{
+			{Key: messages.KeyBotFeltThanks, Language: messages.LanguageIndonesian, Body: "Terima kasih atas laporan Anda dari {{.Label}} (MMI {{.MMIRoman}})."},
+			{Key: messages.KeyBotFeltThanks, Language: messages.LanguageEnglish, Body: "Thank you for your report from {{.Label}} (MMI {{.MMIRoman}})."},
+			{Key: messages.KeyBotFeltAsk, Language: messages.LanguageIndonesian, Body: "Apakah Anda merasakan gempa ini?"},
+			{Key: messages.KeyBotFeltAsk, Language: messages.LanguageEnglish, Body: "Did you feel this earthquake?"},
+			{Key: messages.KeyBotFeltAnswers, Language: messages.LanguageIndonesian, Body: "Ya, terasa" +
+				"\nTidak terasa"},
+			{Key: messages.KeyBotFeltAnswers, Language: messages.LanguageEnglish, Body: "Yes, I felt it" +
+				"\nNo, I did not"},
+			{Key: messages.KeyBotFeltShaking, Language: messages.LanguageIndonesian, Body: "Seberapa kuat guncangannya?"},
+			{Key: messages.KeyBotFeltShaking, Language: messages.LanguageEnglish, Body: "How strong was the shaking?"},
+			{Key: messages.KeyBotFeltShakingOptions, Language: messages.LanguageIndonesian, Body: "Lemah" +
+				"\nSedang" +
+				"\nKuat" +
+				"\nSangat kuat"},
+			{Key: messages.KeyBotFeltShakingOptions, Language: messages.LanguageEnglish, Body: "Weak" +
+				"\nModerate" +
+				"\nStrong" +
+				"\nViolent"},
+			{Key: messages.KeyBotFeltEffect, Language: messages.LanguageIndonesian, Body: "Apa yang terjadi di sekitar Anda?"},
+			{Key: messages.KeyBotFeltEffect, Language: messages.LanguageEnglish, Body: "What happened around you?"},
+			{Key: messages.KeyBotFeltEffectOptions, Language: messages.LanguageIndonesian, Body: "Tidak ada" +
+				"\nBenda tergantung bergoyang" +
+				"\nBenda jatuh atau pecah" +
+				"\nSulit berdiri atau berjalan" +
+				"\nBangunan retak atau rusak"},
+			{Key: messages.KeyBotFeltEffectOptions, Language: messages.LanguageEnglish, Body: "Nothing" +
+				"\nHanging objects swung" +
+				"\nObjects fell or broke" +
+				"\nHard to stand or walk" +
+				"\nBuildings cracked or damaged"},
+		}
+
+		// the wording seeded before, replaced unless an admin edited it
+		replaced := []messages.Template{
+			{Key: messages.KeyBotFeltThanks, Language: messages.LanguageIndonesian, Body: "Terima kasih atas laporan Anda."},
+			{Key: messages.KeyBotFeltThanks, Language: messages.LanguageEnglish, Body: "Thank you for your report."},
+		}
+
+		return upgradeMessageTemplates(app, templates, replaced)
+	}, func(app core.App) error {
+		// the previous wording is not restored
+		return nil
+	})
+}
@@ -0,0 +1,180 @@
+package migrations
+
+import (
+	"encoding/json"
+
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		jsonData := `{
+			"createRule": null,
+			"deleteRule": null,
+			"fields": [
+				{
+					"autogeneratePattern": "[a-z0-9]{15}",
+					"hidden": false,
+					"id": "text3208210256",
+					"max": 15,
+					"min": 15,
+					"name": "id",
+					"pattern": "^[a-z0-9]+$",
+					"presentable": false,
+					"primaryKey": true,
+					"required": true,
+					"system": true,
+					"type": "text"
+				},
+				{
+					"cascadeDelete": true,
+					"collectionId": "pbc_428798634",
+					"hidden": false,
+					"id": "relation3879649850",
+					"maxSelect": 1,
+					"minSelect": 0,
+					"name": "earthquake",
+					"presentable": false,
+					"required": true,
+					"system": false,
+					"type": "relation"
+				},
+				{
+					"cascadeDelete": true,
+					"collectionId": "pbc_3034109313",
+					"hidden": false,
+					"id": "relation2902481769",
+					"maxSelect": 1,
+					"minSelect": 0,
+					"name": "subscriber",
+					"presentable": false,
+					"required": true,
+					"system": false,
+					"type": "relation"
+				},
+				{
+					"hidden": false,
+					"id": "select2063623452",
+					"maxSelect": 1,
+					"name": "status",
+					"presentable": false,
+					"required": true,
+					"system": false,
+					"type": "select",
+					"values": [
+						"pending",
+						"safe",
+						"need_help"
+					]
+				},
+				{
+					"autogeneratePattern": "",
+					"hidden": true,
+					"id": "text1597481275",
+					"max": 64,
+					"min": 0,
+					"name": "token",
+					"pattern": "",
+					"presentable": false,
+					"primaryKey": false,
+					"required": true,
+					"system": false,
+					"type": "text"
+				},
+				{
+					"hidden": false,
+					"id": "number1534283127",
+					"max": 12,
+					"min": 0,
+					"name": "mmi",
+					"onlyInt": false,
+					"presentable": false,
+					"required": false,
+					"system": false,
+					"type": "number"
+				},
+				{
+					"hidden": false,
+					"id": "date2535835892",
+					"max": "",
+					"min": "",
+					"name": "responded_at",
+					"presentable": false,
+					"required": false,
+					"system": false,
+					"type": "date"
+				},
+				{
+					"hidden": false,
+					"id": "number1838332372",
+					"max": null,
+					"min": 0,
+					"name": "reminders",
+					"onlyInt": true,
+					"presentable": false,
+					"required": false,
+					"system": false,
+					"type": "number"
+				},
+				{
+					"hidden": false,
+					"id": "date2040212570",
+					"max": "",
+					"min": "",
+					"name": "reminded_at",
+					"presentable": false,
+					"required": false,
+					"system": false,
+					"type": "date"
+				},
+				{
+					"hidden": false,
+					"id": "autodate2990389176",
+					"name": "created",
+					"onCreate": true,
+					"onUpdate": false,
+					"presentable": false,
+					"system": false,
+					"type": "autodate"
+				},
+				{
+					"hidden": false,
+					"id": "autodate3332085495",
+					"name": "updated",
+					"onCreate": true,
+					"onUpdate": true,
+					"presentable": false,
+					"system": false,
+					"type": "autodate"
+				}
+			],
+			"id": "pbc_108796014",
+			"indexes": [
+				"CREATE UNIQUE INDEX idx_safety_checkin_subscriber ON safety_checkin (earthquake, subscriber)",
+				"CREATE UNIQUE INDEX idx_safety_checkin_token ON safety_checkin (token)",
+				"CREATE INDEX idx_safety_checkin_status ON safety_checkin (status, created)"
+			],
+			"listRule": "subscriber.safety_group_member_via_subscriber.safety_group.owner ?= @request.auth.id",
+			"name": "safety_checkin",
+			"system": false,
+			"type": "base",
+			"updateRule": null,
+			"viewRule": "subscriber.safety_group_member_via_subscriber.safety_group.owner ?= @request.auth.id"
+		}`
+
+		collection := &core.Collection{}
+		if err := json.Unmarshal([]byte(jsonData), &collection); err != nil {
+			return err
+		}
+
+		return app.Save(collection)
+	}, func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("pbc_108796014")
+		if err != nil {
+			return err
+		}
+
+		return app.Delete(collection)
+	})
+}
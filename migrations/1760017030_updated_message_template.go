@@ -0,0 +1,93 @@
+package migrations
+
+import (
+	"bmkg/src/messages"
+
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		// safety check-in and the /gabung command
+		templates := []messages.Template{
+			{Key: messages.KeyBotCommands, Language: messages.LanguageIndonesian, Body: "start - Mulai dan tampilkan bantuan" +
+				"\nlokasi - Lihat lokasi tersimpan" +
+				"\nstatus - Lihat lokasi dan pengaturan" +
+				"\nterkini - Gempa terkini" +
+				"\nhariini - Gempa hari ini" +
+				"\nradius - Batasi jarak gempa, misalnya /radius 100" +
+				"\nbahasa - Ganti bahasa pesan (id, en, jv, su)" +
+				"\npreferensi - Lihat dan atur notifikasi" +
+				"\nhapuslokasi - Hapus lokasi tersimpan" +
+				"\ngabung - Bergabung ke grup check-in, misalnya /gabung KODE Nama" +
+				"\nhapus - Berhenti berlangganan dan hapus data"},
+			{Key: messages.KeyBotCommands, Language: messages.LanguageEnglish, Body: "start - Start and show help" +
+				"\nlokasi - Show saved locations" +
+				"\nstatus - Show locations and settings" +
+				"\nterkini - Latest earthquake" +
+				"\nhariini - Today's earthquakes" +
+				"\nradius - Limit the quake distance, e.g. /radius 100" +
+				"\nbahasa - Change the message language (id, en, jv, su)" +
+				"\npreferensi - Show and manage notifications" +
+				"\nhapuslokasi - Remove a saved location" +
+				"\ngabung - Join a check-in group, e.g. /gabung CODE Name" +
+				"\nhapus - Unsubscribe and delete your data"},
+			{Key: messages.KeyCheckin, Language: messages.LanguageIndonesian, Body: "{{if .Reminder}}[PENGINGAT] {{end}}Apakah Anda aman?" +
+				"\nGempa M{{.Magnitude}} {{.Wilayah}}, perkiraan guncangan di lokasi Anda MMI {{.MMIRoman}}." +
+				"\nKabari grup Anda: {{.URL}}"},
+			{Key: messages.KeyCheckin, Language: messages.LanguageEnglish, Body: "{{if .Reminder}}[REMINDER] {{end}}Are you safe?" +
+				"\nM{{.Magnitude}} earthquake {{.Wilayah}}, estimated shaking at your location MMI {{.MMIRoman}}." +
+				"\nLet your group know: {{.URL}}"},
+			{Key: messages.KeyCheckin, Channel: "telegram", Language: messages.LanguageIndonesian, Body: "{{if .Reminder}}[PENGINGAT] {{end}}Apakah Anda aman?" +
+				"\nGempa M{{.Magnitude}} {{.Wilayah}}, perkiraan guncangan di lokasi Anda MMI {{.MMIRoman}}." +
+				"\nKabari grup Anda dengan tombol di bawah."},
+			{Key: messages.KeyCheckin, Channel: "telegram", Language: messages.LanguageEnglish, Body: "{{if .Reminder}}[REMINDER] {{end}}Are you safe?" +
+				"\nM{{.Magnitude}} earthquake {{.Wilayah}}, estimated shaking at your location MMI {{.MMIRoman}}." +
+				"\nLet your group know with the buttons below."},
+			{Key: messages.KeyBotButtonCheckinSafe, Language: messages.LanguageIndonesian, Body: "Aman"},
+			{Key: messages.KeyBotButtonCheckinSafe, Language: messages.LanguageEnglish, Body: "Safe"},
+			{Key: messages.KeyBotButtonCheckinNeedHelp, Language: messages.LanguageIndonesian, Body: "Butuh bantuan"},
+			{Key: messages.KeyBotButtonCheckinNeedHelp, Language: messages.LanguageEnglish, Body: "Need help"},
+			{Key: messages.KeyBotCheckinRecorded, Language: messages.LanguageIndonesian, Body: "{{if eq .Status \"need_help\"}}Status Anda: butuh bantuan. Grup Anda sudah diberi tahu.{{else}}Status Anda: aman. Terima kasih sudah mengabari grup Anda.{{end}}"},
+			{Key: messages.KeyBotCheckinRecorded, Language: messages.LanguageEnglish, Body: "{{if eq .Status \"need_help\"}}Your status: need help. Your group has been informed.{{else}}Your status: safe. Thank you for letting your group know.{{end}}"},
+			{Key: messages.KeyBotCheckinUnknown, Language: messages.LanguageIndonesian, Body: "Check-in ini tidak ditemukan."},
+			{Key: messages.KeyBotCheckinUnknown, Language: messages.LanguageEnglish, Body: "This check-in was not found."},
+			{Key: messages.KeyBotGroupUsage, Language: messages.LanguageIndonesian, Body: "Kirim /gabung KODE Nama, misalnya /gabung AB12CD34 Budi. Kode undangan didapat dari pengelola grup."},
+			{Key: messages.KeyBotGroupUsage, Language: messages.LanguageEnglish, Body: "Send /gabung CODE Name, e.g. /gabung AB12CD34 Budi. The invite code is given by the group owner."},
+			{Key: messages.KeyBotGroupJoined, Language: messages.LanguageIndonesian, Body: "Anda bergabung ke grup \"{{.Group}}\". Setelah gempa kuat Anda akan diminta melapor apakah Anda aman."},
+			{Key: messages.KeyBotGroupJoined, Language: messages.LanguageEnglish, Body: "You joined the group \"{{.Group}}\". After a strong earthquake you will be asked whether you are safe."},
+			{Key: messages.KeyBotGroupNotFound, Language: messages.LanguageIndonesian, Body: "Kode undangan tidak ditemukan."},
+			{Key: messages.KeyBotGroupNotFound, Language: messages.LanguageEnglish, Body: "Invite code not found."},
+		}
+
+		// the wording seeded before, replaced unless an admin edited it
+		replaced := []messages.Template{
+			{Key: messages.KeyBotCommands, Language: messages.LanguageIndonesian, Body: "start - Mulai dan tampilkan bantuan\n" +
+				"lokasi - Lihat lokasi tersimpan\n" +
+				"status - Lihat lokasi dan pengaturan\n" +
+				"terkini - Gempa terkini\n" +
+				"hariini - Gempa hari ini\n" +
+				"radius - Batasi jarak gempa, misalnya /radius 100\n" +
+				"bahasa - Ganti bahasa pesan (id, en, jv, su)\n" +
+				"preferensi - Lihat dan atur notifikasi\n" +
+				"hapuslokasi - Hapus lokasi tersimpan\n" +
+				"hapus - Berhenti berlangganan dan hapus data"},
+			{Key: messages.KeyBotCommands, Language: messages.LanguageEnglish, Body: "start - Start and show help\n" +
+				"lokasi - Show saved locations\n" +
+				"status - Show locations and settings\n" +
+				"terkini - Latest earthquake\n" +
+				"hariini - Today's earthquakes\n" +
+				"radius - Limit the quake distance, e.g. /radius 100\n" +
+				"bahasa - Change the message language (id, en, jv, su)\n" +
+				"preferensi - Show and manage notifications\n" +
+				"hapuslokasi - Remove a saved location\n" +
+				"hapus - Unsubscribe and delete your data"},
+		}
+
+		return upgradeMessageTemplates(app, templates, replaced)
+	}, func(app core.App) error {
+		// the previous wording is not restored
+		return nil
+	})
+}
@@ -0,0 +1,29 @@
+package migrations
+
+import (
+	"bmkg/src/messages"
+
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		// wording of the alert email around the alert text
+		templates := []messages.Template{
+			{Key: messages.KeyEmailSubject, Language: messages.LanguageIndonesian, Body: "{{.Title}}"},
+			{Key: messages.KeyEmailSubject, Language: messages.LanguageEnglish, Body: "{{.Title}}"},
+			{Key: messages.KeyEmailMapLink, Language: messages.LanguageIndonesian, Body: "Lihat episenter di peta"},
+			{Key: messages.KeyEmailMapLink, Language: messages.LanguageEnglish, Body: "View the epicenter on a map"},
+			{Key: messages.KeyEmailShakemap, Language: messages.LanguageIndonesian, Body: "Peta guncangan (shakemap) terlampir"},
+			{Key: messages.KeyEmailShakemap, Language: messages.LanguageEnglish, Body: "Shakemap attached"},
+			{Key: messages.KeyEmailFooter, Language: messages.LanguageIndonesian, Body: "Pesan ini dikirim otomatis oleh sistem peringatan gempa berdasarkan data BMKG."},
+			{Key: messages.KeyEmailFooter, Language: messages.LanguageEnglish, Body: "This message was sent automatically by the earthquake alert system based on BMKG data."},
+		}
+
+		return upgradeMessageTemplates(app, templates, nil)
+	}, func(app core.App) error {
+		// the added templates are kept
+		return nil
+	})
+}
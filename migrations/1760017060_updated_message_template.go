@@ -0,0 +1,163 @@
+package migrations
+
+import (
+	"bmkg/src/messages"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("pbc_2150196503")
+		if err != nil {
+			return err
+		}
+
+		// Javanese and Sundanese are no longer offered, their templates would never be picked
+		if _, err := app.DB().Delete("message_template", dbx.In("language", "jv", "su")).Execute(); err != nil {
+			return err
+		}
+
+		// update field
+		if err := collection.Fields.AddMarshaledJSONAt(4, []byte(`{
+			"hidden": false,
+			"id": "select3571151285",
+			"maxSelect": 1,
+			"name": "language",
+			"presentable": false,
+			"required": true,
+			"system": false,
+			"type": "select",
+			"values": [
+				"id",
+				"en"
+			]
+		}`)); err != nil {
+			return err
+		}
+
+		if err := app.Save(collection); err != nil {
+			return err
+		}
+
+		// the language lists only name id and en
+		templates := []messages.Template{
+			{Key: messages.KeyBotPreferencesHelp, Language: messages.LanguageIndonesian, Body: "Atur notifikasi Anda:" +
+				"\n/preferensi - lihat pengaturan" +
+				"\n/minmag 5 - hanya gempa M5 ke atas (off untuk semua)" +
+				"\n/minmmi 4 - hanya guncangan MMI IV ke atas di lokasi Anda" +
+				"\n/radius 100 - hanya gempa dalam 100 km dari lokasi Anda (off untuk semua)" +
+				"\n/sunyi 22:00-06:00 - jam tenang, kecuali peringatan bahaya (off untuk mematikan)" +
+				"\n/zonawaktu WITA - zona waktu jam tenang (WIB, WITA, WIT)" +
+				"\n/bahasa en - bahasa pesan (id, en)"},
+			{Key: messages.KeyBotPreferencesHelp, Language: messages.LanguageEnglish, Body: "Manage your notifications:" +
+				"\n/preferensi - show settings" +
+				"\n/minmag 5 - only M5 and above (off for all)" +
+				"\n/minmmi 4 - only shaking of MMI IV and above at your location" +
+				"\n/radius 100 - only quakes within 100 km of your locations (off for all)" +
+				"\n/sunyi 22:00-06:00 - quiet hours, except danger alerts (off to disable)" +
+				"\n/zonawaktu WITA - time zone of the quiet hours (WIB, WITA, WIT)" +
+				"\n/bahasa id - message language (id, en)"},
+			{Key: messages.KeyBotUsageLanguage, Language: messages.LanguageIndonesian, Body: "Tulis kode bahasa: id atau en, misalnya /bahasa en"},
+			{Key: messages.KeyBotUsageLanguage, Language: messages.LanguageEnglish, Body: "Write a language code: id or en, e.g. /bahasa id"},
+			{Key: messages.KeyBotCommands, Language: messages.LanguageIndonesian, Body: "start - Mulai dan tampilkan bantuan" +
+				"\nlokasi - Lihat lokasi tersimpan" +
+				"\nstatus - Lihat lokasi dan pengaturan" +
+				"\nterkini - Gempa terkini" +
+				"\nhariini - Gempa hari ini" +
+				"\nradius - Batasi jarak gempa, misalnya /radius 100" +
+				"\nbahasa - Ganti bahasa pesan (id, en)" +
+				"\npreferensi - Lihat dan atur notifikasi" +
+				"\nhapuslokasi - Hapus lokasi tersimpan" +
+				"\ngabung - Bergabung ke grup check-in, misalnya /gabung KODE Nama" +
+				"\nhapus - Berhenti berlangganan dan hapus data"},
+			{Key: messages.KeyBotCommands, Language: messages.LanguageEnglish, Body: "start - Start and show help" +
+				"\nlokasi - Show saved locations" +
+				"\nstatus - Show locations and settings" +
+				"\nterkini - Latest earthquake" +
+				"\nhariini - Today's earthquakes" +
+				"\nradius - Limit the quake distance, e.g. /radius 100" +
+				"\nbahasa - Change the message language (id, en)" +
+				"\npreferensi - Show and manage notifications" +
+				"\nhapuslokasi - Remove a saved location" +
+				"\ngabung - Join a check-in group, e.g. /gabung CODE Name" +
+				"\nhapus - Unsubscribe and delete your data"},
+		}
+
+		// the wording seeded before, replaced unless an admin edited it
+		replaced := []messages.Template{
+			{Key: messages.KeyBotPreferencesHelp, Language: messages.LanguageIndonesian, Body: "Atur notifikasi Anda:" +
+				"\n/preferensi - lihat pengaturan" +
+				"\n/minmag 5 - hanya gempa M5 ke atas (off untuk semua)" +
+				"\n/minmmi 4 - hanya guncangan MMI IV ke atas di lokasi Anda" +
+				"\n/radius 100 - hanya gempa dalam 100 km dari lokasi Anda (off untuk semua)" +
+				"\n/sunyi 22:00-06:00 - jam tenang, kecuali peringatan bahaya (off untuk mematikan)" +
+				"\n/zonawaktu WITA - zona waktu jam tenang (WIB, WITA, WIT)" +
+				"\n/bahasa en - bahasa pesan (id, en, jv, su)"},
+			{Key: messages.KeyBotPreferencesHelp, Language: messages.LanguageEnglish, Body: "Manage your notifications:" +
+				"\n/preferensi - show settings" +
+				"\n/minmag 5 - only M5 and above (off for all)" +
+				"\n/minmmi 4 - only shaking of MMI IV and above at your location" +
+				"\n/radius 100 - only quakes within 100 km of your locations (off for all)" +
+				"\n/sunyi 22:00-06:00 - quiet hours, except danger alerts (off to disable)" +
+				"\n/zonawaktu WITA - time zone of the quiet hours (WIB, WITA, WIT)" +
+				"\n/bahasa id - message language (id, en, jv, su)"},
+			{Key: messages.KeyBotUsageLanguage, Language: messages.LanguageIndonesian, Body: "Tulis kode bahasa: id, en, jv atau su, misalnya /bahasa jv"},
+			{Key: messages.KeyBotUsageLanguage, Language: messages.LanguageEnglish, Body: "Write a language code: id, en, jv or su, e.g. /bahasa en"},
+			{Key: messages.KeyBotCommands, Language: messages.LanguageIndonesian, Body: "start - Mulai dan tampilkan bantuan" +
+				"\nlokasi - Lihat lokasi tersimpan" +
+				"\nstatus - Lihat lokasi dan pengaturan" +
+				"\nterkini - Gempa terkini" +
+				"\nhariini - Gempa hari ini" +
+				"\nradius - Batasi jarak gempa, misalnya /radius 100" +
+				"\nbahasa - Ganti bahasa pesan (id, en, jv, su)" +
+				"\npreferensi - Lihat dan atur notifikasi" +
+				"\nhapuslokasi - Hapus lokasi tersimpan" +
+				"\ngabung - Bergabung ke grup check-in, misalnya /gabung KODE Nama" +
+				"\nhapus - Berhenti berlangganan dan hapus data"},
+			{Key: messages.KeyBotCommands, Language: messages.LanguageEnglish, Body: "start - Start and show help" +
+				"\nlokasi - Show saved locations" +
+				"\nstatus - Show locations and settings" +
+				"\nterkini - Latest earthquake" +
+				"\nhariini - Today's earthquakes" +
+				"\nradius - Limit the quake distance, e.g. /radius 100" +
+				"\nbahasa - Change the message language (id, en, jv, su)" +
+				"\npreferensi - Show and manage notifications" +
+				"\nhapuslokasi - Remove a saved location" +
+				"\ngabung - Join a check-in group, e.g. /gabung CODE Name" +
+				"\nhapus - Unsubscribe and delete your data"},
+		}
+
+		return upgradeMessageTemplates(app, templates, replaced)
+	}, func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("pbc_2150196503")
+		if err != nil {
+			return err
+		}
+
+		// update field
+		if err := collection.Fields.AddMarshaledJSONAt(4, []byte(`{
+			"hidden": false,
+			"id": "select3571151285",
+			"maxSelect": 1,
+			"name": "language",
+			"presentable": false,
+			"required": true,
+			"system": false,
+			"type": "select",
+			"values": [
+				"id",
+				"en",
+				"jv",
+				"su"
+			]
+		}`)); err != nil {
+			return err
+		}
+
+		// the previous wording is not restored
+		return app.Save(collection)
+	})
+}
@@ -0,0 +1,57 @@
+package migrations
+
+import (
+	"bmkg/src/messages"
+	"database/sql"
+	"errors"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// upgradeMessageTemplates stores the templates of one migration. Missing templates are
+// added, templates still holding one of the replaced built-in bodies get the new body.
+// Wording edited by an admin is kept. Migrations pass literal templates instead of
+// messages.Defaults, so running them later yields what they stored when written.
+func upgradeMessageTemplates(app core.App, templates []messages.Template, replaced []messages.Template) error {
+	collection, err := app.FindCollectionByNameOrId("message_template")
+	if err != nil {
+		return err
+	}
+
+	for _, item := range templates {
+		record := &core.Record{}
+		err := app.RecordQuery(collection).
+			AndWhere(dbx.HashExp{"key": item.Key, "channel": item.Channel, "tier": item.Tier, "language": item.Language}).
+			Limit(1).
+			One(record)
+		if errors.Is(err, sql.ErrNoRows) {
+			record = core.NewRecord(collection)
+			record.Set("key", item.Key)
+			record.Set("channel", item.Channel)
+			record.Set("tier", item.Tier)
+			record.Set("language", item.Language)
+		} else if err != nil {
+			return err
+		} else if !replacedBody(replaced, item, record.GetString("body")) {
+			continue
+		}
+
+		record.Set("body", item.Body)
+		if err := app.Save(record); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func replacedBody(replaced []messages.Template, item messages.Template, body string) bool {
+	for _, old := range replaced {
+		if old.Key == item.Key && old.Channel == item.Channel && old.Tier == item.Tier &&
+			old.Language == item.Language && old.Body == body {
+			return true
+		}
+	}
+	return false
+}
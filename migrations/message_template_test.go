@@ -0,0 +1,49 @@
+package migrations_test
+
+import (
+	"testing"
+
+	_ "bmkg/migrations"
+	"bmkg/src/messages"
+
+	"github.com/pocketbase/pocketbase"
+)
+
+// TestMessageTemplatesMatchDefaults guards the frozen template migrations: a fresh
+// database must end up with exactly the built-in wording. When it fails, the
+// defaults changed without a migration carrying the new bodies.
+func TestMessageTemplatesMatchDefaults(t *testing.T) {
+	app := pocketbase.NewWithConfig(pocketbase.Config{DefaultDataDir: t.TempDir()})
+	if err := app.Bootstrap(); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.RunAllMigrations(); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := app.FindAllRecords("message_template")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stored := make(map[messages.Template]bool, len(records))
+	for _, record := range records {
+		stored[messages.Template{
+			Key:      record.GetString("key"),
+			Channel:  record.GetString("channel"),
+			Tier:     record.GetString("tier"),
+			Language: record.GetString("language"),
+			Body:     record.GetString("body"),
+		}] = true
+	}
+
+	defaults := messages.Defaults()
+	for _, item := range defaults {
+		if !stored[item] {
+			t.Errorf("template %s/%s/%s/%s missing or outdated after migrating", item.Key, item.Channel, item.Tier, item.Language)
+		}
+	}
+	if len(records) != len(defaults) {
+		t.Errorf("got %d stored templates, want %d", len(records), len(defaults))
+	}
+}
@@ -4,29 +4,84 @@ import (
 	"github.com/joho/godotenv"
 	"log"
 	"os"
+	"strconv"
 )
 
 type Config struct {
 	DSN          string `json:"DSN"`
 	PortListener string `json:"PortListener"`
 	SMTPHost     string `json:"SMTPHost"`
+	SMTPPort     int    `json:"SMTPPort"`
+	SMTPUsername string `json:"SMTPUsername"`
+	SMTPPassword string `json:"SMTPPassword"`
+	SMTPTLS      bool   `json:"SMTPTLS"`
+	MailFrom     string `json:"MailFrom"`
+	MailFromName string `json:"MailFromName"`
+	GMPEModel    string `json:"GMPEModel"`
+	AlertTiers   string `json:"AlertTiers"`
+
+	WhatsAppPhoneNumberID string `json:"WhatsAppPhoneNumberID"`
+	WhatsAppAccessToken   string `json:"WhatsAppAccessToken"`
+	WhatsAppTemplate      string `json:"WhatsAppTemplate"`
+	WhatsAppTemplateImage bool   `json:"WhatsAppTemplateImage"`
+	WhatsAppVerifyToken   string `json:"WhatsAppVerifyToken"`
+	WhatsAppAppSecret     string `json:"WhatsAppAppSecret"`
+
+	FCMCredentialsFile string `json:"FCMCredentialsFile"`
+
+	TelegramBotToken      string `json:"TelegramBotToken"`
+	TelegramWebhookURL    string `json:"TelegramWebhookURL"`
+	TelegramWebhookSecret string `json:"TelegramWebhookSecret"`
+
+	SafetyCheckinMMI float64 `json:"SafetyCheckinMMI"`
 }
 
 func NewConfig() Config {
 	return Config{
 		DSN:          "default_dsn",
 		PortListener: "default_port",
+		GMPEModel:    "fukushima-tanaka",
 	}
 }
 
+// LoadConfig membaca konfigurasi dari environment. File .env bersifat opsional,
+// sehingga deployment bisa mengisi variabel langsung tanpa file tersebut.
 func LoadConfig() Config {
-	err := godotenv.Load()
-	if err != nil {
-		log.Fatal("Error loading .env file")
+	if err := godotenv.Load(); err != nil {
+		log.Printf("No .env file loaded, using environment: %v", err)
 	}
 
+	// port kosong atau tidak valid memakai default mailer (587)
+	smtpPort, _ := strconv.Atoi(os.Getenv("SMTP_PORT"))
+	// kosong atau tidak valid memakai default check-in (MMI VI)
+	checkinMMI, _ := strconv.ParseFloat(os.Getenv("SAFETY_CHECKIN_MMI"), 64)
+
 	return Config{
 		DSN:          os.Getenv("DSN"),
 		PortListener: os.Getenv("PORT_LISTENER"),
+		SMTPHost:     os.Getenv("SMTP_HOST"),
+		SMTPPort:     smtpPort,
+		SMTPUsername: os.Getenv("SMTP_USERNAME"),
+		SMTPPassword: os.Getenv("SMTP_PASSWORD"),
+		SMTPTLS:      os.Getenv("SMTP_TLS") == "true",
+		MailFrom:     os.Getenv("MAIL_FROM"),
+		MailFromName: os.Getenv("MAIL_FROM_NAME"),
+		GMPEModel:    os.Getenv("GMPE_MODEL"),
+		AlertTiers:   os.Getenv("ALERT_TIERS"),
+
+		WhatsAppPhoneNumberID: os.Getenv("WA_PHONE_NUMBER_ID"),
+		WhatsAppAccessToken:   os.Getenv("WA_ACCESS_TOKEN"),
+		WhatsAppTemplate:      os.Getenv("WA_TEMPLATE"),
+		WhatsAppTemplateImage: os.Getenv("WA_TEMPLATE_HEADER_IMAGE") == "true",
+		WhatsAppVerifyToken:   os.Getenv("WA_VERIFY_TOKEN"),
+		WhatsAppAppSecret:     os.Getenv("WA_APP_SECRET"),
+
+		FCMCredentialsFile: os.Getenv("FCM_CREDENTIALS_FILE"),
+
+		TelegramBotToken:      os.Getenv("TELEGRAM_BOT_TOKEN"),
+		TelegramWebhookURL:    os.Getenv("TELEGRAM_WEBHOOK_URL"),
+		TelegramWebhookSecret: os.Getenv("TELEGRAM_WEBHOOK_SECRET"),
+
+		SafetyCheckinMMI: checkinMMI,
 	}
 }
@@ -111,12 +111,12 @@ func (p *Earthquake) SetBujur(bujur string) {
 	p.Set("Bujur", bujur)
 }
 
-func (p *Earthquake) Magnitude() string {
-	return p.GetString("Magnitude")
+func (p *Earthquake) MagnitudeText() string {
+	return p.GetString("MagnitudeText")
 }
 
-func (p *Earthquake) SetMagnitude(magnitude string) {
-	p.Set("Magnitude", magnitude)
+func (p *Earthquake) SetMagnitudeText(magnitudeText string) {
+	p.Set("MagnitudeText", magnitudeText)
 }
 
 func (p *Earthquake) Kedalaman() string {
@@ -183,6 +183,71 @@ func (p *Earthquake) SetDateTime(dateTime types.DateTime) {
 	p.Set("DateTime", dateTime)
 }
 
+func (p *Earthquake) Fingerprint() string {
+	return p.GetString("fingerprint")
+}
+
+func (p *Earthquake) SetFingerprint(fingerprint string) {
+	p.Set("fingerprint", fingerprint)
+}
+
+func (p *Earthquake) Sources() types.JSONRaw {
+	raw, _ := p.GetRaw("sources").(types.JSONRaw)
+	return raw
+}
+
+func (p *Earthquake) SetSources(sources types.JSONRaw) {
+	p.Set("sources", sources)
+}
+
+func (p *Earthquake) Magnitude() float64 {
+	return p.GetFloat("magnitude")
+}
+
+func (p *Earthquake) SetMagnitude(magnitude float64) {
+	p.Set("magnitude", magnitude)
+}
+
+func (p *Earthquake) DepthKm() float64 {
+	return p.GetFloat("depth_km")
+}
+
+func (p *Earthquake) SetDepthKm(depthKm float64) {
+	p.Set("depth_km", depthKm)
+}
+
+func (p *Earthquake) Lat() float64 {
+	return p.GetFloat("lat")
+}
+
+func (p *Earthquake) SetLat(lat float64) {
+	p.Set("lat", lat)
+}
+
+func (p *Earthquake) Lon() float64 {
+	return p.GetFloat("lon")
+}
+
+func (p *Earthquake) SetLon(lon float64) {
+	p.Set("lon", lon)
+}
+
+func (p *Earthquake) OriginTime() types.DateTime {
+	return p.GetDateTime("origin_time")
+}
+
+func (p *Earthquake) SetOriginTime(originTime types.DateTime) {
+	p.Set("origin_time", originTime)
+}
+
+func (p *Earthquake) Revision() int {
+	return p.GetInt("revision")
+}
+
+func (p *Earthquake) SetRevision(revision int) {
+	p.Set("revision", revision)
+}
+
 func (p *Earthquake) Created() types.DateTime {
 	return p.GetDateTime("created")
 }
@@ -372,15 +437,21 @@ type TypeSelectType int
 const (
 	Wa TypeSelectType = iota
 	Telegram
+	Android
+	Email
 )
 
 var zzTypeSelectTypeSelectNameMap = map[string]TypeSelectType{
 	"wa":       0,
 	"telegram": 1,
+	"android":  2,
+	"email":    3,
 }
 var zzTypeSelectTypeSelectIotaMap = map[TypeSelectType]string{
 	0: "wa",
 	1: "telegram",
+	2: "android",
+	3: "email",
 }
 
 type UserNotify struct {
@@ -432,6 +503,71 @@ func (p *UserNotify) SetType(type_ TypeSelectType) {
 	p.Set("type", i)
 }
 
+func (p *UserNotify) MinMagnitude() float64 {
+	return p.GetFloat("min_magnitude")
+}
+
+func (p *UserNotify) SetMinMagnitude(minMagnitude float64) {
+	p.Set("min_magnitude", minMagnitude)
+}
+
+func (p *UserNotify) MinMmi() float64 {
+	return p.GetFloat("min_mmi")
+}
+
+func (p *UserNotify) SetMinMmi(minMmi float64) {
+	p.Set("min_mmi", minMmi)
+}
+
+func (p *UserNotify) QuietStart() string {
+	return p.GetString("quiet_start")
+}
+
+func (p *UserNotify) SetQuietStart(quietStart string) {
+	p.Set("quiet_start", quietStart)
+}
+
+func (p *UserNotify) QuietEnd() string {
+	return p.GetString("quiet_end")
+}
+
+func (p *UserNotify) SetQuietEnd(quietEnd string) {
+	p.Set("quiet_end", quietEnd)
+}
+
+func (p *UserNotify) Timezone() string {
+	return p.GetString("timezone")
+}
+
+func (p *UserNotify) SetTimezone(timezone string) {
+	p.Set("timezone", timezone)
+}
+
+func (p *UserNotify) Language() LanguageSelectType {
+	option := p.GetString("language")
+	i, ok := zzLanguageSelectTypeSelectNameMap[option]
+	if !ok {
+		panic("Unknown select value")
+	}
+	return i
+}
+
+func (p *UserNotify) SetLanguage(language LanguageSelectType) {
+	i, ok := zzLanguageSelectTypeSelectIotaMap[language]
+	if !ok {
+		panic("Unknown select value")
+	}
+	p.Set("language", i)
+}
+
+func (p *UserNotify) RadiusKm() float64 {
+	return p.GetFloat("radius_km")
+}
+
+func (p *UserNotify) SetRadiusKm(radiusKm float64) {
+	p.Set("radius_km", radiusKm)
+}
+
 func (p *UserNotify) Created() types.DateTime {
 	return p.GetDateTime("created")
 }
@@ -463,3 +599,1480 @@ func (p *ViewGempa) Created() types.DateTime {
 func (p *ViewGempa) SetCreated(created types.DateTime) {
 	p.Set("created", created)
 }
+
+type WorkerState struct {
+	core.BaseRecordProxy
+}
+
+func (p *WorkerState) CollectionName() string {
+	return "worker_state"
+}
+
+func (p *WorkerState) Key() string {
+	return p.GetString("key")
+}
+
+func (p *WorkerState) SetKey(key string) {
+	p.Set("key", key)
+}
+
+func (p *WorkerState) Value() types.JSONRaw {
+	raw, _ := p.GetRaw("value").(types.JSONRaw)
+	return raw
+}
+
+func (p *WorkerState) SetValue(value types.JSONRaw) {
+	p.Set("value", value)
+}
+
+func (p *WorkerState) Created() types.DateTime {
+	return p.GetDateTime("created")
+}
+
+func (p *WorkerState) SetCreated(created types.DateTime) {
+	p.Set("created", created)
+}
+
+func (p *WorkerState) Updated() types.DateTime {
+	return p.GetDateTime("updated")
+}
+
+func (p *WorkerState) SetUpdated(updated types.DateTime) {
+	p.Set("updated", updated)
+}
+
+type EarthquakeRevision struct {
+	core.BaseRecordProxy
+}
+
+func (p *EarthquakeRevision) CollectionName() string {
+	return "earthquake_revision"
+}
+
+func (p *EarthquakeRevision) Earthquake() *Earthquake {
+	var proxy *Earthquake
+	if rel := p.ExpandedOne("earthquake"); rel != nil {
+		proxy = &Earthquake{}
+		proxy.Record = rel
+	}
+	return proxy
+}
+
+func (p *EarthquakeRevision) SetEarthquake(earthquake *Earthquake) {
+	var id string
+	if earthquake != nil {
+		id = earthquake.Id
+	}
+	p.Record.Set("earthquake", id)
+	e := p.Expand()
+	if earthquake != nil {
+		e["earthquake"] = earthquake.Record
+	} else {
+		delete(e, "earthquake")
+	}
+	p.SetExpand(e)
+}
+
+func (p *EarthquakeRevision) Revision() int {
+	return p.GetInt("revision")
+}
+
+func (p *EarthquakeRevision) SetRevision(revision int) {
+	p.Set("revision", revision)
+}
+
+func (p *EarthquakeRevision) Source() string {
+	return p.GetString("source")
+}
+
+func (p *EarthquakeRevision) SetSource(source string) {
+	p.Set("source", source)
+}
+
+func (p *EarthquakeRevision) Magnitude() float64 {
+	return p.GetFloat("magnitude")
+}
+
+func (p *EarthquakeRevision) SetMagnitude(magnitude float64) {
+	p.Set("magnitude", magnitude)
+}
+
+func (p *EarthquakeRevision) DepthKm() float64 {
+	return p.GetFloat("depth_km")
+}
+
+func (p *EarthquakeRevision) SetDepthKm(depthKm float64) {
+	p.Set("depth_km", depthKm)
+}
+
+func (p *EarthquakeRevision) Lat() float64 {
+	return p.GetFloat("lat")
+}
+
+func (p *EarthquakeRevision) SetLat(lat float64) {
+	p.Set("lat", lat)
+}
+
+func (p *EarthquakeRevision) Lon() float64 {
+	return p.GetFloat("lon")
+}
+
+func (p *EarthquakeRevision) SetLon(lon float64) {
+	p.Set("lon", lon)
+}
+
+func (p *EarthquakeRevision) OriginTime() types.DateTime {
+	return p.GetDateTime("origin_time")
+}
+
+func (p *EarthquakeRevision) SetOriginTime(originTime types.DateTime) {
+	p.Set("origin_time", originTime)
+}
+
+func (p *EarthquakeRevision) Fingerprint() string {
+	return p.GetString("fingerprint")
+}
+
+func (p *EarthquakeRevision) SetFingerprint(fingerprint string) {
+	p.Set("fingerprint", fingerprint)
+}
+
+func (p *EarthquakeRevision) Created() types.DateTime {
+	return p.GetDateTime("created")
+}
+
+func (p *EarthquakeRevision) SetCreated(created types.DateTime) {
+	p.Set("created", created)
+}
+
+func (p *EarthquakeRevision) Updated() types.DateTime {
+	return p.GetDateTime("updated")
+}
+
+func (p *EarthquakeRevision) SetUpdated(updated types.DateTime) {
+	p.Set("updated", updated)
+}
+
+type OutboxStatusSelectType int
+
+const (
+	OutboxPending OutboxStatusSelectType = iota
+	OutboxSending
+	OutboxSent
+	OutboxFailed
+	OutboxExpired
+)
+
+var zzOutboxStatusSelectTypeSelectNameMap = map[string]OutboxStatusSelectType{
+	"pending": 0,
+	"sending": 1,
+	"sent":    2,
+	"failed":  3,
+	"expired": 4,
+}
+var zzOutboxStatusSelectTypeSelectIotaMap = map[OutboxStatusSelectType]string{
+	0: "pending",
+	1: "sending",
+	2: "sent",
+	3: "failed",
+	4: "expired",
+}
+
+type NotificationOutbox struct {
+	core.BaseRecordProxy
+}
+
+func (p *NotificationOutbox) CollectionName() string {
+	return "notification_outbox"
+}
+
+func (p *NotificationOutbox) DedupeKey() string {
+	return p.GetString("dedupe_key")
+}
+
+func (p *NotificationOutbox) SetDedupeKey(dedupeKey string) {
+	p.Set("dedupe_key", dedupeKey)
+}
+
+func (p *NotificationOutbox) Channel() string {
+	return p.GetString("channel")
+}
+
+func (p *NotificationOutbox) SetChannel(channel string) {
+	p.Set("channel", channel)
+}
+
+func (p *NotificationOutbox) Recipient() string {
+	return p.GetString("recipient")
+}
+
+func (p *NotificationOutbox) SetRecipient(recipient string) {
+	p.Set("recipient", recipient)
+}
+
+func (p *NotificationOutbox) Earthquake() *Earthquake {
+	var proxy *Earthquake
+	if rel := p.ExpandedOne("earthquake"); rel != nil {
+		proxy = &Earthquake{}
+		proxy.Record = rel
+	}
+	return proxy
+}
+
+func (p *NotificationOutbox) SetEarthquake(earthquake *Earthquake) {
+	var id string
+	if earthquake != nil {
+		id = earthquake.Id
+	}
+	p.Record.Set("earthquake", id)
+	e := p.Expand()
+	if earthquake != nil {
+		e["earthquake"] = earthquake.Record
+	} else {
+		delete(e, "earthquake")
+	}
+	p.SetExpand(e)
+}
+
+func (p *NotificationOutbox) Payload() types.JSONRaw {
+	raw, _ := p.GetRaw("payload").(types.JSONRaw)
+	return raw
+}
+
+func (p *NotificationOutbox) SetPayload(payload types.JSONRaw) {
+	p.Set("payload", payload)
+}
+
+func (p *NotificationOutbox) Status() OutboxStatusSelectType {
+	option := p.GetString("status")
+	i, ok := zzOutboxStatusSelectTypeSelectNameMap[option]
+	if !ok {
+		panic("Unknown select value")
+	}
+	return i
+}
+
+func (p *NotificationOutbox) SetStatus(status OutboxStatusSelectType) {
+	i, ok := zzOutboxStatusSelectTypeSelectIotaMap[status]
+	if !ok {
+		panic("Unknown select value")
+	}
+	p.Set("status", i)
+}
+
+func (p *NotificationOutbox) Attempts() int {
+	return p.GetInt("attempts")
+}
+
+func (p *NotificationOutbox) SetAttempts(attempts int) {
+	p.Set("attempts", attempts)
+}
+
+func (p *NotificationOutbox) NextAttemptAt() types.DateTime {
+	return p.GetDateTime("next_attempt_at")
+}
+
+func (p *NotificationOutbox) SetNextAttemptAt(nextAttemptAt types.DateTime) {
+	p.Set("next_attempt_at", nextAttemptAt)
+}
+
+func (p *NotificationOutbox) ExpiresAt() types.DateTime {
+	return p.GetDateTime("expires_at")
+}
+
+func (p *NotificationOutbox) SetExpiresAt(expiresAt types.DateTime) {
+	p.Set("expires_at", expiresAt)
+}
+
+func (p *NotificationOutbox) SentAt() types.DateTime {
+	return p.GetDateTime("sent_at")
+}
+
+func (p *NotificationOutbox) SetSentAt(sentAt types.DateTime) {
+	p.Set("sent_at", sentAt)
+}
+
+func (p *NotificationOutbox) LastError() string {
+	return p.GetString("last_error")
+}
+
+func (p *NotificationOutbox) SetLastError(lastError string) {
+	p.Set("last_error", lastError)
+}
+
+func (p *NotificationOutbox) Mmi() float64 {
+	return p.GetFloat("mmi")
+}
+
+func (p *NotificationOutbox) SetMmi(mmi float64) {
+	p.Set("mmi", mmi)
+}
+
+func (p *NotificationOutbox) DistanceKm() float64 {
+	return p.GetFloat("distance_km")
+}
+
+func (p *NotificationOutbox) SetDistanceKm(distanceKm float64) {
+	p.Set("distance_km", distanceKm)
+}
+
+func (p *NotificationOutbox) ProviderMessageId() string {
+	return p.GetString("provider_message_id")
+}
+
+func (p *NotificationOutbox) SetProviderMessageId(providerMessageId string) {
+	p.Set("provider_message_id", providerMessageId)
+}
+
+func (p *NotificationOutbox) ProviderStatus() string {
+	return p.GetString("provider_status")
+}
+
+func (p *NotificationOutbox) SetProviderStatus(providerStatus string) {
+	p.Set("provider_status", providerStatus)
+}
+
+func (p *NotificationOutbox) Created() types.DateTime {
+	return p.GetDateTime("created")
+}
+
+func (p *NotificationOutbox) SetCreated(created types.DateTime) {
+	p.Set("created", created)
+}
+
+func (p *NotificationOutbox) Updated() types.DateTime {
+	return p.GetDateTime("updated")
+}
+
+func (p *NotificationOutbox) SetUpdated(updated types.DateTime) {
+	p.Set("updated", updated)
+}
+
+type AttemptStatusSelectType int
+
+const (
+	AttemptSent AttemptStatusSelectType = iota
+	AttemptFailed
+)
+
+var zzAttemptStatusSelectTypeSelectNameMap = map[string]AttemptStatusSelectType{
+	"sent":   0,
+	"failed": 1,
+}
+var zzAttemptStatusSelectTypeSelectIotaMap = map[AttemptStatusSelectType]string{
+	0: "sent",
+	1: "failed",
+}
+
+type NotificationAttempt struct {
+	core.BaseRecordProxy
+}
+
+func (p *NotificationAttempt) CollectionName() string {
+	return "notification_attempt"
+}
+
+func (p *NotificationAttempt) Outbox() *NotificationOutbox {
+	var proxy *NotificationOutbox
+	if rel := p.ExpandedOne("outbox"); rel != nil {
+		proxy = &NotificationOutbox{}
+		proxy.Record = rel
+	}
+	return proxy
+}
+
+func (p *NotificationAttempt) SetOutbox(outbox *NotificationOutbox) {
+	var id string
+	if outbox != nil {
+		id = outbox.Id
+	}
+	p.Record.Set("outbox", id)
+	e := p.Expand()
+	if outbox != nil {
+		e["outbox"] = outbox.Record
+	} else {
+		delete(e, "outbox")
+	}
+	p.SetExpand(e)
+}
+
+func (p *NotificationAttempt) Attempt() int {
+	return p.GetInt("attempt")
+}
+
+func (p *NotificationAttempt) SetAttempt(attempt int) {
+	p.Set("attempt", attempt)
+}
+
+func (p *NotificationAttempt) Status() AttemptStatusSelectType {
+	option := p.GetString("status")
+	i, ok := zzAttemptStatusSelectTypeSelectNameMap[option]
+	if !ok {
+		panic("Unknown select value")
+	}
+	return i
+}
+
+func (p *NotificationAttempt) SetStatus(status AttemptStatusSelectType) {
+	i, ok := zzAttemptStatusSelectTypeSelectIotaMap[status]
+	if !ok {
+		panic("Unknown select value")
+	}
+	p.Set("status", i)
+}
+
+func (p *NotificationAttempt) Error() string {
+	return p.GetString("error")
+}
+
+func (p *NotificationAttempt) SetError(error string) {
+	p.Set("error", error)
+}
+
+func (p *NotificationAttempt) DurationMs() int {
+	return p.GetInt("duration_ms")
+}
+
+func (p *NotificationAttempt) SetDurationMs(durationMs int) {
+	p.Set("duration_ms", durationMs)
+}
+
+func (p *NotificationAttempt) Created() types.DateTime {
+	return p.GetDateTime("created")
+}
+
+func (p *NotificationAttempt) SetCreated(created types.DateTime) {
+	p.Set("created", created)
+}
+
+func (p *NotificationAttempt) Updated() types.DateTime {
+	return p.GetDateTime("updated")
+}
+
+func (p *NotificationAttempt) SetUpdated(updated types.DateTime) {
+	p.Set("updated", updated)
+}
+
+type PlatformSelectType int
+
+const (
+	PlatformAndroid PlatformSelectType = iota
+	PlatformIos
+)
+
+var zzPlatformSelectTypeSelectNameMap = map[string]PlatformSelectType{
+	"android": 0,
+	"ios":     1,
+}
+var zzPlatformSelectTypeSelectIotaMap = map[PlatformSelectType]string{
+	0: "android",
+	1: "ios",
+}
+
+type PushToken struct {
+	core.BaseRecordProxy
+}
+
+func (p *PushToken) CollectionName() string {
+	return "push_token"
+}
+
+func (p *PushToken) User() *Users {
+	var proxy *Users
+	if rel := p.ExpandedOne("user"); rel != nil {
+		proxy = &Users{}
+		proxy.Record = rel
+	}
+	return proxy
+}
+
+func (p *PushToken) SetUser(user *Users) {
+	var id string
+	if user != nil {
+		id = user.Id
+	}
+	p.Record.Set("user", id)
+	e := p.Expand()
+	if user != nil {
+		e["user"] = user.Record
+	} else {
+		delete(e, "user")
+	}
+	p.SetExpand(e)
+}
+
+func (p *PushToken) Token() string {
+	return p.GetString("token")
+}
+
+func (p *PushToken) SetToken(token string) {
+	p.Set("token", token)
+}
+
+func (p *PushToken) Platform() PlatformSelectType {
+	option := p.GetString("platform")
+	i, ok := zzPlatformSelectTypeSelectNameMap[option]
+	if !ok {
+		panic("Unknown select value")
+	}
+	return i
+}
+
+func (p *PushToken) SetPlatform(platform PlatformSelectType) {
+	i, ok := zzPlatformSelectTypeSelectIotaMap[platform]
+	if !ok {
+		panic("Unknown select value")
+	}
+	p.Set("platform", i)
+}
+
+func (p *PushToken) LastSeen() types.DateTime {
+	return p.GetDateTime("last_seen")
+}
+
+func (p *PushToken) SetLastSeen(lastSeen types.DateTime) {
+	p.Set("last_seen", lastSeen)
+}
+
+func (p *PushToken) Created() types.DateTime {
+	return p.GetDateTime("created")
+}
+
+func (p *PushToken) SetCreated(created types.DateTime) {
+	p.Set("created", created)
+}
+
+func (p *PushToken) Updated() types.DateTime {
+	return p.GetDateTime("updated")
+}
+
+func (p *PushToken) SetUpdated(updated types.DateTime) {
+	p.Set("updated", updated)
+}
+
+type EventTypesSelectType int
+
+const (
+	EarthquakeCreated EventTypesSelectType = iota
+	EarthquakeRevised
+)
+
+var zzEventTypesSelectTypeSelectNameMap = map[string]EventTypesSelectType{
+	"earthquake.created": 0,
+	"earthquake.revised": 1,
+}
+var zzEventTypesSelectTypeSelectIotaMap = map[EventTypesSelectType]string{
+	0: "earthquake.created",
+	1: "earthquake.revised",
+}
+
+type WebhookSubscription struct {
+	core.BaseRecordProxy
+}
+
+func (p *WebhookSubscription) CollectionName() string {
+	return "webhook_subscription"
+}
+
+func (p *WebhookSubscription) Name() string {
+	return p.GetString("name")
+}
+
+func (p *WebhookSubscription) SetName(name string) {
+	p.Set("name", name)
+}
+
+func (p *WebhookSubscription) Url() string {
+	return p.GetString("url")
+}
+
+func (p *WebhookSubscription) SetUrl(url string) {
+	p.Set("url", url)
+}
+
+func (p *WebhookSubscription) Secret() string {
+	return p.GetString("secret")
+}
+
+func (p *WebhookSubscription) SetSecret(secret string) {
+	p.Set("secret", secret)
+}
+
+func (p *WebhookSubscription) EventTypes() []EventTypesSelectType {
+	options := p.GetStringSlice("event_types")
+	eventTypes := make([]EventTypesSelectType, len(options))
+	for i, option := range options {
+		o, ok := zzEventTypesSelectTypeSelectNameMap[option]
+		if !ok {
+			panic("Unknown select value")
+		}
+		eventTypes[i] = o
+	}
+	return eventTypes
+}
+
+func (p *WebhookSubscription) SetEventTypes(eventTypes []EventTypesSelectType) {
+	options := make([]string, len(eventTypes))
+	for i, eventType := range eventTypes {
+		o, ok := zzEventTypesSelectTypeSelectIotaMap[eventType]
+		if !ok {
+			panic("Unknown select value")
+		}
+		options[i] = o
+	}
+	p.Set("event_types", options)
+}
+
+func (p *WebhookSubscription) MinMagnitude() float64 {
+	return p.GetFloat("min_magnitude")
+}
+
+func (p *WebhookSubscription) SetMinMagnitude(minMagnitude float64) {
+	p.Set("min_magnitude", minMagnitude)
+}
+
+func (p *WebhookSubscription) MinLat() float64 {
+	return p.GetFloat("min_lat")
+}
+
+func (p *WebhookSubscription) SetMinLat(minLat float64) {
+	p.Set("min_lat", minLat)
+}
+
+func (p *WebhookSubscription) MaxLat() float64 {
+	return p.GetFloat("max_lat")
+}
+
+func (p *WebhookSubscription) SetMaxLat(maxLat float64) {
+	p.Set("max_lat", maxLat)
+}
+
+func (p *WebhookSubscription) MinLon() float64 {
+	return p.GetFloat("min_lon")
+}
+
+func (p *WebhookSubscription) SetMinLon(minLon float64) {
+	p.Set("min_lon", minLon)
+}
+
+func (p *WebhookSubscription) MaxLon() float64 {
+	return p.GetFloat("max_lon")
+}
+
+func (p *WebhookSubscription) SetMaxLon(maxLon float64) {
+	p.Set("max_lon", maxLon)
+}
+
+func (p *WebhookSubscription) PointLat() float64 {
+	return p.GetFloat("point_lat")
+}
+
+func (p *WebhookSubscription) SetPointLat(pointLat float64) {
+	p.Set("point_lat", pointLat)
+}
+
+func (p *WebhookSubscription) PointLon() float64 {
+	return p.GetFloat("point_lon")
+}
+
+func (p *WebhookSubscription) SetPointLon(pointLon float64) {
+	p.Set("point_lon", pointLon)
+}
+
+func (p *WebhookSubscription) MinMmi() float64 {
+	return p.GetFloat("min_mmi")
+}
+
+func (p *WebhookSubscription) SetMinMmi(minMmi float64) {
+	p.Set("min_mmi", minMmi)
+}
+
+func (p *WebhookSubscription) Active() bool {
+	return p.GetBool("active")
+}
+
+func (p *WebhookSubscription) SetActive(active bool) {
+	p.Set("active", active)
+}
+
+func (p *WebhookSubscription) ConsecutiveFailures() int {
+	return p.GetInt("consecutive_failures")
+}
+
+func (p *WebhookSubscription) SetConsecutiveFailures(consecutiveFailures int) {
+	p.Set("consecutive_failures", consecutiveFailures)
+}
+
+func (p *WebhookSubscription) DisabledReason() string {
+	return p.GetString("disabled_reason")
+}
+
+func (p *WebhookSubscription) SetDisabledReason(disabledReason string) {
+	p.Set("disabled_reason", disabledReason)
+}
+
+func (p *WebhookSubscription) LastSuccessAt() types.DateTime {
+	return p.GetDateTime("last_success_at")
+}
+
+func (p *WebhookSubscription) SetLastSuccessAt(lastSuccessAt types.DateTime) {
+	p.Set("last_success_at", lastSuccessAt)
+}
+
+func (p *WebhookSubscription) LastFailureAt() types.DateTime {
+	return p.GetDateTime("last_failure_at")
+}
+
+func (p *WebhookSubscription) SetLastFailureAt(lastFailureAt types.DateTime) {
+	p.Set("last_failure_at", lastFailureAt)
+}
+
+func (p *WebhookSubscription) Created() types.DateTime {
+	return p.GetDateTime("created")
+}
+
+func (p *WebhookSubscription) SetCreated(created types.DateTime) {
+	p.Set("created", created)
+}
+
+func (p *WebhookSubscription) Updated() types.DateTime {
+	return p.GetDateTime("updated")
+}
+
+func (p *WebhookSubscription) SetUpdated(updated types.DateTime) {
+	p.Set("updated", updated)
+}
+
+type SubscriberLocation struct {
+	core.BaseRecordProxy
+}
+
+func (p *SubscriberLocation) CollectionName() string {
+	return "subscriber_location"
+}
+
+func (p *SubscriberLocation) Subscriber() *UserNotify {
+	var proxy *UserNotify
+	if rel := p.ExpandedOne("subscriber"); rel != nil {
+		proxy = &UserNotify{}
+		proxy.Record = rel
+	}
+	return proxy
+}
+
+func (p *SubscriberLocation) SetSubscriber(subscriber *UserNotify) {
+	var id string
+	if subscriber != nil {
+		id = subscriber.Id
+	}
+	p.Record.Set("subscriber", id)
+	e := p.Expand()
+	if subscriber != nil {
+		e["subscriber"] = subscriber.Record
+	} else {
+		delete(e, "subscriber")
+	}
+	p.SetExpand(e)
+}
+
+func (p *SubscriberLocation) Label() string {
+	return p.GetString("label")
+}
+
+func (p *SubscriberLocation) SetLabel(label string) {
+	p.Set("label", label)
+}
+
+func (p *SubscriberLocation) Lintang() string {
+	return p.GetString("lintang")
+}
+
+func (p *SubscriberLocation) SetLintang(lintang string) {
+	p.Set("lintang", lintang)
+}
+
+func (p *SubscriberLocation) Bujur() string {
+	return p.GetString("bujur")
+}
+
+func (p *SubscriberLocation) SetBujur(bujur string) {
+	p.Set("bujur", bujur)
+}
+
+func (p *SubscriberLocation) Created() types.DateTime {
+	return p.GetDateTime("created")
+}
+
+func (p *SubscriberLocation) SetCreated(created types.DateTime) {
+	p.Set("created", created)
+}
+
+func (p *SubscriberLocation) Updated() types.DateTime {
+	return p.GetDateTime("updated")
+}
+
+func (p *SubscriberLocation) SetUpdated(updated types.DateTime) {
+	p.Set("updated", updated)
+}
+
+type LanguageSelectType int
+
+const (
+	LanguageId LanguageSelectType = iota
+	LanguageEn
+)
+
+var zzLanguageSelectTypeSelectNameMap = map[string]LanguageSelectType{
+	"id": 0,
+	"en": 1,
+}
+var zzLanguageSelectTypeSelectIotaMap = map[LanguageSelectType]string{
+	0: "id",
+	1: "en",
+}
+
+type MessageTemplate struct {
+	core.BaseRecordProxy
+}
+
+func (p *MessageTemplate) CollectionName() string {
+	return "message_template"
+}
+
+func (p *MessageTemplate) Key() string {
+	return p.GetString("key")
+}
+
+func (p *MessageTemplate) SetKey(key string) {
+	p.Set("key", key)
+}
+
+func (p *MessageTemplate) Channel() string {
+	return p.GetString("channel")
+}
+
+func (p *MessageTemplate) SetChannel(channel string) {
+	p.Set("channel", channel)
+}
+
+func (p *MessageTemplate) Tier() string {
+	return p.GetString("tier")
+}
+
+func (p *MessageTemplate) SetTier(tier string) {
+	p.Set("tier", tier)
+}
+
+func (p *MessageTemplate) Language() LanguageSelectType {
+	option := p.GetString("language")
+	i, ok := zzLanguageSelectTypeSelectNameMap[option]
+	if !ok {
+		panic("Unknown select value")
+	}
+	return i
+}
+
+func (p *MessageTemplate) SetLanguage(language LanguageSelectType) {
+	i, ok := zzLanguageSelectTypeSelectIotaMap[language]
+	if !ok {
+		panic("Unknown select value")
+	}
+	p.Set("language", i)
+}
+
+func (p *MessageTemplate) Body() string {
+	return p.GetString("body")
+}
+
+func (p *MessageTemplate) SetBody(body string) {
+	p.Set("body", body)
+}
+
+func (p *MessageTemplate) Created() types.DateTime {
+	return p.GetDateTime("created")
+}
+
+func (p *MessageTemplate) SetCreated(created types.DateTime) {
+	p.Set("created", created)
+}
+
+func (p *MessageTemplate) Updated() types.DateTime {
+	return p.GetDateTime("updated")
+}
+
+func (p *MessageTemplate) SetUpdated(updated types.DateTime) {
+	p.Set("updated", updated)
+}
+
+type ShakingSelectType int
+
+const (
+	ShakingWeak ShakingSelectType = iota
+	ShakingModerate
+	ShakingStrong
+	ShakingViolent
+)
+
+var zzShakingSelectTypeSelectNameMap = map[string]ShakingSelectType{
+	"weak":     0,
+	"moderate": 1,
+	"strong":   2,
+	"violent":  3,
+}
+var zzShakingSelectTypeSelectIotaMap = map[ShakingSelectType]string{
+	0: "weak",
+	1: "moderate",
+	2: "strong",
+	3: "violent",
+}
+
+type EffectSelectType int
+
+const (
+	EffectNone EffectSelectType = iota
+	EffectSwinging
+	EffectFalling
+	EffectUnsteady
+	EffectDamage
+)
+
+var zzEffectSelectTypeSelectNameMap = map[string]EffectSelectType{
+	"none":     0,
+	"swinging": 1,
+	"falling":  2,
+	"unsteady": 3,
+	"damage":   4,
+}
+var zzEffectSelectTypeSelectIotaMap = map[EffectSelectType]string{
+	0: "none",
+	1: "swinging",
+	2: "falling",
+	3: "unsteady",
+	4: "damage",
+}
+
+type FeltReport struct {
+	core.BaseRecordProxy
+}
+
+func (p *FeltReport) CollectionName() string {
+	return "felt_report"
+}
+
+func (p *FeltReport) Earthquake() *Earthquake {
+	var proxy *Earthquake
+	if rel := p.ExpandedOne("earthquake"); rel != nil {
+		proxy = &Earthquake{}
+		proxy.Record = rel
+	}
+	return proxy
+}
+
+func (p *FeltReport) SetEarthquake(earthquake *Earthquake) {
+	var id string
+	if earthquake != nil {
+		id = earthquake.Id
+	}
+	p.Record.Set("earthquake", id)
+	e := p.Expand()
+	if earthquake != nil {
+		e["earthquake"] = earthquake.Record
+	} else {
+		delete(e, "earthquake")
+	}
+	p.SetExpand(e)
+}
+
+func (p *FeltReport) Subscriber() *UserNotify {
+	var proxy *UserNotify
+	if rel := p.ExpandedOne("subscriber"); rel != nil {
+		proxy = &UserNotify{}
+		proxy.Record = rel
+	}
+	return proxy
+}
+
+func (p *FeltReport) SetSubscriber(subscriber *UserNotify) {
+	var id string
+	if subscriber != nil {
+		id = subscriber.Id
+	}
+	p.Record.Set("subscriber", id)
+	e := p.Expand()
+	if subscriber != nil {
+		e["subscriber"] = subscriber.Record
+	} else {
+		delete(e, "subscriber")
+	}
+	p.SetExpand(e)
+}
+
+func (p *FeltReport) Felt() bool {
+	return p.GetBool("felt")
+}
+
+func (p *FeltReport) SetFelt(felt bool) {
+	p.Set("felt", felt)
+}
+
+func (p *FeltReport) Shaking() ShakingSelectType {
+	option := p.GetString("shaking")
+	i, ok := zzShakingSelectTypeSelectNameMap[option]
+	if !ok {
+		panic("Unknown select value")
+	}
+	return i
+}
+
+func (p *FeltReport) SetShaking(shaking ShakingSelectType) {
+	i, ok := zzShakingSelectTypeSelectIotaMap[shaking]
+	if !ok {
+		panic("Unknown select value")
+	}
+	p.Set("shaking", i)
+}
+
+func (p *FeltReport) Effect() EffectSelectType {
+	option := p.GetString("effect")
+	i, ok := zzEffectSelectTypeSelectNameMap[option]
+	if !ok {
+		panic("Unknown select value")
+	}
+	return i
+}
+
+func (p *FeltReport) SetEffect(effect EffectSelectType) {
+	i, ok := zzEffectSelectTypeSelectIotaMap[effect]
+	if !ok {
+		panic("Unknown select value")
+	}
+	p.Set("effect", i)
+}
+
+func (p *FeltReport) Mmi() float64 {
+	return p.GetFloat("mmi")
+}
+
+func (p *FeltReport) SetMmi(mmi float64) {
+	p.Set("mmi", mmi)
+}
+
+func (p *FeltReport) Lat() float64 {
+	return p.GetFloat("lat")
+}
+
+func (p *FeltReport) SetLat(lat float64) {
+	p.Set("lat", lat)
+}
+
+func (p *FeltReport) Lon() float64 {
+	return p.GetFloat("lon")
+}
+
+func (p *FeltReport) SetLon(lon float64) {
+	p.Set("lon", lon)
+}
+
+func (p *FeltReport) Geohash() string {
+	return p.GetString("geohash")
+}
+
+func (p *FeltReport) SetGeohash(geohash string) {
+	p.Set("geohash", geohash)
+}
+
+func (p *FeltReport) Created() types.DateTime {
+	return p.GetDateTime("created")
+}
+
+func (p *FeltReport) SetCreated(created types.DateTime) {
+	p.Set("created", created)
+}
+
+func (p *FeltReport) Updated() types.DateTime {
+	return p.GetDateTime("updated")
+}
+
+func (p *FeltReport) SetUpdated(updated types.DateTime) {
+	p.Set("updated", updated)
+}
+
+type FeltIntensity struct {
+	core.BaseRecordProxy
+}
+
+func (p *FeltIntensity) CollectionName() string {
+	return "felt_intensity"
+}
+
+func (p *FeltIntensity) Earthquake() *Earthquake {
+	var proxy *Earthquake
+	if rel := p.ExpandedOne("earthquake"); rel != nil {
+		proxy = &Earthquake{}
+		proxy.Record = rel
+	}
+	return proxy
+}
+
+func (p *FeltIntensity) SetEarthquake(earthquake *Earthquake) {
+	var id string
+	if earthquake != nil {
+		id = earthquake.Id
+	}
+	p.Record.Set("earthquake", id)
+	e := p.Expand()
+	if earthquake != nil {
+		e["earthquake"] = earthquake.Record
+	} else {
+		delete(e, "earthquake")
+	}
+	p.SetExpand(e)
+}
+
+func (p *FeltIntensity) Geohash() string {
+	return p.GetString("geohash")
+}
+
+func (p *FeltIntensity) SetGeohash(geohash string) {
+	p.Set("geohash", geohash)
+}
+
+func (p *FeltIntensity) Lat() float64 {
+	return p.GetFloat("lat")
+}
+
+func (p *FeltIntensity) SetLat(lat float64) {
+	p.Set("lat", lat)
+}
+
+func (p *FeltIntensity) Lon() float64 {
+	return p.GetFloat("lon")
+}
+
+func (p *FeltIntensity) SetLon(lon float64) {
+	p.Set("lon", lon)
+}
+
+func (p *FeltIntensity) Mmi() float64 {
+	return p.GetFloat("mmi")
+}
+
+func (p *FeltIntensity) SetMmi(mmi float64) {
+	p.Set("mmi", mmi)
+}
+
+func (p *FeltIntensity) Reports() int {
+	return p.GetInt("reports")
+}
+
+func (p *FeltIntensity) SetReports(reports int) {
+	p.Set("reports", reports)
+}
+
+func (p *FeltIntensity) Created() types.DateTime {
+	return p.GetDateTime("created")
+}
+
+func (p *FeltIntensity) SetCreated(created types.DateTime) {
+	p.Set("created", created)
+}
+
+func (p *FeltIntensity) Updated() types.DateTime {
+	return p.GetDateTime("updated")
+}
+
+func (p *FeltIntensity) SetUpdated(updated types.DateTime) {
+	p.Set("updated", updated)
+}
+
+type SafetyGroup struct {
+	core.BaseRecordProxy
+}
+
+func (p *SafetyGroup) CollectionName() string {
+	return "safety_group"
+}
+
+func (p *SafetyGroup) Name() string {
+	return p.GetString("name")
+}
+
+func (p *SafetyGroup) SetName(name string) {
+	p.Set("name", name)
+}
+
+func (p *SafetyGroup) Owner() *Users {
+	var proxy *Users
+	if rel := p.ExpandedOne("owner"); rel != nil {
+		proxy = &Users{}
+		proxy.Record = rel
+	}
+	return proxy
+}
+
+func (p *SafetyGroup) SetOwner(owner *Users) {
+	var id string
+	if owner != nil {
+		id = owner.Id
+	}
+	p.Record.Set("owner", id)
+	e := p.Expand()
+	if owner != nil {
+		e["owner"] = owner.Record
+	} else {
+		delete(e, "owner")
+	}
+	p.SetExpand(e)
+}
+
+func (p *SafetyGroup) InviteCode() string {
+	return p.GetString("invite_code")
+}
+
+func (p *SafetyGroup) SetInviteCode(inviteCode string) {
+	p.Set("invite_code", inviteCode)
+}
+
+func (p *SafetyGroup) Created() types.DateTime {
+	return p.GetDateTime("created")
+}
+
+func (p *SafetyGroup) SetCreated(created types.DateTime) {
+	p.Set("created", created)
+}
+
+func (p *SafetyGroup) Updated() types.DateTime {
+	return p.GetDateTime("updated")
+}
+
+func (p *SafetyGroup) SetUpdated(updated types.DateTime) {
+	p.Set("updated", updated)
+}
+
+type SafetyGroupMember struct {
+	core.BaseRecordProxy
+}
+
+func (p *SafetyGroupMember) CollectionName() string {
+	return "safety_group_member"
+}
+
+func (p *SafetyGroupMember) SafetyGroup() *SafetyGroup {
+	var proxy *SafetyGroup
+	if rel := p.ExpandedOne("safety_group"); rel != nil {
+		proxy = &SafetyGroup{}
+		proxy.Record = rel
+	}
+	return proxy
+}
+
+func (p *SafetyGroupMember) SetSafetyGroup(safetyGroup *SafetyGroup) {
+	var id string
+	if safetyGroup != nil {
+		id = safetyGroup.Id
+	}
+	p.Record.Set("safety_group", id)
+	e := p.Expand()
+	if safetyGroup != nil {
+		e["safety_group"] = safetyGroup.Record
+	} else {
+		delete(e, "safety_group")
+	}
+	p.SetExpand(e)
+}
+
+func (p *SafetyGroupMember) Subscriber() *UserNotify {
+	var proxy *UserNotify
+	if rel := p.ExpandedOne("subscriber"); rel != nil {
+		proxy = &UserNotify{}
+		proxy.Record = rel
+	}
+	return proxy
+}
+
+func (p *SafetyGroupMember) SetSubscriber(subscriber *UserNotify) {
+	var id string
+	if subscriber != nil {
+		id = subscriber.Id
+	}
+	p.Record.Set("subscriber", id)
+	e := p.Expand()
+	if subscriber != nil {
+		e["subscriber"] = subscriber.Record
+	} else {
+		delete(e, "subscriber")
+	}
+	p.SetExpand(e)
+}
+
+func (p *SafetyGroupMember) Name() string {
+	return p.GetString("name")
+}
+
+func (p *SafetyGroupMember) SetName(name string) {
+	p.Set("name", name)
+}
+
+func (p *SafetyGroupMember) Created() types.DateTime {
+	return p.GetDateTime("created")
+}
+
+func (p *SafetyGroupMember) SetCreated(created types.DateTime) {
+	p.Set("created", created)
+}
+
+func (p *SafetyGroupMember) Updated() types.DateTime {
+	return p.GetDateTime("updated")
+}
+
+func (p *SafetyGroupMember) SetUpdated(updated types.DateTime) {
+	p.Set("updated", updated)
+}
+
+type CheckinStatusSelectType int
+
+const (
+	CheckinPending CheckinStatusSelectType = iota
+	CheckinSafe
+	CheckinNeedHelp
+)
+
+var zzCheckinStatusSelectTypeSelectNameMap = map[string]CheckinStatusSelectType{
+	"pending":   0,
+	"safe":      1,
+	"need_help": 2,
+}
+var zzCheckinStatusSelectTypeSelectIotaMap = map[CheckinStatusSelectType]string{
+	0: "pending",
+	1: "safe",
+	2: "need_help",
+}
+
+type SafetyCheckin struct {
+	core.BaseRecordProxy
+}
+
+func (p *SafetyCheckin) CollectionName() string {
+	return "safety_checkin"
+}
+
+func (p *SafetyCheckin) Earthquake() *Earthquake {
+	var proxy *Earthquake
+	if rel := p.ExpandedOne("earthquake"); rel != nil {
+		proxy = &Earthquake{}
+		proxy.Record = rel
+	}
+	return proxy
+}
+
+func (p *SafetyCheckin) SetEarthquake(earthquake *Earthquake) {
+	var id string
+	if earthquake != nil {
+		id = earthquake.Id
+	}
+	p.Record.Set("earthquake", id)
+	e := p.Expand()
+	if earthquake != nil {
+		e["earthquake"] = earthquake.Record
+	} else {
+		delete(e, "earthquake")
+	}
+	p.SetExpand(e)
+}
+
+func (p *SafetyCheckin) Subscriber() *UserNotify {
+	var proxy *UserNotify
+	if rel := p.ExpandedOne("subscriber"); rel != nil {
+		proxy = &UserNotify{}
+		proxy.Record = rel
+	}
+	return proxy
+}
+
+func (p *SafetyCheckin) SetSubscriber(subscriber *UserNotify) {
+	var id string
+	if subscriber != nil {
+		id = subscriber.Id
+	}
+	p.Record.Set("subscriber", id)
+	e := p.Expand()
+	if subscriber != nil {
+		e["subscriber"] = subscriber.Record
+	} else {
+		delete(e, "subscriber")
+	}
+	p.SetExpand(e)
+}
+
+func (p *SafetyCheckin) Status() CheckinStatusSelectType {
+	option := p.GetString("status")
+	i, ok := zzCheckinStatusSelectTypeSelectNameMap[option]
+	if !ok {
+		panic("Unknown select value")
+	}
+	return i
+}
+
+func (p *SafetyCheckin) SetStatus(status CheckinStatusSelectType) {
+	i, ok := zzCheckinStatusSelectTypeSelectIotaMap[status]
+	if !ok {
+		panic("Unknown select value")
+	}
+	p.Set("status", i)
+}
+
+func (p *SafetyCheckin) Token() string {
+	return p.GetString("token")
+}
+
+func (p *SafetyCheckin) SetToken(token string) {
+	p.Set("token", token)
+}
+
+func (p *SafetyCheckin) Mmi() float64 {
+	return p.GetFloat("mmi")
+}
+
+func (p *SafetyCheckin) SetMmi(mmi float64) {
+	p.Set("mmi", mmi)
+}
+
+func (p *SafetyCheckin) RespondedAt() types.DateTime {
+	return p.GetDateTime("responded_at")
+}
+
+func (p *SafetyCheckin) SetRespondedAt(respondedAt types.DateTime) {
+	p.Set("responded_at", respondedAt)
+}
+
+func (p *SafetyCheckin) Reminders() int {
+	return p.GetInt("reminders")
+}
+
+func (p *SafetyCheckin) SetReminders(reminders int) {
+	p.Set("reminders", reminders)
+}
+
+func (p *SafetyCheckin) RemindedAt() types.DateTime {
+	return p.GetDateTime("reminded_at")
+}
+
+func (p *SafetyCheckin) SetRemindedAt(remindedAt types.DateTime) {
+	p.Set("reminded_at", remindedAt)
+}
+
+func (p *SafetyCheckin) Created() types.DateTime {
+	return p.GetDateTime("created")
+}
+
+func (p *SafetyCheckin) SetCreated(created types.DateTime) {
+	p.Set("created", created)
+}
+
+func (p *SafetyCheckin) Updated() types.DateTime {
+	return p.GetDateTime("updated")
+}
+
+func (p *SafetyCheckin) SetUpdated(updated types.DateTime) {
+	p.Set("updated", updated)
+}
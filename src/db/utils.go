@@ -8,7 +8,7 @@ import (
 )
 
 type Proxy interface {
-	Users | Earthquake | IotDevice | HistoryIot | UserHistory | UserNotify | ViewGempa
+	Users | Earthquake | IotDevice | HistoryIot | UserHistory | UserNotify | ViewGempa | WorkerState | EarthquakeRevision | NotificationOutbox | NotificationAttempt | PushToken | WebhookSubscription | SubscriberLocation | MessageTemplate | FeltReport | FeltIntensity | SafetyGroup | SafetyGroupMember | SafetyCheckin
 }
 
 // This interface constrains a type parameter of
@@ -99,11 +99,70 @@ type RelationField struct {
 //	 -> collection names that it is related to
 //	  -> list of fields that contain the relation values
 var Relations = map[string]map[string][]RelationField{
+	"earthquake_revision": {
+		"earthquake": {
+			{"earthquake", false},
+		},
+	},
+	"felt_intensity": {
+		"earthquake": {
+			{"earthquake", false},
+		},
+	},
+	"felt_report": {
+		"earthquake": {
+			{"earthquake", false},
+		},
+		"user_notify": {
+			{"subscriber", false},
+		},
+	},
 	"history_iot": {
 		"iot_device": {
 			{"device", false},
 		},
 	},
+	"notification_attempt": {
+		"notification_outbox": {
+			{"outbox", false},
+		},
+	},
+	"notification_outbox": {
+		"earthquake": {
+			{"earthquake", false},
+		},
+	},
+	"push_token": {
+		"users": {
+			{"user", false},
+		},
+	},
+	"safety_checkin": {
+		"earthquake": {
+			{"earthquake", false},
+		},
+		"user_notify": {
+			{"subscriber", false},
+		},
+	},
+	"safety_group": {
+		"users": {
+			{"owner", false},
+		},
+	},
+	"safety_group_member": {
+		"safety_group": {
+			{"safety_group", false},
+		},
+		"user_notify": {
+			{"subscriber", false},
+		},
+	},
+	"subscriber_location": {
+		"user_notify": {
+			{"subscriber", false},
+		},
+	},
 	"user_history": {
 		"users": {
 			{"user_id", false},
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"encoding/json"
 	"time"
 )
 
@@ -15,6 +16,17 @@ type Infogempa struct {
 	Gempa Gempa `json:"gempa"`
 }
 
+// ResponseGempaList represents the list feeds of the BMKG API
+// (gempaterkini.json and gempadirasakan.json), which carry an array of events.
+type ResponseGempaList struct {
+	Infogempa InfogempaList `json:"Infogempa"`
+}
+
+// InfogempaList represents the information about a list of earthquakes.
+type InfogempaList struct {
+	Gempa []Gempa `json:"gempa"`
+}
+
 // Gempa represents the details of the earthquake.
 type Gempa struct {
 	Tanggal     string    `json:"Tanggal"`     // Date of the earthquake
@@ -30,3 +42,30 @@ type Gempa struct {
 	Dirasakan   string    `json:"Dirasakan"`   // Felt impact of the earthquake
 	Shakemap    string    `json:"Shakemap"`    // Shakemap of the earthquake
 }
+
+// DecodeAutoGempa decodes autogempa.json, which only holds the latest event.
+func DecodeAutoGempa(data []byte) ([]Gempa, error) {
+	var response ResponseBmkgAPI
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, err
+	}
+	return []Gempa{response.Infogempa.Gempa}, nil
+}
+
+// DecodeGempaTerkini decodes gempaterkini.json, the list of the latest M5+ events.
+func DecodeGempaTerkini(data []byte) ([]Gempa, error) {
+	var response ResponseGempaList
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, err
+	}
+	return response.Infogempa.Gempa, nil
+}
+
+// DecodeGempaDirasakan decodes gempadirasakan.json, the list of felt events.
+func DecodeGempaDirasakan(data []byte) ([]Gempa, error) {
+	var response ResponseGempaList
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, err
+	}
+	return response.Infogempa.Gempa, nil
+}
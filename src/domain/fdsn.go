@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FDSNEvent represents one row of the FDSN event web service text format, as served by EMSC:
+// EventID|Time|Latitude|Longitude|Depth/km|Author|Catalog|Contributor|ContributorID|MagType|Magnitude|MagAuthor|EventLocationName
+type FDSNEvent struct {
+	EventID           string    // Event id of the agency
+	Time              time.Time // Origin time in UTC
+	Latitude          float64   // Latitude of the epicenter
+	Longitude         float64   // Longitude of the epicenter
+	DepthKm           float64   // Depth in km
+	Author            string    // Agency that computed the location
+	MagType           string    // Magnitude type, e.g. mb or Mw
+	Magnitude         float64   // Magnitude of the earthquake
+	EventLocationName string    // Flinn-Engdahl region name
+}
+
+// fdsnTimeLayouts are the time layouts seen in FDSN text responses
+var fdsnTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02T15:04:05",
+}
+
+// DecodeFDSNText decodes the FDSN event text format. Header and comment lines start with '#'.
+// Rows that cannot be parsed, e.g. without depth or magnitude, are skipped so one bad row
+// does not drop the whole response.
+func DecodeFDSNText(data []byte) ([]FDSNEvent, error) {
+	var events []FDSNEvent
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		event, err := parseFDSNRow(line)
+		if err != nil {
+			log.Printf("Skipping invalid FDSN row: %v", err)
+			continue
+		}
+
+		events = append(events, event)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// parseFDSNRow parses one "|" separated event row
+func parseFDSNRow(line string) (FDSNEvent, error) {
+	cols := strings.Split(line, "|")
+	if len(cols) < 13 {
+		return FDSNEvent{}, fmt.Errorf("invalid FDSN row %q", line)
+	}
+
+	event := FDSNEvent{
+		EventID:           cols[0],
+		Author:            cols[5],
+		MagType:           cols[9],
+		EventLocationName: cols[12],
+	}
+
+	var err error
+	if event.Time, err = parseFDSNTime(cols[1]); err != nil {
+		return event, err
+	}
+	if event.Latitude, err = strconv.ParseFloat(cols[2], 64); err != nil {
+		return event, fmt.Errorf("invalid FDSN latitude %q: %w", cols[2], err)
+	}
+	if event.Longitude, err = strconv.ParseFloat(cols[3], 64); err != nil {
+		return event, fmt.Errorf("invalid FDSN longitude %q: %w", cols[3], err)
+	}
+	if event.DepthKm, err = strconv.ParseFloat(cols[4], 64); err != nil {
+		return event, fmt.Errorf("invalid FDSN depth %q: %w", cols[4], err)
+	}
+	if event.Magnitude, err = strconv.ParseFloat(cols[10], 64); err != nil {
+		return event, fmt.Errorf("invalid FDSN magnitude %q: %w", cols[10], err)
+	}
+
+	return event, nil
+}
+
+// parseFDSNTime parses the origin time, which is UTC with or without the Z suffix
+func parseFDSNTime(value string) (time.Time, error) {
+	for _, layout := range fdsnTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid FDSN time %q", value)
+}
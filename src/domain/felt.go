@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"slices"
+)
+
+// Answers of the simplified "did you feel it?" questionnaire, see the shaking and effect
+// selects of felt_report
+var (
+	// ShakingLevels answer "how strong was the shaking?"
+	ShakingLevels = []string{"weak", "moderate", "strong", "violent"}
+	// Effects answer "what happened around you?"
+	Effects = []string{"none", "swinging", "falling", "unsteady", "damage"}
+)
+
+// intensity of each answer, the report takes the strongest one
+var (
+	shakingMMI = map[string]float64{"weak": 3, "moderate": 4, "strong": 5, "violent": 6}
+	effectMMI  = map[string]float64{"none": 0, "swinging": 4, "falling": 5, "unsteady": 6, "damage": 7}
+)
+
+// ErrInvalidFeltReport is returned for answers outside the questionnaire
+var ErrInvalidFeltReport = errors.New("invalid felt report")
+
+// FeltAnswers are the answers of one person to the questionnaire. Shaking and Effect are
+// only asked when the earthquake was felt.
+type FeltAnswers struct {
+	Felt    bool   `json:"felt"`
+	Shaking string `json:"shaking,omitempty"`
+	Effect  string `json:"effect,omitempty"`
+}
+
+// Validate checks the answers against the questionnaire
+func (r FeltAnswers) Validate() error {
+	if r.Shaking != "" && !slices.Contains(ShakingLevels, r.Shaking) {
+		return ErrInvalidFeltReport
+	}
+	if r.Effect != "" && !slices.Contains(Effects, r.Effect) {
+		return ErrInvalidFeltReport
+	}
+	if !r.Felt && (r.Shaking != "" || r.Effect != "") {
+		return ErrInvalidFeltReport
+	}
+	return nil
+}
+
+// MMI converts the answers to an intensity: I when not felt, II when felt without more
+// detail, otherwise the strongest of the shaking and the observed effect
+func (r FeltAnswers) MMI() float64 {
+	if !r.Felt {
+		return 1
+	}
+	return math.Max(2, math.Max(shakingMMI[r.Shaking], effectMMI[r.Effect]))
+}
+
+// FeltCell is the community intensity of one geohash cell: the mean intensity of the
+// reports made inside it
+type FeltCell struct {
+	Geohash string
+	Lat     float64
+	Lon     float64
+	MMI     float64
+	Reports int
+}
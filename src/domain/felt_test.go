@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFeltAnswersMMI(t *testing.T) {
+	tests := []struct {
+		name    string
+		answers FeltAnswers
+		want    float64
+	}{
+		{"not felt", FeltAnswers{}, 1},
+		{"felt without detail", FeltAnswers{Felt: true}, 2},
+		{"felt, nothing happened", FeltAnswers{Felt: true, Effect: "none"}, 2},
+		{"weak shaking only", FeltAnswers{Felt: true, Shaking: "weak"}, 3},
+		{"swinging only", FeltAnswers{Felt: true, Effect: "swinging"}, 4},
+		{"damage only", FeltAnswers{Felt: true, Effect: "damage"}, 7},
+		// the stronger of both answers counts
+		{"shaking stronger than effect", FeltAnswers{Felt: true, Shaking: "violent", Effect: "swinging"}, 6},
+		{"effect stronger than shaking", FeltAnswers{Felt: true, Shaking: "weak", Effect: "unsteady"}, 6},
+		{"shaking and effect equal", FeltAnswers{Felt: true, Shaking: "strong", Effect: "falling"}, 5},
+		{"weak shaking, no effect", FeltAnswers{Felt: true, Shaking: "weak", Effect: "none"}, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.answers.MMI(); got != tt.want {
+				t.Errorf("%+v.MMI() = %v, want %v", tt.answers, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFeltAnswersValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		answers FeltAnswers
+		err     error
+	}{
+		{"not felt", FeltAnswers{}, nil},
+		{"felt without detail", FeltAnswers{Felt: true}, nil},
+		{"felt with every answer", FeltAnswers{Felt: true, Shaking: "moderate", Effect: "falling"}, nil},
+		{"unknown shaking", FeltAnswers{Felt: true, Shaking: "huge"}, ErrInvalidFeltReport},
+		{"unknown effect", FeltAnswers{Felt: true, Effect: "collapse"}, ErrInvalidFeltReport},
+		{"shaking case matters", FeltAnswers{Felt: true, Shaking: "Weak"}, ErrInvalidFeltReport},
+		// details of an earthquake that was not felt contradict the answer
+		{"not felt with shaking", FeltAnswers{Shaking: "weak"}, ErrInvalidFeltReport},
+		{"not felt with effect", FeltAnswers{Effect: "none"}, ErrInvalidFeltReport},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.answers.Validate(); !errors.Is(err, tt.err) {
+				t.Errorf("%+v.Validate() = %v, want %v", tt.answers, err, tt.err)
+			}
+		})
+	}
+}
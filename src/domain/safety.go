@@ -0,0 +1,36 @@
+package domain
+
+import "time"
+
+// Check-in statuses, see the status select of safety_checkin
+const (
+	CheckinPending  = "pending"
+	CheckinSafe     = "safe"
+	CheckinNeedHelp = "need_help"
+)
+
+// IsCheckinAnswer reports whether status is an answer a member can give
+func IsCheckinAnswer(status string) bool {
+	return status == CheckinSafe || status == CheckinNeedHelp
+}
+
+// CheckinMember is the check-in of one group member. Status is empty when the member
+// was not affected by the earthquake.
+type CheckinMember struct {
+	Member      string     `json:"member"`
+	Name        string     `json:"name"`
+	Status      string     `json:"status"`
+	MMI         float64    `json:"mmi,omitempty"`
+	RespondedAt *time.Time `json:"responded_at,omitempty"`
+}
+
+// CheckinRollUp summarizes the check-ins of a group after one earthquake
+type CheckinRollUp struct {
+	Earthquake  string          `json:"earthquake"`
+	Responded   int             `json:"responded"`
+	Unresponded int             `json:"unresponded"`
+	Safe        int             `json:"safe"`
+	NeedHelp    int             `json:"need_help"`
+	NotAffected int             `json:"not_affected"`
+	Members     []CheckinMember `json:"members"`
+}
@@ -63,21 +63,29 @@ type Users struct {
 type Earthquake struct {
 	// collection-name: earthquake
 	// system: id
-	Id          string
-	Coordinates string
-	Lintang     string
-	Bujur       string
-	Magnitude   string
-	Kedalaman   string
-	Wilayah     string
-	Potensi     string
-	Dirasakan   string
-	Shakemap    string
-	Jam         string
-	Tanggal     string
-	DateTime    types.DateTime
-	created     types.DateTime
-	updated     types.DateTime
+	Id            string
+	Coordinates   string
+	Lintang       string
+	Bujur         string
+	MagnitudeText string
+	Kedalaman     string
+	Wilayah       string
+	Potensi       string
+	Dirasakan     string
+	Shakemap      string
+	Jam           string
+	Tanggal       string
+	DateTime      types.DateTime
+	fingerprint   string
+	sources       types.JSONRaw
+	magnitude     float64
+	depth_km      float64
+	lat           float64
+	lon           float64
+	origin_time   types.DateTime
+	revision      int
+	created       types.DateTime
+	updated       types.DateTime
 }
 
 type IotDevice struct {
@@ -119,10 +127,18 @@ type UserNotify struct {
 	identifier string
 	lintang    string
 	bujur      string
-	// select: TypeSelectType(wa, telegram)
-	type_   int
-	created types.DateTime
-	updated types.DateTime
+	// select: TypeSelectType(wa, telegram, android, email)
+	type_         int
+	min_magnitude float64
+	min_mmi       float64
+	quiet_start   string
+	quiet_end     string
+	timezone      string
+	// select: LanguageSelectType(id, en)[LanguageId, LanguageEn]
+	language  int
+	radius_km float64
+	created   types.DateTime
+	updated   types.DateTime
 }
 
 type ViewGempa struct {
@@ -131,3 +147,205 @@ type ViewGempa struct {
 	Id      string
 	created types.DateTime
 }
+
+type WorkerState struct {
+	// collection-name: worker_state
+	// system: id
+	Id      string
+	key     string
+	value   types.JSONRaw
+	created types.DateTime
+	updated types.DateTime
+}
+
+type EarthquakeRevision struct {
+	// collection-name: earthquake_revision
+	// system: id
+	Id          string
+	earthquake  *Earthquake
+	revision    int
+	source      string
+	magnitude   float64
+	depth_km    float64
+	lat         float64
+	lon         float64
+	origin_time types.DateTime
+	fingerprint string
+	created     types.DateTime
+	updated     types.DateTime
+}
+
+type NotificationOutbox struct {
+	// collection-name: notification_outbox
+	// system: id
+	Id         string
+	dedupe_key string
+	channel    string
+	recipient  string
+	earthquake *Earthquake
+	payload    types.JSONRaw
+	// select: OutboxStatusSelectType(pending, sending, sent, failed, expired)[OutboxPending, OutboxSending, OutboxSent, OutboxFailed, OutboxExpired]
+	status              int
+	attempts            int
+	next_attempt_at     types.DateTime
+	expires_at          types.DateTime
+	sent_at             types.DateTime
+	last_error          string
+	mmi                 float64
+	distance_km         float64
+	provider_message_id string
+	provider_status     string
+	created             types.DateTime
+	updated             types.DateTime
+}
+
+type NotificationAttempt struct {
+	// collection-name: notification_attempt
+	// system: id
+	Id      string
+	outbox  *NotificationOutbox
+	attempt int
+	// select: AttemptStatusSelectType(sent, failed)[AttemptSent, AttemptFailed]
+	status      int
+	error       string
+	duration_ms int
+	created     types.DateTime
+	updated     types.DateTime
+}
+
+type PushToken struct {
+	// collection-name: push_token
+	// system: id
+	Id    string
+	user  *Users
+	token string
+	// select: PlatformSelectType(android, ios)[PlatformAndroid, PlatformIos]
+	platform  int
+	last_seen types.DateTime
+	created   types.DateTime
+	updated   types.DateTime
+}
+
+type WebhookSubscription struct {
+	// collection-name: webhook_subscription
+	// system: id
+	Id     string
+	name   string
+	url    string
+	secret string
+	// select: EventTypesSelectType(earthquake.created, earthquake.revised)[EarthquakeCreated, EarthquakeRevised]
+	event_types          []int
+	min_magnitude        float64
+	min_lat              float64
+	max_lat              float64
+	min_lon              float64
+	max_lon              float64
+	point_lat            float64
+	point_lon            float64
+	min_mmi              float64
+	active               bool
+	consecutive_failures int
+	disabled_reason      string
+	last_success_at      types.DateTime
+	last_failure_at      types.DateTime
+	created              types.DateTime
+	updated              types.DateTime
+}
+
+type SubscriberLocation struct {
+	// collection-name: subscriber_location
+	// system: id
+	Id         string
+	subscriber *UserNotify
+	label      string
+	lintang    string
+	bujur      string
+	created    types.DateTime
+	updated    types.DateTime
+}
+
+type MessageTemplate struct {
+	// collection-name: message_template
+	// system: id
+	Id      string
+	key     string
+	channel string
+	tier    string
+	// select: LanguageSelectType(id, en)[LanguageId, LanguageEn]
+	language int
+	body     string
+	created  types.DateTime
+	updated  types.DateTime
+}
+
+type FeltReport struct {
+	// collection-name: felt_report
+	// system: id
+	Id         string
+	earthquake *Earthquake
+	subscriber *UserNotify
+	felt       bool
+	// select: ShakingSelectType(weak, moderate, strong, violent)[ShakingWeak, ShakingModerate, ShakingStrong, ShakingViolent]
+	shaking int
+	// select: EffectSelectType(none, swinging, falling, unsteady, damage)[EffectNone, EffectSwinging, EffectFalling, EffectUnsteady, EffectDamage]
+	effect  int
+	mmi     float64
+	lat     float64
+	lon     float64
+	geohash string
+	created types.DateTime
+	updated types.DateTime
+}
+
+type FeltIntensity struct {
+	// collection-name: felt_intensity
+	// system: id
+	Id         string
+	earthquake *Earthquake
+	geohash    string
+	lat        float64
+	lon        float64
+	mmi        float64
+	reports    int
+	created    types.DateTime
+	updated    types.DateTime
+}
+
+type SafetyGroup struct {
+	// collection-name: safety_group
+	// system: id
+	Id          string
+	name        string
+	owner       *Users
+	invite_code string
+	created     types.DateTime
+	updated     types.DateTime
+}
+
+type SafetyGroupMember struct {
+	// collection-name: safety_group_member
+	// system: id
+	Id           string
+	safety_group *SafetyGroup
+	subscriber   *UserNotify
+	name         string
+	created      types.DateTime
+	updated      types.DateTime
+}
+
+type SafetyCheckin struct {
+	// collection-name: safety_checkin
+	// system: id
+	Id         string
+	earthquake *Earthquake
+	subscriber *UserNotify
+	// select: CheckinStatusSelectType(pending, safe, need_help)[CheckinPending, CheckinSafe, CheckinNeedHelp]
+	status       int
+	token        string
+	mmi          float64
+	responded_at types.DateTime
+	reminders    int
+	reminded_at  types.DateTime
+	created      types.DateTime
+	updated      types.DateTime
+}
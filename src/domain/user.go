@@ -2,3 +2,17 @@ package domain
 
 type User struct {
 }
+
+// NotifyPreferences are the alert preferences of a subscriber. A nil field is left
+// unchanged on update; an empty quiet window turns quiet hours off.
+type NotifyPreferences struct {
+	MinMagnitude *float64 `json:"min_magnitude,omitempty"`
+	MinMMI       *float64 `json:"min_mmi,omitempty"`
+	QuietStart   *string  `json:"quiet_start,omitempty"`
+	QuietEnd     *string  `json:"quiet_end,omitempty"`
+	Timezone     *string  `json:"timezone,omitempty"`
+	// Language of the messages: id or en
+	Language *string `json:"language,omitempty"`
+	// RadiusKm only alerts for quakes within this distance of a saved location, 0 is off
+	RadiusKm *float64 `json:"radius_km,omitempty"`
+}
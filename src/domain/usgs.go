@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"encoding/json"
+)
+
+// ResponseUSGS represents the GeoJSON summary feed of the USGS earthquake API.
+type ResponseUSGS struct {
+	Features []USGSFeature `json:"features"`
+}
+
+// USGSFeature represents a single earthquake of the USGS feed.
+type USGSFeature struct {
+	ID         string         `json:"id"`         // USGS event id
+	Properties USGSProperties `json:"properties"` // Event details
+	Geometry   USGSGeometry   `json:"geometry"`   // Epicenter and depth
+}
+
+// USGSProperties represents the details of a USGS earthquake.
+type USGSProperties struct {
+	Mag     float64 `json:"mag"`     // Magnitude of the earthquake
+	Place   string  `json:"place"`   // Human readable region
+	Time    int64   `json:"time"`    // Origin time in milliseconds since epoch
+	Updated int64   `json:"updated"` // Last update in milliseconds since epoch
+	URL     string  `json:"url"`     // Event page
+	Type    string  `json:"type"`    // Event type, "earthquake" for quakes
+}
+
+// USGSGeometry holds [longitude, latitude, depth in km].
+type USGSGeometry struct {
+	Coordinates []float64 `json:"coordinates"`
+}
+
+// DecodeUSGS decodes the USGS GeoJSON summary feed.
+func DecodeUSGS(data []byte) ([]USGSFeature, error) {
+	var response ResponseUSGS
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, err
+	}
+	return response.Features, nil
+}
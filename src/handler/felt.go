@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"bmkg/src/db"
+	"bmkg/src/domain"
+	"bmkg/src/repository"
+	"bmkg/src/utils"
+	"bmkg/src/utils/ngitung"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/pocketbase/pocketbase/apis"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
+)
+
+// FeltHandler collects "did you feel it?" reports and publishes the community intensity
+type FeltHandler struct {
+	feltRepo       *repository.Felt
+	subscriberRepo *repository.Subscriber
+	bmkgRepo       *repository.BMKG
+}
+
+// NewFeltHandler creates a new instance of FeltHandler
+func NewFeltHandler(feltRepo *repository.Felt, subscriberRepo *repository.Subscriber, bmkgRepo *repository.BMKG) *FeltHandler {
+	return &FeltHandler{
+		feltRepo:       feltRepo,
+		subscriberRepo: subscriberRepo,
+		bmkgRepo:       bmkgRepo,
+	}
+}
+
+// AddFeltHandler registers the felt report routes. Reporting needs an app user, the
+// community intensity is public.
+func (h *FeltHandler) AddFeltHandler(router *router.Router[*core.RequestEvent]) {
+	group := router.Group("/api/earthquakes/{id}/felt")
+	group.GET("", h.intensity)
+	group.POST("", h.report).Bind(apis.RequireAuth("users"))
+}
+
+// feltRequest is the questionnaire. Without lintang and bujur the saved place closest to
+// the epicenter is used.
+type feltRequest struct {
+	domain.FeltAnswers
+	Lintang *float64 `json:"lintang"`
+	Bujur   *float64 `json:"bujur"`
+}
+
+// feltResponse is the intensity derived from the answers
+type feltResponse struct {
+	MMI      float64 `json:"mmi"`
+	MMIRoman string  `json:"mmi_roman"`
+	Geohash  string  `json:"geohash"`
+}
+
+func (h *FeltHandler) report(e *core.RequestEvent) error {
+	var request feltRequest
+	if err := e.BindBody(&request); err != nil {
+		return e.BadRequestError("Invalid body", err)
+	}
+	if err := request.Validate(); err != nil {
+		return e.BadRequestError(err.Error(), nil)
+	}
+
+	earthquake, err := h.bmkgRepo.GetEarthquake(e.Request.PathValue("id"))
+	if errors.Is(err, sql.ErrNoRows) {
+		return e.NotFoundError("Earthquake not found", nil)
+	}
+	if err != nil {
+		log.Printf("Error loading earthquake: %v", err)
+		return e.InternalServerError("Failed to save report", nil)
+	}
+
+	subscriber, err := h.subscriberRepo.Ensure(e.Auth.Id, db.Android)
+	if err != nil {
+		log.Printf("Error loading subscriber: %v", err)
+		return e.InternalServerError("Failed to save report", nil)
+	}
+
+	var lat, lon float64
+	if request.Lintang != nil && request.Bujur != nil {
+		lat, lon = *request.Lintang, *request.Bujur
+	} else {
+		location, err := h.subscriberRepo.NearestLocation(subscriber, earthquake.Lat(), earthquake.Lon())
+		if err != nil {
+			log.Printf("Error loading locations: %v", err)
+			return e.InternalServerError("Failed to save report", nil)
+		}
+		if location == nil {
+			return e.BadRequestError("Lintang and bujur are required without a saved location", nil)
+		}
+		lat, _ = strconv.ParseFloat(location.Lintang(), 64)
+		lon, _ = strconv.ParseFloat(location.Bujur(), 64)
+	}
+
+	report, err := h.feltRepo.SaveReport(subscriber, earthquake.Id, request.FeltAnswers, lat, lon)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidLocation) {
+			return e.BadRequestError(err.Error(), nil)
+		}
+		log.Printf("Error saving felt report: %v", err)
+		return e.InternalServerError("Failed to save report", nil)
+	}
+
+	return e.JSON(http.StatusOK, feltResponse{
+		MMI:      report.Mmi(),
+		MMIRoman: ngitung.MMIRoman(report.Mmi()),
+		Geohash:  report.Geohash(),
+	})
+}
+
+// featureCollection is a GeoJSON FeatureCollection of the intensity cells
+type featureCollection struct {
+	Type     string    `json:"type"`
+	Features []feature `json:"features"`
+}
+
+type feature struct {
+	Type       string            `json:"type"`
+	Geometry   polygon           `json:"geometry"`
+	Properties intensityProperty `json:"properties"`
+}
+
+type polygon struct {
+	Type        string         `json:"type"`
+	Coordinates [][][2]float64 `json:"coordinates"`
+}
+
+type intensityProperty struct {
+	Geohash  string  `json:"geohash"`
+	MMI      float64 `json:"mmi"`
+	MMIRoman string  `json:"mmi_roman"`
+	Reports  int     `json:"reports"`
+}
+
+// intensity returns the community intensity cells as GeoJSON polygons
+func (h *FeltHandler) intensity(e *core.RequestEvent) error {
+	cells, err := h.feltRepo.Intensity(e.Request.PathValue("id"))
+	if err != nil {
+		log.Printf("Error loading felt intensity: %v", err)
+		return e.InternalServerError("Failed to load intensity", nil)
+	}
+
+	collection := featureCollection{Type: "FeatureCollection", Features: make([]feature, 0, len(cells))}
+	for _, cell := range cells {
+		minLat, minLon, maxLat, maxLon := utils.GeohashBounds(cell.Geohash())
+		collection.Features = append(collection.Features, feature{
+			Type: "Feature",
+			Geometry: polygon{
+				Type: "Polygon",
+				// GeoJSON is lon, lat with the ring closed on the first corner
+				Coordinates: [][][2]float64{{
+					{minLon, minLat}, {maxLon, minLat}, {maxLon, maxLat}, {minLon, maxLat}, {minLon, minLat},
+				}},
+			},
+			Properties: intensityProperty{
+				Geohash:  cell.Geohash(),
+				MMI:      cell.Mmi(),
+				MMIRoman: ngitung.MMIRoman(cell.Mmi()),
+				Reports:  cell.Reports(),
+			},
+		})
+	}
+
+	body, err := json.Marshal(collection)
+	if err != nil {
+		return e.InternalServerError("Failed to encode intensity", err)
+	}
+	return e.Blob(http.StatusOK, "application/geo+json", body)
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"bmkg/src/db"
+	"bmkg/src/repository"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/pocketbase/pocketbase/apis"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
+)
+
+// LocationHandler lets app users manage the places they receive alerts for
+type LocationHandler struct {
+	subscriberRepo *repository.Subscriber
+}
+
+// NewLocationHandler creates a new instance of LocationHandler
+func NewLocationHandler(subscriberRepo *repository.Subscriber) *LocationHandler {
+	return &LocationHandler{
+		subscriberRepo: subscriberRepo,
+	}
+}
+
+// AddLocationHandler registers the location routes, only for authenticated users
+func (h *LocationHandler) AddLocationHandler(router *router.Router[*core.RequestEvent]) {
+	group := router.Group("/api/locations")
+	group.Bind(apis.RequireAuth("users"))
+	group.GET("", h.list)
+	group.POST("", h.save)
+	group.DELETE("/{label}", h.remove)
+}
+
+// locationResponse is one saved place
+type locationResponse struct {
+	Label   string  `json:"label"`
+	Lintang float64 `json:"lintang"`
+	Bujur   float64 `json:"bujur"`
+}
+
+// locationRequest saves a place, an existing label is moved
+type locationRequest struct {
+	Label   string   `json:"label"`
+	Lintang *float64 `json:"lintang"`
+	Bujur   *float64 `json:"bujur"`
+}
+
+func (h *LocationHandler) list(e *core.RequestEvent) error {
+	subscriber, err := h.subscriberRepo.Ensure(e.Auth.Id, db.Android)
+	if err != nil {
+		log.Printf("Error loading subscriber: %v", err)
+		return e.InternalServerError("Failed to load locations", nil)
+	}
+
+	locations, err := h.subscriberRepo.Locations(subscriber)
+	if err != nil {
+		log.Printf("Error loading locations: %v", err)
+		return e.InternalServerError("Failed to load locations", nil)
+	}
+
+	response := make([]locationResponse, 0, len(locations))
+	for _, location := range locations {
+		lat, _ := strconv.ParseFloat(location.Lintang(), 64)
+		lon, _ := strconv.ParseFloat(location.Bujur(), 64)
+		response = append(response, locationResponse{Label: location.Label(), Lintang: lat, Bujur: lon})
+	}
+
+	return e.JSON(http.StatusOK, response)
+}
+
+func (h *LocationHandler) save(e *core.RequestEvent) error {
+	var request locationRequest
+	if err := e.BindBody(&request); err != nil {
+		return e.BadRequestError("Invalid body", err)
+	}
+	if request.Lintang == nil || request.Bujur == nil {
+		return e.BadRequestError("Lintang and bujur are required", nil)
+	}
+
+	subscriber, err := h.subscriberRepo.Ensure(e.Auth.Id, db.Android)
+	if err != nil {
+		log.Printf("Error loading subscriber: %v", err)
+		return e.InternalServerError("Failed to save location", nil)
+	}
+
+	location, err := h.subscriberRepo.SaveLocation(subscriber, request.Label, *request.Lintang, *request.Bujur)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidLocation) || errors.Is(err, repository.ErrLocationLimit) {
+			return e.BadRequestError(err.Error(), nil)
+		}
+		log.Printf("Error saving location: %v", err)
+		return e.InternalServerError("Failed to save location", nil)
+	}
+
+	return e.JSON(http.StatusOK, locationResponse{Label: location.Label(), Lintang: *request.Lintang, Bujur: *request.Bujur})
+}
+
+func (h *LocationHandler) remove(e *core.RequestEvent) error {
+	subscriber, err := h.subscriberRepo.Ensure(e.Auth.Id, db.Android)
+	if err != nil {
+		log.Printf("Error loading subscriber: %v", err)
+		return e.InternalServerError("Failed to remove location", nil)
+	}
+
+	removed, err := h.subscriberRepo.RemoveLocation(subscriber, e.Request.PathValue("label"))
+	if err != nil {
+		log.Printf("Error removing location: %v", err)
+		return e.InternalServerError("Failed to remove location", nil)
+	}
+	if !removed {
+		return e.NotFoundError("Location not found", nil)
+	}
+
+	return e.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"bmkg/src/worker/outbox"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
+	"net/http"
+)
+
+// OutboxHandler exposes the notification delivery metrics
+type OutboxHandler struct {
+	outbox *outbox.Worker
+}
+
+// NewOutboxHandler creates a new instance of OutboxHandler
+func NewOutboxHandler(worker *outbox.Worker) *OutboxHandler {
+	return &OutboxHandler{
+		outbox: worker,
+	}
+}
+
+// AddOutboxHandler registers outbox route handlers to the router
+func (h *OutboxHandler) AddOutboxHandler(router *router.Router[*core.RequestEvent]) {
+	group := router.Group("/admin")
+	group.GET("/outbox", h.metrics)
+}
+
+// metrics returns queue depth, delivery counters and time-to-deliver per channel
+func (h *OutboxHandler) metrics(e *core.RequestEvent) error {
+	metrics, err := h.outbox.Metrics()
+	if err != nil {
+		return e.String(http.StatusInternalServerError, err.Error())
+	}
+
+	return e.JSON(http.StatusOK, metrics)
+}
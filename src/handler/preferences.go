@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"bmkg/src/db"
+	"bmkg/src/domain"
+	"bmkg/src/repository"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/pocketbase/pocketbase/apis"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
+)
+
+// PreferencesHandler exposes the alert preferences of subscribers
+type PreferencesHandler struct {
+	subscriberRepo *repository.Subscriber
+}
+
+// NewPreferencesHandler creates a new instance of PreferencesHandler
+func NewPreferencesHandler(subscriberRepo *repository.Subscriber) *PreferencesHandler {
+	return &PreferencesHandler{
+		subscriberRepo: subscriberRepo,
+	}
+}
+
+// AddPreferencesHandler registers the preference routes. App users edit their own
+// subscription, superusers any subscription such as an institutional email.
+func (h *PreferencesHandler) AddPreferencesHandler(router *router.Router[*core.RequestEvent]) {
+	user := router.Group("/api/preferences")
+	user.Bind(apis.RequireAuth("users"))
+	user.GET("", h.getOwn)
+	user.PATCH("", h.updateOwn)
+
+	admin := router.Group("/admin/subscribers")
+	admin.Bind(apis.RequireSuperuserAuth())
+	admin.GET("/{id}/preferences", h.get)
+	admin.PATCH("/{id}/preferences", h.update)
+}
+
+// own returns the subscription of the authenticated user, registered with the push token
+func (h *PreferencesHandler) own(e *core.RequestEvent) (*db.UserNotify, error) {
+	subscribers, err := h.subscriberRepo.FindByIdentifier(e.Auth.Id)
+	if err != nil {
+		return nil, err
+	}
+	if len(subscribers) == 0 {
+		return nil, nil
+	}
+	return subscribers[0], nil
+}
+
+func (h *PreferencesHandler) getOwn(e *core.RequestEvent) error {
+	subscriber, err := h.own(e)
+	if err != nil {
+		log.Printf("Error loading preferences: %v", err)
+		return e.InternalServerError("Failed to load preferences", nil)
+	}
+	if subscriber == nil {
+		return e.NotFoundError("No subscription, register a push token with a location first", nil)
+	}
+
+	return e.JSON(http.StatusOK, h.subscriberRepo.Preferences(subscriber))
+}
+
+func (h *PreferencesHandler) updateOwn(e *core.RequestEvent) error {
+	subscriber, err := h.own(e)
+	if err != nil {
+		log.Printf("Error loading preferences: %v", err)
+		return e.InternalServerError("Failed to load preferences", nil)
+	}
+	if subscriber == nil {
+		return e.NotFoundError("No subscription, register a push token with a location first", nil)
+	}
+
+	return h.save(e, subscriber)
+}
+
+func (h *PreferencesHandler) get(e *core.RequestEvent) error {
+	subscriber, err := h.subscriberRepo.Find(e.Request.PathValue("id"))
+	if err != nil {
+		return e.NotFoundError("Subscriber not found", err)
+	}
+
+	return e.JSON(http.StatusOK, h.subscriberRepo.Preferences(subscriber))
+}
+
+func (h *PreferencesHandler) update(e *core.RequestEvent) error {
+	subscriber, err := h.subscriberRepo.Find(e.Request.PathValue("id"))
+	if err != nil {
+		return e.NotFoundError("Subscriber not found", err)
+	}
+
+	return h.save(e, subscriber)
+}
+
+// save applies a partial update, only the fields present in the body change
+func (h *PreferencesHandler) save(e *core.RequestEvent, subscriber *db.UserNotify) error {
+	var preferences domain.NotifyPreferences
+	if err := e.BindBody(&preferences); err != nil {
+		return e.BadRequestError("Invalid body", err)
+	}
+
+	if err := h.subscriberRepo.SavePreferences(subscriber, preferences); err != nil {
+		if errors.Is(err, repository.ErrInvalidPreferences) {
+			return e.BadRequestError(err.Error(), nil)
+		}
+		log.Printf("Error saving preferences: %v", err)
+		return e.InternalServerError("Failed to save preferences", nil)
+	}
+
+	return e.JSON(http.StatusOK, h.subscriberRepo.Preferences(subscriber))
+}
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"bmkg/src/db"
+	"bmkg/src/repository"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/pocketbase/pocketbase/apis"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
+)
+
+// PushHandler lets the mobile app register its FCM device token
+type PushHandler struct {
+	pushTokenRepo  *repository.PushToken
+	subscriberRepo *repository.Subscriber
+}
+
+// NewPushHandler creates a new instance of PushHandler
+func NewPushHandler(pushTokenRepo *repository.PushToken, subscriberRepo *repository.Subscriber) *PushHandler {
+	return &PushHandler{
+		pushTokenRepo:  pushTokenRepo,
+		subscriberRepo: subscriberRepo,
+	}
+}
+
+// AddPushHandler registers the push token routes, only for authenticated users
+func (h *PushHandler) AddPushHandler(router *router.Router[*core.RequestEvent]) {
+	group := router.Group("/api/push")
+	group.Bind(apis.RequireAuth("users"))
+	group.POST("/tokens", h.register)
+	group.DELETE("/tokens/{token}", h.unregister)
+}
+
+// registerRequest is sent by the app on start and whenever FCM refreshes the token.
+// Lintang and Bujur are optional and subscribe the user to alerts for that location,
+// saved under Label (default "Lokasi saat ini").
+type registerRequest struct {
+	Token    string   `json:"token"`
+	Platform string   `json:"platform"`
+	Lintang  *float64 `json:"lintang"`
+	Bujur    *float64 `json:"bujur"`
+	Label    string   `json:"label"`
+}
+
+// currentLocationLabel is the label of the location sent along with the push token
+const currentLocationLabel = "Lokasi saat ini"
+
+// register creates or refreshes a device token of the authenticated user
+func (h *PushHandler) register(e *core.RequestEvent) error {
+	var request registerRequest
+	if err := e.BindBody(&request); err != nil {
+		return e.BadRequestError("Invalid body", err)
+	}
+
+	request.Token = strings.TrimSpace(request.Token)
+	if request.Token == "" {
+		return e.BadRequestError("Token is required", nil)
+	}
+	if request.Platform == "" {
+		request.Platform = "android"
+	}
+	if request.Platform != "android" && request.Platform != "ios" {
+		return e.BadRequestError("Platform must be android or ios", nil)
+	}
+	if (request.Lintang == nil) != (request.Bujur == nil) {
+		return e.BadRequestError("Lintang and bujur must be sent together", nil)
+	}
+
+	userID := e.Auth.Id
+	if err := h.pushTokenRepo.Register(userID, request.Token, request.Platform); err != nil {
+		log.Printf("Error registering push token: %v", err)
+		return e.InternalServerError("Failed to register token", nil)
+	}
+
+	if request.Lintang != nil {
+		if request.Label == "" {
+			request.Label = currentLocationLabel
+		}
+		if err := h.saveLocation(userID, request.Label, *request.Lintang, *request.Bujur); err != nil {
+			if errors.Is(err, repository.ErrInvalidLocation) || errors.Is(err, repository.ErrLocationLimit) {
+				return e.BadRequestError(err.Error(), nil)
+			}
+			log.Printf("Error saving push location: %v", err)
+			return e.InternalServerError("Failed to save location", nil)
+		}
+	}
+
+	return e.NoContent(http.StatusNoContent)
+}
+
+// saveLocation subscribes the user to android alerts for a place. The user_notify
+// identifier of the android channel is the user id.
+func (h *PushHandler) saveLocation(userID, label string, latitude, longitude float64) error {
+	subscriber, err := h.subscriberRepo.Ensure(userID, db.Android)
+	if err != nil {
+		return err
+	}
+
+	_, err = h.subscriberRepo.SaveLocation(subscriber, label, latitude, longitude)
+	return err
+}
+
+// unregister removes a device token of the authenticated user, e.g. on logout
+func (h *PushHandler) unregister(e *core.RequestEvent) error {
+	if err := h.pushTokenRepo.RemoveForUser(e.Auth.Id, e.Request.PathValue("token")); err != nil {
+		log.Printf("Error removing push token: %v", err)
+		return e.InternalServerError("Failed to remove token", nil)
+	}
+
+	return e.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,300 @@
+package handler
+
+import (
+	"bmkg/src/db"
+	"bmkg/src/domain"
+	"bmkg/src/repository"
+	"bmkg/src/utils/ngitung"
+	"bmkg/src/worker/safety"
+	"bytes"
+	"errors"
+	"html/template"
+	"log"
+	"net/http"
+
+	"github.com/pocketbase/pocketbase/apis"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
+)
+
+// SafetyHandler serves the check-in page of group members and the group API of owners.
+// Owners can also follow safety_checkin live through the PocketBase realtime API.
+type SafetyHandler struct {
+	safetyRepo     *repository.Safety
+	subscriberRepo *repository.Subscriber
+	bmkgRepo       *repository.BMKG
+	checkins       *safety.Worker
+}
+
+// NewSafetyHandler creates a new instance of SafetyHandler
+func NewSafetyHandler(safetyRepo *repository.Safety, subscriberRepo *repository.Subscriber, bmkgRepo *repository.BMKG, checkins *safety.Worker) *SafetyHandler {
+	return &SafetyHandler{
+		safetyRepo:     safetyRepo,
+		subscriberRepo: subscriberRepo,
+		bmkgRepo:       bmkgRepo,
+		checkins:       checkins,
+	}
+}
+
+// AddSafetyHandler registers the check-in routes. The check-in page only needs the token
+// of the link, the groups need an app user.
+func (h *SafetyHandler) AddSafetyHandler(router *router.Router[*core.RequestEvent]) {
+	router.GET("/checkin/{token}", h.checkinPage)
+	router.POST("/checkin/{token}", h.checkinAnswer)
+
+	group := router.Group("/api/safety")
+	group.Bind(apis.RequireAuth("users"))
+	group.GET("/groups", h.listGroups)
+	group.POST("/groups", h.createGroup)
+	group.GET("/groups/{id}", h.rollUp)
+	group.DELETE("/groups/{id}/members/{member}", h.removeMember)
+	group.POST("/groups/{id}/remind", h.remind)
+	group.POST("/join", h.join)
+}
+
+const checkinHTML = `<!DOCTYPE html>
+<html>
+<head>
+	<meta charset="utf-8">
+	<meta name="viewport" content="width=device-width, initial-scale=1">
+	<title>Check-in gempa</title>
+</head>
+<body style="font-family: Arial, sans-serif; color: #222; max-width: 480px; margin: 24px auto; padding: 0 16px;">
+	<h2 style="color: #b00020;">Apakah Anda aman?</h2>
+	{{if .Wilayah}}<p>Gempa M{{printf "%.1f" .Magnitude}} {{.Wilayah}}, perkiraan guncangan di lokasi Anda MMI {{.MMIRoman}}.</p>{{end}}
+	{{if eq .Status "safe"}}<p><b>Status Anda: aman.</b> Terima kasih sudah mengabari grup Anda.</p>{{end}}
+	{{if eq .Status "need_help"}}<p><b>Status Anda: butuh bantuan.</b> Grup Anda sudah diberi tahu.</p>{{end}}
+	<form method="post">
+		<button name="status" value="safe" style="font-size: 18px; padding: 12px 24px; margin: 4px; background: #2e7d32; color: #fff; border: 0;">Aman</button>
+		<button name="status" value="need_help" style="font-size: 18px; padding: 12px 24px; margin: 4px; background: #b00020; color: #fff; border: 0;">Butuh bantuan</button>
+	</form>
+	<hr>
+	<p style="font-size: 12px; color: #777;">Jawaban Anda hanya terlihat oleh pengelola grup Anda.</p>
+</body>
+</html>
+`
+
+var checkinTemplate = template.Must(template.New("checkin").Parse(checkinHTML))
+
+// checkinPageData is the data of the check-in page
+type checkinPageData struct {
+	Magnitude float64
+	Wilayah   string
+	MMIRoman  string
+	Status    string
+}
+
+func (h *SafetyHandler) checkinPage(e *core.RequestEvent) error {
+	checkin, err := h.safetyRepo.FindCheckinByToken(e.Request.PathValue("token"))
+	if err != nil {
+		return h.checkinError(e, err)
+	}
+	return h.renderCheckin(e, checkin)
+}
+
+func (h *SafetyHandler) checkinAnswer(e *core.RequestEvent) error {
+	checkin, err := h.safetyRepo.FindCheckinByToken(e.Request.PathValue("token"))
+	if err != nil {
+		return h.checkinError(e, err)
+	}
+
+	status := e.Request.FormValue("status")
+	if !domain.IsCheckinAnswer(status) {
+		return e.BadRequestError("Invalid status", nil)
+	}
+	if err := h.safetyRepo.Respond(checkin, status); err != nil {
+		log.Printf("Error saving check-in: %v", err)
+		return e.InternalServerError("Failed to save check-in", nil)
+	}
+
+	return h.renderCheckin(e, checkin)
+}
+
+func (h *SafetyHandler) checkinError(e *core.RequestEvent, err error) error {
+	if errors.Is(err, repository.ErrCheckinNotFound) {
+		return e.NotFoundError("Check-in not found", nil)
+	}
+	log.Printf("Error loading check-in: %v", err)
+	return e.InternalServerError("Failed to load check-in", nil)
+}
+
+// renderCheckin shows the question with the current answer
+func (h *SafetyHandler) renderCheckin(e *core.RequestEvent, checkin *db.SafetyCheckin) error {
+	data := checkinPageData{
+		MMIRoman: ngitung.MMIRoman(checkin.Mmi()),
+		Status:   checkin.GetString("status"),
+	}
+	if earthquake, err := h.bmkgRepo.GetEarthquake(checkin.GetString("earthquake")); err == nil {
+		data.Magnitude = earthquake.Magnitude()
+		data.Wilayah = earthquake.Wilayah()
+	}
+
+	var page bytes.Buffer
+	if err := checkinTemplate.Execute(&page, data); err != nil {
+		return e.InternalServerError("Failed to render check-in", err)
+	}
+	return e.HTML(http.StatusOK, page.String())
+}
+
+// groupRequest creates a group
+type groupRequest struct {
+	Name string `json:"name"`
+}
+
+// groupResponse is a group of the owner, the invite code is shared with the members
+type groupResponse struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	InviteCode string `json:"invite_code"`
+}
+
+// rollUpResponse is a group with the check-ins of its members after one earthquake
+type rollUpResponse struct {
+	groupResponse
+	domain.CheckinRollUp
+}
+
+func newGroupResponse(group *db.SafetyGroup) groupResponse {
+	return groupResponse{ID: group.Id, Name: group.Name(), InviteCode: group.InviteCode()}
+}
+
+func (h *SafetyHandler) listGroups(e *core.RequestEvent) error {
+	groups, err := h.safetyRepo.OwnedGroups(e.Auth.Id)
+	if err != nil {
+		log.Printf("Error loading groups: %v", err)
+		return e.InternalServerError("Failed to load groups", nil)
+	}
+
+	response := make([]groupResponse, 0, len(groups))
+	for _, group := range groups {
+		response = append(response, newGroupResponse(group))
+	}
+	return e.JSON(http.StatusOK, response)
+}
+
+func (h *SafetyHandler) createGroup(e *core.RequestEvent) error {
+	var request groupRequest
+	if err := e.BindBody(&request); err != nil {
+		return e.BadRequestError("Invalid body", err)
+	}
+
+	group, err := h.safetyRepo.CreateGroup(e.Auth.Id, request.Name)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidGroup) {
+			return e.BadRequestError(err.Error(), nil)
+		}
+		log.Printf("Error creating group: %v", err)
+		return e.InternalServerError("Failed to create group", nil)
+	}
+
+	return e.JSON(http.StatusCreated, newGroupResponse(group))
+}
+
+// ownedGroup loads the group of the path, writing the error response when it fails
+func (h *SafetyHandler) ownedGroup(e *core.RequestEvent) (*db.SafetyGroup, error) {
+	group, err := h.safetyRepo.OwnedGroup(e.Auth.Id, e.Request.PathValue("id"))
+	if errors.Is(err, repository.ErrGroupNotFound) {
+		return nil, e.NotFoundError("Group not found", nil)
+	}
+	if err != nil {
+		log.Printf("Error loading group: %v", err)
+		return nil, e.InternalServerError("Failed to load group", nil)
+	}
+	return group, nil
+}
+
+// rollUp shows who responded after the earthquake of ?earthquake=, by default the latest
+// earthquake that opened a check-in for a member
+func (h *SafetyHandler) rollUp(e *core.RequestEvent) error {
+	group, err := h.ownedGroup(e)
+	if err != nil {
+		return err
+	}
+
+	rollUp, err := h.safetyRepo.RollUp(group, e.Request.URL.Query().Get("earthquake"))
+	if err != nil {
+		log.Printf("Error loading check-ins: %v", err)
+		return e.InternalServerError("Failed to load check-ins", nil)
+	}
+
+	return e.JSON(http.StatusOK, rollUpResponse{groupResponse: newGroupResponse(group), CheckinRollUp: rollUp})
+}
+
+func (h *SafetyHandler) removeMember(e *core.RequestEvent) error {
+	group, err := h.ownedGroup(e)
+	if err != nil {
+		return err
+	}
+
+	removed, err := h.safetyRepo.RemoveMember(group, e.Request.PathValue("member"))
+	if err != nil {
+		log.Printf("Error removing member: %v", err)
+		return e.InternalServerError("Failed to remove member", nil)
+	}
+	if !removed {
+		return e.NotFoundError("Member not found", nil)
+	}
+
+	return e.NoContent(http.StatusNoContent)
+}
+
+// remind asks the members that did not answer after the earthquake of ?earthquake= again
+func (h *SafetyHandler) remind(e *core.RequestEvent) error {
+	group, err := h.ownedGroup(e)
+	if err != nil {
+		return err
+	}
+
+	_, checkins, err := h.safetyRepo.GroupCheckins(group, e.Request.URL.Query().Get("earthquake"))
+	if err != nil {
+		log.Printf("Error loading check-ins: %v", err)
+		return e.InternalServerError("Failed to load check-ins", nil)
+	}
+
+	sent, err := h.checkins.Remind(checkins)
+	if err != nil {
+		log.Printf("Error sending reminders: %v", err)
+		return e.InternalServerError("Failed to send reminders", nil)
+	}
+
+	return e.JSON(http.StatusOK, map[string]int{"reminded": sent})
+}
+
+// joinRequest joins a group with the invite code, name is how the owner sees the member
+type joinRequest struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+// join adds the push subscription of the app user to a group
+func (h *SafetyHandler) join(e *core.RequestEvent) error {
+	var request joinRequest
+	if err := e.BindBody(&request); err != nil {
+		return e.BadRequestError("Invalid body", err)
+	}
+	if request.Code == "" {
+		return e.BadRequestError("Code is required", nil)
+	}
+
+	subscriber, err := h.subscriberRepo.Ensure(e.Auth.Id, db.Android)
+	if err != nil {
+		log.Printf("Error loading subscriber: %v", err)
+		return e.InternalServerError("Failed to join group", nil)
+	}
+
+	name := request.Name
+	if name == "" {
+		name = e.Auth.GetString("name")
+	}
+
+	group, err := h.safetyRepo.JoinGroup(request.Code, subscriber, name)
+	if errors.Is(err, repository.ErrGroupNotFound) {
+		return e.NotFoundError("Group not found", nil)
+	}
+	if err != nil {
+		log.Printf("Error joining group: %v", err)
+		return e.InternalServerError("Failed to join group", nil)
+	}
+
+	return e.JSON(http.StatusOK, map[string]string{"id": group.Id, "name": group.Name()})
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"bmkg/src/repository"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/pocketbase/pocketbase/apis"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
+)
+
+// replayDefaultWindow is how far back a replay goes when no since is given
+const replayDefaultWindow = 24 * time.Hour
+
+// WebhookHandler lets superusers replay and re-enable webhook subscriptions
+type WebhookHandler struct {
+	webhookRepo *repository.Webhook
+	outboxRepo  *repository.Outbox
+}
+
+// NewWebhookHandler creates a new instance of WebhookHandler
+func NewWebhookHandler(webhookRepo *repository.Webhook, outboxRepo *repository.Outbox) *WebhookHandler {
+	return &WebhookHandler{
+		webhookRepo: webhookRepo,
+		outboxRepo:  outboxRepo,
+	}
+}
+
+// AddWebhookHandler registers the webhook admin routes to the router
+func (h *WebhookHandler) AddWebhookHandler(router *router.Router[*core.RequestEvent]) {
+	group := router.Group("/admin/webhooks")
+	group.Bind(apis.RequireSuperuserAuth())
+	group.POST("/{id}/replay", h.replay)
+	group.POST("/{id}/enable", h.enable)
+}
+
+// replay sends the events of a subscription again, e.g. after the partner lost data.
+// The optional since query parameter (RFC 3339) defaults to the last 24 hours.
+func (h *WebhookHandler) replay(e *core.RequestEvent) error {
+	id := e.Request.PathValue("id")
+	if _, err := h.webhookRepo.Find(id); err != nil {
+		return e.NotFoundError("Webhook subscription not found", err)
+	}
+
+	since := time.Now().Add(-replayDefaultWindow)
+	if raw := e.Request.URL.Query().Get("since"); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return e.BadRequestError("Invalid since, use RFC 3339", err)
+		}
+		since = parsed
+	}
+
+	count, err := h.outboxRepo.Replay("webhook", id, since)
+	if err != nil {
+		log.Printf("Error replaying webhook %s: %v", id, err)
+		return e.InternalServerError("Failed to replay webhook", nil)
+	}
+
+	return e.JSON(http.StatusOK, map[string]interface{}{"replayed": count})
+}
+
+// enable reactivates a subscription that was disabled after repeated failures
+func (h *WebhookHandler) enable(e *core.RequestEvent) error {
+	id := e.Request.PathValue("id")
+	if _, err := h.webhookRepo.Find(id); err != nil {
+		return e.NotFoundError("Webhook subscription not found", err)
+	}
+
+	if err := h.webhookRepo.Enable(id); err != nil {
+		log.Printf("Error enabling webhook %s: %v", id, err)
+		return e.InternalServerError("Failed to enable webhook", nil)
+	}
+
+	return e.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"bmkg/src/repository"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
+)
+
+// WhatsAppHandler receives the WhatsApp Cloud API webhook with delivery receipts
+type WhatsAppHandler struct {
+	outboxRepo  *repository.Outbox
+	verifyToken string
+	appSecret   string
+}
+
+// NewWhatsAppHandler creates a new instance of WhatsAppHandler
+func NewWhatsAppHandler(outboxRepo *repository.Outbox, verifyToken, appSecret string) *WhatsAppHandler {
+	return &WhatsAppHandler{
+		outboxRepo:  outboxRepo,
+		verifyToken: verifyToken,
+		appSecret:   appSecret,
+	}
+}
+
+// AddWhatsAppHandler registers the webhook routes to the router
+func (h *WhatsAppHandler) AddWhatsAppHandler(router *router.Router[*core.RequestEvent]) {
+	group := router.Group("/webhook")
+	group.GET("/whatsapp", h.verify)
+	group.POST("/whatsapp", h.receive)
+}
+
+// verify answers the subscription handshake of Meta by echoing hub.challenge
+func (h *WhatsAppHandler) verify(e *core.RequestEvent) error {
+	query := e.Request.URL.Query()
+
+	if h.verifyToken == "" || query.Get("hub.mode") != "subscribe" || query.Get("hub.verify_token") != h.verifyToken {
+		return e.String(http.StatusForbidden, "Forbidden")
+	}
+
+	return e.String(http.StatusOK, query.Get("hub.challenge"))
+}
+
+// whatsAppWebhook is the part of the webhook payload carrying message statuses
+type whatsAppWebhook struct {
+	Entry []struct {
+		Changes []struct {
+			Value struct {
+				Statuses []struct {
+					ID          string `json:"id"`
+					Status      string `json:"status"`
+					RecipientID string `json:"recipient_id"`
+					Errors      []struct {
+						Code  int    `json:"code"`
+						Title string `json:"title"`
+					} `json:"errors"`
+				} `json:"statuses"`
+			} `json:"value"`
+		} `json:"changes"`
+	} `json:"entry"`
+}
+
+// receive stores the sent/delivered/read/failed receipts on the matching outbox rows
+func (h *WhatsAppHandler) receive(e *core.RequestEvent) error {
+	body, err := io.ReadAll(e.Request.Body)
+	if err != nil {
+		return e.String(http.StatusBadRequest, "Invalid body")
+	}
+
+	if !h.validSignature(body, e.Request.Header.Get("X-Hub-Signature-256")) {
+		return e.String(http.StatusUnauthorized, "Invalid signature")
+	}
+
+	var payload whatsAppWebhook
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return e.String(http.StatusBadRequest, "Invalid payload")
+	}
+
+	for _, entry := range payload.Entry {
+		for _, change := range entry.Changes {
+			for _, status := range change.Value.Statuses {
+				var errorText string
+				if len(status.Errors) > 0 {
+					errorText = status.Errors[0].Title
+				}
+
+				if err := h.outboxRepo.UpdateReceipt(status.ID, status.Status, errorText); err != nil {
+					log.Printf("Error saving whatsapp receipt %s: %v", status.ID, err)
+				}
+			}
+		}
+	}
+
+	// Meta retries anything but 200, so always acknowledge a valid delivery
+	return e.String(http.StatusOK, "OK")
+}
+
+// validSignature checks the HMAC-SHA256 of the body signed with the app secret
+func (h *WhatsAppHandler) validSignature(body []byte, header string) bool {
+	if h.appSecret == "" {
+		return false
+	}
+
+	signature, ok := strings.CutPrefix(header, "sha256=")
+	if !ok {
+		return false
+	}
+
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(h.appSecret))
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
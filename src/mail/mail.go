@@ -1,16 +1,142 @@
 package mail
 
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	netmail "net/mail"
+	"sync"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/mailer"
+)
+
+// ErrNotConfigured is returned by the package functions before SetDefault is called
+var ErrNotConfigured = errors.New("mail is not configured")
+
+// Config of the outgoing mail server. Without Host the SMTP settings of PocketBase
+// (or sendmail) are used, and without From its sender address.
+type Config struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	TLS      bool
+	From     string
+	FromName string
+}
+
 // send message to email
 
 type Message struct {
 	To      string
 	Subject string
-	Body    string
+	// Body is the plain text part, HTML the optional HTML part
+	Body string
+	HTML string
+	// Attachments by file name
+	Attachments map[string][]byte
+}
+
+// Mailer sends messages through a direct SMTP client or the PocketBase mailer
+type Mailer struct {
+	app    core.App
+	config Config
+}
+
+// NewMailer creates a mailer
+func NewMailer(app core.App, config Config) *Mailer {
+	if config.Host != "" && config.Port == 0 {
+		config.Port = 587
+	}
+
+	return &Mailer{
+		app:    app,
+		config: config,
+	}
+}
+
+// client is resolved on every send so changes in the PocketBase settings apply right away
+func (m *Mailer) client() mailer.Mailer {
+	if m.config.Host == "" {
+		return m.app.NewMailClient()
+	}
+
+	return &mailer.SMTPClient{
+		Host:     m.config.Host,
+		Port:     m.config.Port,
+		Username: m.config.Username,
+		Password: m.config.Password,
+		TLS:      m.config.TLS,
+	}
+}
+
+func (m *Mailer) from() netmail.Address {
+	if m.config.From != "" {
+		return netmail.Address{Address: m.config.From, Name: m.config.FromName}
+	}
+
+	meta := m.app.Settings().Meta
+	return netmail.Address{Address: meta.SenderAddress, Name: meta.SenderName}
+}
+
+// Send delivers a message
+func (m *Mailer) Send(msg Message) error {
+	to, err := netmail.ParseAddress(msg.To)
+	if err != nil {
+		return fmt.Errorf("invalid email address %q: %w", msg.To, err)
+	}
+
+	message := &mailer.Message{
+		From:    m.from(),
+		To:      []netmail.Address{*to},
+		Subject: msg.Subject,
+		HTML:    msg.HTML,
+		Text:    msg.Body,
+	}
+
+	if len(msg.Attachments) > 0 {
+		message.Attachments = make(map[string]io.Reader, len(msg.Attachments))
+		for name, data := range msg.Attachments {
+			message.Attachments[name] = bytes.NewReader(data)
+		}
+	}
+
+	if err := m.client().Send(message); err != nil {
+		return fmt.Errorf("failed to send email to %s: %w", to.Address, err)
+	}
+	return nil
+}
+
+var (
+	defaultMu     sync.RWMutex
+	defaultMailer *Mailer
+)
+
+// SetDefault sets the mailer used by SendMessage and SendEmail
+func SetDefault(m *Mailer) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+
+	defaultMailer = m
+}
+
+// Default returns the mailer set with SetDefault, or nil
+func Default() *Mailer {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+
+	return defaultMailer
 }
 
 // SendMessage sends a message to the specified email address
-func SendMessage(msg Message) {
-	// send email
+func SendMessage(msg Message) error {
+	m := Default()
+	if m == nil {
+		return ErrNotConfigured
+	}
+	return m.Send(msg)
 }
 
 // make new message
@@ -23,7 +149,7 @@ func NewMessage(to, subject, body string) Message {
 }
 
 // send message to email
-func SendEmail(to, subject, body string) {
+func SendEmail(to, subject, body string) error {
 	msg := NewMessage(to, subject, body)
-	SendMessage(msg)
+	return SendMessage(msg)
 }
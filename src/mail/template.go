@@ -0,0 +1,69 @@
+package mail
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	texttemplate "text/template"
+)
+
+// Alert is the content of an alert or report email
+type Alert struct {
+	Title string
+	Lines []string
+	// MapURL links to the epicenter on a map, optional
+	MapURL string
+	// Shakemap is the file name of the attached shakemap image, optional
+	Shakemap string
+	// Localized wording around the alert, see the email.* message templates
+	MapLabel      string
+	ShakemapLabel string
+	Footer        string
+}
+
+const alertText = `{{.Title}}
+
+{{range .Lines}}{{.}}
+{{end}}{{if .MapURL}}
+{{.MapLabel}}: {{.MapURL}}
+{{end}}{{if .Shakemap}}
+{{.ShakemapLabel}}: {{.Shakemap}}
+{{end}}
+--
+{{.Footer}}
+`
+
+const alertHTML = `<!DOCTYPE html>
+<html>
+<body style="font-family: Arial, sans-serif; color: #222; max-width: 600px;">
+	<h2 style="color: #b00020;">{{.Title}}</h2>
+	<table style="border-collapse: collapse;">
+		{{range .Lines}}<tr><td style="padding: 4px 0;">{{.}}</td></tr>
+		{{end}}
+	</table>
+	{{if .MapURL}}<p><a href="{{.MapURL}}">{{.MapLabel}}</a></p>{{end}}
+	{{if .Shakemap}}<p>{{.ShakemapLabel}}: <b>{{.Shakemap}}</b></p>{{end}}
+	<hr>
+	<p style="font-size: 12px; color: #777;">{{.Footer}}</p>
+</body>
+</html>
+`
+
+var (
+	alertTextTemplate = texttemplate.Must(texttemplate.New("alert").Parse(alertText))
+	alertHTMLTemplate = htmltemplate.Must(htmltemplate.New("alert").Parse(alertHTML))
+)
+
+// RenderAlert renders the HTML and plain text part of an alert email
+func RenderAlert(alert Alert) (string, string, error) {
+	var html, text bytes.Buffer
+
+	if err := alertHTMLTemplate.Execute(&html, alert); err != nil {
+		return "", "", fmt.Errorf("failed to render alert email: %w", err)
+	}
+	if err := alertTextTemplate.Execute(&text, alert); err != nil {
+		return "", "", fmt.Errorf("failed to render alert email: %w", err)
+	}
+
+	return html.String(), text.String(), nil
+}
@@ -0,0 +1,339 @@
+package messages
+
+// Keys of the message templates
+const (
+	// KeyAlert is the earthquake alert, usually one per tier (data: bmkg alertData)
+	KeyAlert = "alert"
+	// KeyCorrection heads the alert of a revised report
+	KeyCorrection = "correction"
+	// KeyPlaces lists the affected saved locations of a subscriber
+	KeyPlaces = "places"
+
+	KeyBotWelcome          = "bot.welcome"
+	KeyBotMenu             = "bot.menu"
+	KeyBotButtonLocation   = "bot.button_location"
+	KeyBotButtonHelp       = "bot.button_help"
+	KeyBotError            = "bot.error"
+	KeyBotNoSubscriber     = "bot.no_subscriber"
+	KeyBotLocationAsk      = "bot.location_ask"
+	KeyBotLocationLabels   = "bot.location_labels"
+	KeyBotLocationSaved    = "bot.location_saved"
+	KeyBotLocationLimit    = "bot.location_limit"
+	KeyBotLocationInvalid  = "bot.location_invalid"
+	KeyBotLocationUsage    = "bot.location_usage"
+	KeyBotLocationNotFound = "bot.location_not_found"
+	KeyBotLocations        = "bot.locations"
+	KeyBotLocationsHelp    = "bot.locations_help"
+	KeyBotPreferences      = "bot.preferences"
+	KeyBotPreferencesHelp  = "bot.preferences_help"
+	KeyBotPreferencesError = "bot.preferences_invalid"
+	KeyBotUsageNumber      = "bot.usage_number"
+	KeyBotUsageQuiet       = "bot.usage_quiet"
+	KeyBotUsageTimezone    = "bot.usage_timezone"
+	KeyBotUsageLanguage    = "bot.usage_language"
+	// KeyBotCommands lists the bot commands as "command - description" lines
+	KeyBotCommands       = "bot.commands"
+	KeyBotUnsubscribeAsk = "bot.unsubscribe_confirm"
+	KeyBotUnsubscribed   = "bot.unsubscribed"
+	KeyBotLatest         = "bot.latest"
+	KeyBotToday          = "bot.today"
+	KeyBotNoEarthquake   = "bot.no_earthquake"
+	// alert actions, KeyBotEpicenter also names the WhatsApp location pin
+	KeyBotEpicenter    = "bot.epicenter"
+	KeyBotButtonSafe   = "bot.button_safe"
+	KeyBotButtonFelt   = "bot.button_felt"
+	KeyBotButtonDetail = "bot.button_detail"
+	KeyBotSafeThanks   = "bot.safe_thanks"
+	KeyBotFeltThanks   = "bot.felt_thanks"
+	KeyBotDetail       = "bot.detail"
+	// "did you feel it?" questionnaire, the options are one answer per line
+	KeyBotFeltAsk            = "bot.felt_ask"
+	KeyBotFeltAnswers        = "bot.felt_answers"
+	KeyBotFeltShaking        = "bot.felt_shaking"
+	KeyBotFeltShakingOptions = "bot.felt_shaking_options"
+	KeyBotFeltEffect         = "bot.felt_effect"
+	KeyBotFeltEffectOptions  = "bot.felt_effect_options"
+	// KeyCheckin asks a group member whether they are safe (data: safety checkinData),
+	// the telegram variant answers with buttons instead of the link
+	KeyCheckin = "checkin"
+	// safety check-in buttons and the /gabung command
+	KeyBotButtonCheckinSafe     = "bot.button_checkin_safe"
+	KeyBotButtonCheckinNeedHelp = "bot.button_checkin_need_help"
+	KeyBotCheckinRecorded       = "bot.checkin_recorded"
+	KeyBotCheckinUnknown        = "bot.checkin_unknown"
+	KeyBotGroupUsage            = "bot.group_usage"
+	KeyBotGroupJoined           = "bot.group_joined"
+	KeyBotGroupNotFound         = "bot.group_not_found"
+	// alert email around the alert text (subject data: Title, the first line of the alert)
+	KeyEmailSubject  = "email.subject"
+	KeyEmailMapLink  = "email.map_link"
+	KeyEmailShakemap = "email.shakemap"
+	KeyEmailFooter   = "email.footer"
+)
+
+// Tiers of the default alert tiers, see bmkg.defaultTiers
+const (
+	tierInformational = "informational"
+	tierWarning       = "warning"
+	tierSevere        = "severe"
+)
+
+// Defaults are the built-in templates in Indonesian and English. They are used when
+// message_template has no matching template and seed the collection on first migrate.
+func Defaults() []Template {
+	return []Template{
+		// alert per tier
+		{Key: KeyAlert, Tier: tierInformational, Language: LanguageIndonesian, Body: "Info Gempa M{{.Magnitude}}" +
+			"\nLokasi: {{.Lintang}}, {{.Bujur}} ({{.Wilayah}})" +
+			"\nKedalaman: {{.Kedalaman}}" +
+			"\nWaktu: {{.Tanggal}} {{.Jam}}" +
+			"\nPerkiraan di lokasi Anda: MMI {{.MMIRoman}}, {{printf \"%.0f\" .Distance}} km dari episenter"},
+		{Key: KeyAlert, Tier: tierInformational, Language: LanguageEnglish, Body: "Earthquake Info M{{.Magnitude}}" +
+			"\nLocation: {{.Lintang}}, {{.Bujur}} ({{.Wilayah}})" +
+			"\nDepth: {{.Kedalaman}}" +
+			"\nTime: {{.Tanggal}} {{.Jam}}" +
+			"\nEstimate at your location: MMI {{.MMIRoman}}, {{printf \"%.0f\" .Distance}} km from the epicenter"},
+		{Key: KeyAlert, Tier: tierWarning, Language: LanguageIndonesian, Body: "PERINGATAN Gempa M{{.Magnitude}}" +
+			"\nGuncangan kuat diperkirakan di lokasi Anda: MMI {{.MMIRoman}}, {{printf \"%.0f\" .Distance}} km dari episenter" +
+			"\nLokasi: {{.Lintang}}, {{.Bujur}} ({{.Wilayah}})" +
+			"\nKedalaman: {{.Kedalaman}}" +
+			"\nWaktu: {{.Tanggal}} {{.Jam}}" +
+			"\nWaspada gempa susulan."},
+		{Key: KeyAlert, Tier: tierWarning, Language: LanguageEnglish, Body: "WARNING Earthquake M{{.Magnitude}}" +
+			"\nStrong shaking expected at your location: MMI {{.MMIRoman}}, {{printf \"%.0f\" .Distance}} km from the epicenter" +
+			"\nLocation: {{.Lintang}}, {{.Bujur}} ({{.Wilayah}})" +
+			"\nDepth: {{.Kedalaman}}" +
+			"\nTime: {{.Tanggal}} {{.Jam}}" +
+			"\nBe alert for aftershocks."},
+		{Key: KeyAlert, Tier: tierSevere, Language: LanguageIndonesian, Body: "BAHAYA Gempa M{{.Magnitude}}" +
+			"\nGuncangan sangat kuat diperkirakan di lokasi Anda: MMI {{.MMIRoman}}, {{printf \"%.0f\" .Distance}} km dari episenter" +
+			"\nSegera lindungi diri dan jauhi bangunan." +
+			"\nLokasi: {{.Lintang}}, {{.Bujur}} ({{.Wilayah}})" +
+			"\nKedalaman: {{.Kedalaman}}" +
+			"\nWaktu: {{.Tanggal}} {{.Jam}}"},
+		{Key: KeyAlert, Tier: tierSevere, Language: LanguageEnglish, Body: "DANGER Earthquake M{{.Magnitude}}" +
+			"\nVery strong shaking expected at your location: MMI {{.MMIRoman}}, {{printf \"%.0f\" .Distance}} km from the epicenter" +
+			"\nProtect yourself now and stay away from buildings." +
+			"\nLocation: {{.Lintang}}, {{.Bujur}} ({{.Wilayah}})" +
+			"\nDepth: {{.Kedalaman}}" +
+			"\nTime: {{.Tanggal}} {{.Jam}}"},
+		// tiers from ALERT_TIERS without their own template
+		{Key: KeyAlert, Language: LanguageIndonesian, Body: "Gempa M{{.Magnitude}} ({{.Tier}})" +
+			"\nLokasi: {{.Lintang}}, {{.Bujur}} ({{.Wilayah}})" +
+			"\nKedalaman: {{.Kedalaman}}" +
+			"\nWaktu: {{.Tanggal}} {{.Jam}}" +
+			"\nPerkiraan di lokasi Anda: MMI {{.MMIRoman}}, {{printf \"%.0f\" .Distance}} km dari episenter"},
+		{Key: KeyAlert, Language: LanguageEnglish, Body: "Earthquake M{{.Magnitude}} ({{.Tier}})" +
+			"\nLocation: {{.Lintang}}, {{.Bujur}} ({{.Wilayah}})" +
+			"\nDepth: {{.Kedalaman}}" +
+			"\nTime: {{.Tanggal}} {{.Jam}}" +
+			"\nEstimate at your location: MMI {{.MMIRoman}}, {{printf \"%.0f\" .Distance}} km from the epicenter"},
+
+		{Key: KeyCorrection, Language: LanguageIndonesian, Body: "[KOREKSI] Magnitudo: {{.PreviousMagnitude}} -> {{.Magnitude}}, Kedalaman: {{.PreviousKedalaman}} -> {{.Kedalaman}}"},
+		{Key: KeyCorrection, Language: LanguageEnglish, Body: "[CORRECTION] Magnitude: {{.PreviousMagnitude}} -> {{.Magnitude}}, Depth: {{.PreviousKedalaman}} -> {{.Kedalaman}}"},
+
+		{Key: KeyPlaces, Language: LanguageIndonesian, Body: "Lokasi tersimpan:{{range .Places}}\n{{.Label}}: MMI {{.MMIRoman}}, {{printf \"%.0f\" .Distance}} km{{end}}"},
+		{Key: KeyPlaces, Language: LanguageEnglish, Body: "Saved places:{{range .Places}}\n{{.Label}}: MMI {{.MMIRoman}}, {{printf \"%.0f\" .Distance}} km{{end}}"},
+
+		// telegram bot
+		{Key: KeyBotWelcome, Language: LanguageIndonesian, Body: "Selamat datang di bot ini!\n\nAnda dapat mengirimkan lokasi dengan menekan tombol 'Kirim Lokasi'."},
+		{Key: KeyBotWelcome, Language: LanguageEnglish, Body: "Welcome to this bot!\n\nYou can send your location with the 'Send Location' button."},
+		{Key: KeyBotMenu, Language: LanguageIndonesian, Body: "Silakan kirim lokasi Anda atau pilih opsi lainnya:"},
+		{Key: KeyBotMenu, Language: LanguageEnglish, Body: "Please send your location or choose another option:"},
+		{Key: KeyBotButtonLocation, Language: LanguageIndonesian, Body: "Kirim Lokasi"},
+		{Key: KeyBotButtonLocation, Language: LanguageEnglish, Body: "Send Location"},
+		{Key: KeyBotButtonHelp, Language: LanguageIndonesian, Body: "Bantuan"},
+		{Key: KeyBotButtonHelp, Language: LanguageEnglish, Body: "Help"},
+		{Key: KeyBotError, Language: LanguageIndonesian, Body: "Terjadi kesalahan. Silakan coba lagi nanti."},
+		{Key: KeyBotError, Language: LanguageEnglish, Body: "Something went wrong. Please try again later."},
+		{Key: KeyBotNoSubscriber, Language: LanguageIndonesian, Body: "Kirim lokasi Anda terlebih dahulu dengan tombol 'Kirim Lokasi'."},
+		{Key: KeyBotNoSubscriber, Language: LanguageEnglish, Body: "Send your location first with the 'Send Location' button."},
+
+		{Key: KeyBotLocationAsk, Language: LanguageIndonesian, Body: "Beri nama lokasi ini. Pilih salah satu atau ketik nama sendiri:"},
+		{Key: KeyBotLocationAsk, Language: LanguageEnglish, Body: "Name this location. Pick one or type your own name:"},
+		{Key: KeyBotLocationLabels, Language: LanguageIndonesian, Body: "Rumah\nKantor\nRumah Orang Tua"},
+		{Key: KeyBotLocationLabels, Language: LanguageEnglish, Body: "Home\nOffice\nParents' Home"},
+		{Key: KeyBotLocationSaved, Language: LanguageIndonesian, Body: "Lokasi \"{{.Label}}\" telah disimpan! Anda akan menerima notifikasi gempa bumi yang terasa di lokasi tersebut."},
+		{Key: KeyBotLocationSaved, Language: LanguageEnglish, Body: "Location \"{{.Label}}\" saved! You will be notified of earthquakes felt at this location."},
+		{Key: KeyBotLocationLimit, Language: LanguageIndonesian, Body: "Anda sudah menyimpan {{.Max}} lokasi. Hapus salah satu dengan /hapuslokasi terlebih dahulu."},
+		{Key: KeyBotLocationLimit, Language: LanguageEnglish, Body: "You already saved {{.Max}} locations. Remove one with /hapuslokasi first."},
+		{Key: KeyBotLocationInvalid, Language: LanguageIndonesian, Body: "Nama lokasi maksimal 40 karakter. Silakan kirim lokasi Anda lagi."},
+		{Key: KeyBotLocationInvalid, Language: LanguageEnglish, Body: "A location name has at most 40 characters. Please send your location again."},
+		{Key: KeyBotLocationUsage, Language: LanguageIndonesian, Body: "Tulis nama lokasi, misalnya /hapuslokasi Kantor"},
+		{Key: KeyBotLocationUsage, Language: LanguageEnglish, Body: "Write the location name, e.g. /hapuslokasi Office"},
+		{Key: KeyBotLocationNotFound, Language: LanguageIndonesian, Body: "Lokasi \"{{.Label}}\" tidak ditemukan."},
+		{Key: KeyBotLocationNotFound, Language: LanguageEnglish, Body: "Location \"{{.Label}}\" not found."},
+		{Key: KeyBotLocations, Language: LanguageIndonesian, Body: "{{if .Locations}}Lokasi tersimpan:{{range .Locations}}\n- {{.Label}} ({{.Lintang}}, {{.Bujur}}){{end}}" +
+			"{{else}}Belum ada lokasi tersimpan. Kirim lokasi dengan tombol 'Kirim Lokasi'.{{end}}"},
+		{Key: KeyBotLocations, Language: LanguageEnglish, Body: "{{if .Locations}}Saved locations:{{range .Locations}}\n- {{.Label}} ({{.Lintang}}, {{.Bujur}}){{end}}" +
+			"{{else}}No saved locations yet. Send one with the 'Send Location' button.{{end}}"},
+		{Key: KeyBotLocationsHelp, Language: LanguageIndonesian, Body: "Lokasi tersimpan (maksimal {{.Max}}):\n" +
+			"/lokasi - lihat lokasi tersimpan\n" +
+			"/hapuslokasi Kantor - hapus lokasi dengan nama tersebut"},
+		{Key: KeyBotLocationsHelp, Language: LanguageEnglish, Body: "Saved locations (at most {{.Max}}):\n" +
+			"/lokasi - show saved locations\n" +
+			"/hapuslokasi Office - remove the location with that name"},
+
+		{Key: KeyBotPreferences, Language: LanguageIndonesian, Body: "Pengaturan notifikasi Anda:" +
+			"\nMagnitudo: {{if gt .MinMagnitude 0.0}}M{{printf \"%.1f\" .MinMagnitude}} ke atas{{else}}semua{{end}}" +
+			"\nGuncangan di lokasi: {{if gt .MinMMI 0.0}}MMI {{printf \"%.1f\" .MinMMI}} ke atas{{else}}semua{{end}}" +
+			"\nRadius: {{if gt .RadiusKm 0.0}}{{printf \"%.0f\" .RadiusKm}} km dari lokasi tersimpan{{else}}semua jarak{{end}}" +
+			"\nJam tenang: {{if .QuietStart}}{{.QuietStart}}-{{.QuietEnd}} (peringatan bahaya tetap dikirim){{else}}tidak aktif{{end}}" +
+			"\nZona waktu: {{.Timezone}}" +
+			"\nBahasa: {{.Language}}"},
+		{Key: KeyBotPreferences, Language: LanguageEnglish, Body: "Your notification settings:" +
+			"\nMagnitude: {{if gt .MinMagnitude 0.0}}M{{printf \"%.1f\" .MinMagnitude}} and above{{else}}all{{end}}" +
+			"\nShaking at your location: {{if gt .MinMMI 0.0}}MMI {{printf \"%.1f\" .MinMMI}} and above{{else}}all{{end}}" +
+			"\nRadius: {{if gt .RadiusKm 0.0}}{{printf \"%.0f\" .RadiusKm}} km from your saved locations{{else}}any distance{{end}}" +
+			"\nQuiet hours: {{if .QuietStart}}{{.QuietStart}}-{{.QuietEnd}} (danger alerts are still sent){{else}}off{{end}}" +
+			"\nTime zone: {{.Timezone}}" +
+			"\nLanguage: {{.Language}}"},
+		{Key: KeyBotPreferencesHelp, Language: LanguageIndonesian, Body: "Atur notifikasi Anda:\n" +
+			"/preferensi - lihat pengaturan\n" +
+			"/minmag 5 - hanya gempa M5 ke atas (off untuk semua)\n" +
+			"/minmmi 4 - hanya guncangan MMI IV ke atas di lokasi Anda\n" +
+			"/radius 100 - hanya gempa dalam 100 km dari lokasi Anda (off untuk semua)\n" +
+			"/sunyi 22:00-06:00 - jam tenang, kecuali peringatan bahaya (off untuk mematikan)\n" +
+			"/zonawaktu WITA - zona waktu jam tenang (WIB, WITA, WIT)\n" +
+			"/bahasa en - bahasa pesan (id, en)"},
+		{Key: KeyBotPreferencesHelp, Language: LanguageEnglish, Body: "Manage your notifications:\n" +
+			"/preferensi - show settings\n" +
+			"/minmag 5 - only M5 and above (off for all)\n" +
+			"/minmmi 4 - only shaking of MMI IV and above at your location\n" +
+			"/radius 100 - only quakes within 100 km of your locations (off for all)\n" +
+			"/sunyi 22:00-06:00 - quiet hours, except danger alerts (off to disable)\n" +
+			"/zonawaktu WITA - time zone of the quiet hours (WIB, WITA, WIT)\n" +
+			"/bahasa id - message language (id, en)"},
+		{Key: KeyBotPreferencesError, Language: LanguageIndonesian, Body: "Pengaturan tidak valid: {{.Error}}"},
+		{Key: KeyBotPreferencesError, Language: LanguageEnglish, Body: "Invalid setting: {{.Error}}"},
+		{Key: KeyBotUsageNumber, Language: LanguageIndonesian, Body: "Tulis angka, misalnya /{{.Command}} 5"},
+		{Key: KeyBotUsageNumber, Language: LanguageEnglish, Body: "Write a number, e.g. /{{.Command}} 5"},
+		{Key: KeyBotUsageQuiet, Language: LanguageIndonesian, Body: "Tulis jam tenang, misalnya /sunyi 22:00-06:00"},
+		{Key: KeyBotUsageQuiet, Language: LanguageEnglish, Body: "Write the quiet hours, e.g. /sunyi 22:00-06:00"},
+		{Key: KeyBotUsageTimezone, Language: LanguageIndonesian, Body: "Tulis zona waktu, misalnya /zonawaktu WITA"},
+		{Key: KeyBotUsageTimezone, Language: LanguageEnglish, Body: "Write a time zone, e.g. /zonawaktu WITA"},
+		{Key: KeyBotUsageLanguage, Language: LanguageIndonesian, Body: "Tulis kode bahasa: id atau en, misalnya /bahasa en"},
+		{Key: KeyBotUsageLanguage, Language: LanguageEnglish, Body: "Write a language code: id or en, e.g. /bahasa id"},
+
+		{Key: KeyBotCommands, Language: LanguageIndonesian, Body: "start - Mulai dan tampilkan bantuan\n" +
+			"lokasi - Lihat lokasi tersimpan\n" +
+			"status - Lihat lokasi dan pengaturan\n" +
+			"terkini - Gempa terkini\n" +
+			"hariini - Gempa hari ini\n" +
+			"radius - Batasi jarak gempa, misalnya /radius 100\n" +
+			"bahasa - Ganti bahasa pesan (id, en)\n" +
+			"preferensi - Lihat dan atur notifikasi\n" +
+			"hapuslokasi - Hapus lokasi tersimpan\n" +
+			"gabung - Bergabung ke grup check-in, misalnya /gabung KODE Nama\n" +
+			"hapus - Berhenti berlangganan dan hapus data"},
+		{Key: KeyBotCommands, Language: LanguageEnglish, Body: "start - Start and show help\n" +
+			"lokasi - Show saved locations\n" +
+			"status - Show locations and settings\n" +
+			"terkini - Latest earthquake\n" +
+			"hariini - Today's earthquakes\n" +
+			"radius - Limit the quake distance, e.g. /radius 100\n" +
+			"bahasa - Change the message language (id, en)\n" +
+			"preferensi - Show and manage notifications\n" +
+			"hapuslokasi - Remove a saved location\n" +
+			"gabung - Join a check-in group, e.g. /gabung CODE Name\n" +
+			"hapus - Unsubscribe and delete your data"},
+		{Key: KeyBotUnsubscribeAsk, Language: LanguageIndonesian, Body: "Semua lokasi dan pengaturan Anda akan dihapus dan Anda tidak lagi menerima notifikasi gempa. Kirim /hapus ya untuk melanjutkan."},
+		{Key: KeyBotUnsubscribeAsk, Language: LanguageEnglish, Body: "All your locations and settings will be deleted and you will no longer receive earthquake notifications. Send /hapus ya to continue."},
+		{Key: KeyBotUnsubscribed, Language: LanguageIndonesian, Body: "Data Anda telah dihapus. Kirim lokasi kapan saja untuk berlangganan lagi."},
+		{Key: KeyBotUnsubscribed, Language: LanguageEnglish, Body: "Your data has been deleted. Send a location at any time to subscribe again."},
+		{Key: KeyBotLatest, Language: LanguageIndonesian, Body: "Gempa terkini M{{.Magnitude}}" +
+			"\nWaktu: {{.Tanggal}} {{.Jam}}" +
+			"\nKoordinat: {{.Coordinates}}" +
+			"\nKedalaman: {{.Kedalaman}}" +
+			"\nWilayah: {{.Wilayah}}"},
+		{Key: KeyBotLatest, Language: LanguageEnglish, Body: "Latest earthquake M{{.Magnitude}}" +
+			"\nTime: {{.Tanggal}} {{.Jam}}" +
+			"\nCoordinates: {{.Coordinates}}" +
+			"\nDepth: {{.Kedalaman}}" +
+			"\nRegion: {{.Wilayah}}"},
+		{Key: KeyBotToday, Language: LanguageIndonesian, Body: "{{if .Gempa}}Gempa hari ini ({{len .Gempa}}):{{range .Gempa}}\n- {{.Jam}} M{{.Magnitude}}, {{.Kedalaman}}, {{.Wilayah}}{{end}}" +
+			"{{else}}Belum ada gempa tercatat hari ini.{{end}}"},
+		{Key: KeyBotToday, Language: LanguageEnglish, Body: "{{if .Gempa}}Earthquakes today ({{len .Gempa}}):{{range .Gempa}}\n- {{.Jam}} M{{.Magnitude}}, {{.Kedalaman}}, {{.Wilayah}}{{end}}" +
+			"{{else}}No earthquakes recorded today yet.{{end}}"},
+		{Key: KeyBotNoEarthquake, Language: LanguageIndonesian, Body: "Belum ada data gempa."},
+		{Key: KeyBotNoEarthquake, Language: LanguageEnglish, Body: "No earthquake data yet."},
+
+		// tombol di bawah alert Telegram
+		{Key: KeyBotEpicenter, Language: LanguageIndonesian, Body: "Episenter gempa"},
+		{Key: KeyBotEpicenter, Language: LanguageEnglish, Body: "Earthquake epicenter"},
+		{Key: KeyBotButtonSafe, Language: LanguageIndonesian, Body: "Saya aman"},
+		{Key: KeyBotButtonSafe, Language: LanguageEnglish, Body: "I'm safe"},
+		{Key: KeyBotButtonFelt, Language: LanguageIndonesian, Body: "Terasa?"},
+		{Key: KeyBotButtonFelt, Language: LanguageEnglish, Body: "Felt it?"},
+		{Key: KeyBotButtonDetail, Language: LanguageIndonesian, Body: "Detail"},
+		{Key: KeyBotButtonDetail, Language: LanguageEnglish, Body: "Details"},
+		{Key: KeyBotSafeThanks, Language: LanguageIndonesian, Body: "Terima kasih, semoga Anda tetap aman."},
+		{Key: KeyBotSafeThanks, Language: LanguageEnglish, Body: "Thank you, stay safe."},
+		{Key: KeyBotFeltThanks, Language: LanguageIndonesian, Body: "Terima kasih atas laporan Anda dari {{.Label}} (MMI {{.MMIRoman}})."},
+		{Key: KeyBotFeltThanks, Language: LanguageEnglish, Body: "Thank you for your report from {{.Label}} (MMI {{.MMIRoman}})."},
+		{Key: KeyBotFeltAsk, Language: LanguageIndonesian, Body: "Apakah Anda merasakan gempa ini?"},
+		{Key: KeyBotFeltAsk, Language: LanguageEnglish, Body: "Did you feel this earthquake?"},
+		{Key: KeyBotFeltAnswers, Language: LanguageIndonesian, Body: "Ya, terasa\nTidak terasa"},
+		{Key: KeyBotFeltAnswers, Language: LanguageEnglish, Body: "Yes, I felt it\nNo, I did not"},
+		{Key: KeyBotFeltShaking, Language: LanguageIndonesian, Body: "Seberapa kuat guncangannya?"},
+		{Key: KeyBotFeltShaking, Language: LanguageEnglish, Body: "How strong was the shaking?"},
+		{Key: KeyBotFeltShakingOptions, Language: LanguageIndonesian, Body: "Lemah\nSedang\nKuat\nSangat kuat"},
+		{Key: KeyBotFeltShakingOptions, Language: LanguageEnglish, Body: "Weak\nModerate\nStrong\nViolent"},
+		{Key: KeyBotFeltEffect, Language: LanguageIndonesian, Body: "Apa yang terjadi di sekitar Anda?"},
+		{Key: KeyBotFeltEffect, Language: LanguageEnglish, Body: "What happened around you?"},
+		{Key: KeyBotFeltEffectOptions, Language: LanguageIndonesian, Body: "Tidak ada\nBenda tergantung bergoyang\nBenda jatuh atau pecah\nSulit berdiri atau berjalan\nBangunan retak atau rusak"},
+		{Key: KeyBotFeltEffectOptions, Language: LanguageEnglish, Body: "Nothing\nHanging objects swung\nObjects fell or broke\nHard to stand or walk\nBuildings cracked or damaged"},
+		{Key: KeyBotDetail, Language: LanguageIndonesian, Body: "Detail gempa M{{.Magnitude}}" +
+			"\nWaktu: {{.Tanggal}} {{.Jam}}" +
+			"\nKoordinat: {{.Coordinates}}" +
+			"\nKedalaman: {{.Kedalaman}}" +
+			"\nWilayah: {{.Wilayah}}" +
+			"{{if .Potensi}}\nPotensi: {{.Potensi}}{{end}}" +
+			"{{if .Dirasakan}}\nDirasakan: {{.Dirasakan}}{{end}}"},
+		{Key: KeyBotDetail, Language: LanguageEnglish, Body: "Earthquake details M{{.Magnitude}}" +
+			"\nTime: {{.Tanggal}} {{.Jam}}" +
+			"\nCoordinates: {{.Coordinates}}" +
+			"\nDepth: {{.Kedalaman}}" +
+			"\nRegion: {{.Wilayah}}" +
+			"{{if .Potensi}}\nPotential: {{.Potensi}}{{end}}" +
+			"{{if .Dirasakan}}\nFelt: {{.Dirasakan}}{{end}}"},
+		// safety check-in of group members
+		{Key: KeyCheckin, Language: LanguageIndonesian, Body: "{{if .Reminder}}[PENGINGAT] {{end}}Apakah Anda aman?" +
+			"\nGempa M{{.Magnitude}} {{.Wilayah}}, perkiraan guncangan di lokasi Anda MMI {{.MMIRoman}}." +
+			"\nKabari grup Anda: {{.URL}}"},
+		{Key: KeyCheckin, Language: LanguageEnglish, Body: "{{if .Reminder}}[REMINDER] {{end}}Are you safe?" +
+			"\nM{{.Magnitude}} earthquake {{.Wilayah}}, estimated shaking at your location MMI {{.MMIRoman}}." +
+			"\nLet your group know: {{.URL}}"},
+		{Key: KeyCheckin, Channel: "telegram", Language: LanguageIndonesian, Body: "{{if .Reminder}}[PENGINGAT] {{end}}Apakah Anda aman?" +
+			"\nGempa M{{.Magnitude}} {{.Wilayah}}, perkiraan guncangan di lokasi Anda MMI {{.MMIRoman}}." +
+			"\nKabari grup Anda dengan tombol di bawah."},
+		{Key: KeyCheckin, Channel: "telegram", Language: LanguageEnglish, Body: "{{if .Reminder}}[REMINDER] {{end}}Are you safe?" +
+			"\nM{{.Magnitude}} earthquake {{.Wilayah}}, estimated shaking at your location MMI {{.MMIRoman}}." +
+			"\nLet your group know with the buttons below."},
+		{Key: KeyBotButtonCheckinSafe, Language: LanguageIndonesian, Body: "Aman"},
+		{Key: KeyBotButtonCheckinSafe, Language: LanguageEnglish, Body: "Safe"},
+		{Key: KeyBotButtonCheckinNeedHelp, Language: LanguageIndonesian, Body: "Butuh bantuan"},
+		{Key: KeyBotButtonCheckinNeedHelp, Language: LanguageEnglish, Body: "Need help"},
+		{Key: KeyBotCheckinRecorded, Language: LanguageIndonesian, Body: "{{if eq .Status \"need_help\"}}Status Anda: butuh bantuan. Grup Anda sudah diberi tahu." +
+			"{{else}}Status Anda: aman. Terima kasih sudah mengabari grup Anda.{{end}}"},
+		{Key: KeyBotCheckinRecorded, Language: LanguageEnglish, Body: "{{if eq .Status \"need_help\"}}Your status: need help. Your group has been informed." +
+			"{{else}}Your status: safe. Thank you for letting your group know.{{end}}"},
+		{Key: KeyBotCheckinUnknown, Language: LanguageIndonesian, Body: "Check-in ini tidak ditemukan."},
+		{Key: KeyBotCheckinUnknown, Language: LanguageEnglish, Body: "This check-in was not found."},
+		{Key: KeyBotGroupUsage, Language: LanguageIndonesian, Body: "Kirim /gabung KODE Nama, misalnya /gabung AB12CD34 Budi. Kode undangan didapat dari pengelola grup."},
+		{Key: KeyBotGroupUsage, Language: LanguageEnglish, Body: "Send /gabung CODE Name, e.g. /gabung AB12CD34 Budi. The invite code is given by the group owner."},
+		{Key: KeyBotGroupJoined, Language: LanguageIndonesian, Body: "Anda bergabung ke grup \"{{.Group}}\". Setelah gempa kuat Anda akan diminta melapor apakah Anda aman."},
+		{Key: KeyBotGroupJoined, Language: LanguageEnglish, Body: "You joined the group \"{{.Group}}\". After a strong earthquake you will be asked whether you are safe."},
+		{Key: KeyBotGroupNotFound, Language: LanguageIndonesian, Body: "Kode undangan tidak ditemukan."},
+		{Key: KeyBotGroupNotFound, Language: LanguageEnglish, Body: "Invite code not found."},
+		// alert email
+		{Key: KeyEmailSubject, Language: LanguageIndonesian, Body: "{{.Title}}"},
+		{Key: KeyEmailSubject, Language: LanguageEnglish, Body: "{{.Title}}"},
+		{Key: KeyEmailMapLink, Language: LanguageIndonesian, Body: "Lihat episenter di peta"},
+		{Key: KeyEmailMapLink, Language: LanguageEnglish, Body: "View the epicenter on a map"},
+		{Key: KeyEmailShakemap, Language: LanguageIndonesian, Body: "Peta guncangan (shakemap) terlampir"},
+		{Key: KeyEmailShakemap, Language: LanguageEnglish, Body: "Shakemap attached"},
+		{Key: KeyEmailFooter, Language: LanguageIndonesian, Body: "Pesan ini dikirim otomatis oleh sistem peringatan gempa berdasarkan data BMKG."},
+		{Key: KeyEmailFooter, Language: LanguageEnglish, Body: "This message was sent automatically by the earthquake alert system based on BMKG data."},
+	}
+}
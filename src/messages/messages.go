@@ -0,0 +1,197 @@
+package messages
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+	"text/template"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// collection stores the templates edited by admins
+const collection = "message_template"
+
+// Languages of the message templates, see the language select of message_template
+const (
+	LanguageIndonesian = "id"
+	LanguageEnglish    = "en"
+
+	// DefaultLanguage is used for subscribers without a language and as the last fallback
+	DefaultLanguage = LanguageIndonesian
+)
+
+// Languages are the supported language codes
+var Languages = []string{LanguageIndonesian, LanguageEnglish}
+
+// Template is one message wording. An empty Channel or Tier applies to every channel or tier.
+type Template struct {
+	Key      string
+	Channel  string
+	Tier     string
+	Language string
+	Body     string
+}
+
+// Selector picks the template variant for one recipient
+type Selector struct {
+	Channel  string
+	Tier     string
+	Language string
+}
+
+type templateKey struct {
+	key, channel, tier, language string
+}
+
+// catalog holds the built-in templates and the ones stored in message_template
+type catalog struct {
+	mu        sync.RWMutex
+	builtin   map[templateKey]*template.Template
+	overrides map[templateKey]*template.Template
+}
+
+var defaultCatalog = &catalog{
+	builtin:   mustCompile(Defaults()),
+	overrides: make(map[templateKey]*template.Template),
+}
+
+// IsLanguage reports whether code is a supported language
+func IsLanguage(code string) bool {
+	return slices.Contains(Languages, code)
+}
+
+// Parse checks that body is a valid text/template
+func Parse(body string) (*template.Template, error) {
+	return template.New("message").Option("missingkey=zero").Parse(body)
+}
+
+// Render fills the template of key for a recipient. The lookup falls back in this order:
+//   - the language of the selector, then DefaultLanguage
+//   - within a language: channel and tier, tier only, channel only, then neither
+//   - at each step a template from message_template before the built-in one
+//
+// A stored template that fails to execute is logged and skipped.
+func Render(key string, selector Selector, data interface{}) (string, error) {
+	return defaultCatalog.render(key, selector, data)
+}
+
+func (c *catalog) render(key string, selector Selector, data interface{}) (string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, language := range languageChain(selector.Language) {
+		candidates := []templateKey{
+			{key, selector.Channel, selector.Tier, language},
+			{key, "", selector.Tier, language},
+			{key, selector.Channel, "", language},
+			{key, "", "", language},
+		}
+
+		for _, candidate := range candidates {
+			for _, set := range []map[templateKey]*template.Template{c.overrides, c.builtin} {
+				tmpl, ok := set[candidate]
+				if !ok {
+					continue
+				}
+
+				var buf bytes.Buffer
+				if err := tmpl.Execute(&buf, data); err != nil {
+					log.Printf("Failed to render message template %s (%s): %v", key, language, err)
+					continue
+				}
+				return buf.String(), nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("no message template for %s", key)
+}
+
+// languageChain is the requested language followed by the default language
+func languageChain(language string) []string {
+	if language == "" || language == DefaultLanguage || !IsLanguage(language) {
+		return []string{DefaultLanguage}
+	}
+	return []string{language, DefaultLanguage}
+}
+
+// Bind keeps the templates in sync with message_template and refuses invalid templates,
+// so admins can change the wording in the dashboard without a redeploy
+func Bind(app core.App) {
+	app.OnRecordValidate(collection).BindFunc(func(e *core.RecordEvent) error {
+		if _, err := Parse(e.Record.GetString("body")); err != nil {
+			return validation.Errors{"body": validation.NewError("validation_invalid_template", err.Error())}
+		}
+		return e.Next()
+	})
+
+	reload := func(e *core.RecordEvent) error {
+		if err := Load(e.App); err != nil {
+			log.Printf("Error reloading message templates: %v", err)
+		}
+		return e.Next()
+	}
+	app.OnRecordAfterCreateSuccess(collection).BindFunc(reload)
+	app.OnRecordAfterUpdateSuccess(collection).BindFunc(reload)
+	app.OnRecordAfterDeleteSuccess(collection).BindFunc(reload)
+}
+
+// Load replaces the stored templates with the content of message_template
+func Load(app core.App) error {
+	records, err := app.FindAllRecords(collection)
+	if err != nil {
+		return fmt.Errorf("failed to load message templates: %w", err)
+	}
+
+	overrides := make(map[templateKey]*template.Template, len(records))
+	for _, record := range records {
+		key := templateKey{
+			key:      record.GetString("key"),
+			channel:  record.GetString("channel"),
+			tier:     record.GetString("tier"),
+			language: record.GetString("language"),
+		}
+
+		tmpl, err := Parse(record.GetString("body"))
+		if err != nil {
+			log.Printf("Skipping message template %s: %v", record.Id, err)
+			continue
+		}
+		overrides[key] = tmpl
+	}
+
+	defaultCatalog.mu.Lock()
+	defaultCatalog.overrides = overrides
+	defaultCatalog.mu.Unlock()
+
+	log.Printf("Loaded %d message templates", len(overrides))
+	return nil
+}
+
+func mustCompile(list []Template) map[templateKey]*template.Template {
+	compiled := make(map[templateKey]*template.Template, len(list))
+	for _, item := range list {
+		tmpl, err := Parse(item.Body)
+		if err != nil {
+			panic(fmt.Sprintf("invalid built-in message template %s: %v", item.Key, err))
+		}
+		compiled[templateKey{item.Key, item.Channel, item.Tier, item.Language}] = tmpl
+	}
+	return compiled
+}
+
+// Lines splits a rendered list template into its non-empty lines, e.g. keyboard buttons
+func Lines(text string) []string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
@@ -1,9 +1,13 @@
 package repository
 
 import (
+	"bmkg/src/db"
 	"bmkg/src/domain"
+	"database/sql"
 	"fmt"
 	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/types"
+	"time"
 
 	"github.com/pocketbase/pocketbase/core"
 )
@@ -19,11 +23,11 @@ func NewBMKGRepository(app core.App) *BMKG {
 	}
 }
 
-// SaveGempa saves earthquake data to the database
-func (r *BMKG) SaveGempa(data map[string]interface{}) error {
+// SaveGempa saves earthquake data to the database and returns the new record id
+func (r *BMKG) SaveGempa(data map[string]interface{}) (string, error) {
 	collection, err := r.App.FindCollectionByNameOrId("earthquake")
 	if err != nil {
-		return fmt.Errorf("collection not found: %w", err)
+		return "", fmt.Errorf("collection not found: %w", err)
 	}
 
 	// Create a new record
@@ -39,19 +43,103 @@ func (r *BMKG) SaveGempa(data map[string]interface{}) error {
 
 	// Save the record
 	if err := r.App.Save(record); err != nil {
-		return fmt.Errorf("failed to save record: %w", err)
+		return "", fmt.Errorf("failed to save record: %w", err)
+	}
+
+	return record.Id, nil
+}
+
+// SaveRevision stores one version of an earthquake in its revision history
+func (r *BMKG) SaveRevision(data map[string]interface{}) error {
+	collection, err := r.App.FindCachedCollectionByNameOrId("earthquake_revision")
+	if err != nil {
+		return fmt.Errorf("collection not found: %w", err)
+	}
+
+	record := core.NewRecord(collection)
+	for key, value := range data {
+		record.Set(key, value)
+	}
+
+	if err := r.App.Save(record); err != nil {
+		return fmt.Errorf("failed to save revision: %w", err)
+	}
+
+	return nil
+}
+
+// GetRevisions retrieves the revision history of an earthquake, oldest first
+func (r *BMKG) GetRevisions(earthquakeID string) ([]*db.EarthquakeRevision, error) {
+	var revisions []*db.EarthquakeRevision
+
+	err := r.App.RecordQuery("earthquake_revision").
+		AndWhere(dbx.HashExp{"earthquake": earthquakeID}).
+		OrderBy("revision ASC").
+		All(&revisions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch earthquake revisions: %w", err)
+	}
+
+	return revisions, nil
+}
+
+// UpdateGempa overwrites the given fields of an existing earthquake record
+func (r *BMKG) UpdateGempa(id string, data map[string]interface{}) error {
+	record, err := r.App.FindRecordById("earthquake", id)
+	if err != nil {
+		return fmt.Errorf("earthquake %s not found: %w", id, err)
+	}
+
+	for key, value := range data {
+		record.Set(key, value)
+	}
+
+	if err := r.App.Save(record); err != nil {
+		return fmt.Errorf("failed to update record: %w", err)
 	}
 
 	return nil
 }
 
+// GetRecentRecords retrieves the earthquake records created after since, newest first
+func (r *BMKG) GetRecentRecords(since time.Time) ([]*core.Record, error) {
+	records, err := r.App.FindRecordsByFilter(
+		"earthquake",
+		"created >= {:since}",
+		"-created",
+		0,
+		0,
+		dbx.Params{"since": since.UTC().Format(types.DefaultDateLayout)},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch recent earthquakes: %w", err)
+	}
+
+	return records, nil
+}
+
+// ExistsFingerprint reports whether an earthquake with the given fingerprint was already stored
+func (r *BMKG) ExistsFingerprint(fingerprint string) (bool, error) {
+	total, err := r.App.CountRecords("earthquake", dbx.HashExp{"fingerprint": fingerprint})
+	if err != nil {
+		return false, fmt.Errorf("failed to check earthquake fingerprint: %w", err)
+	}
+
+	return total > 0, nil
+}
+
+// gempaColumns selects the earthquake fields of domain.Gempa. Magnitude is the text as
+// reported by BMKG, e.g. "5.0", the numeric magnitude column is only used for filtering.
+const gempaColumns = "id, Tanggal AS tanggal, Jam AS jam, MagnitudeText AS magnitude, Kedalaman AS kedalaman, " +
+	"Wilayah AS wilayah, Coordinates AS coordinates, created"
+
 // GetAllGempa retrieves all earthquake data from the database
 func (r *BMKG) GetAllGempa() ([]domain.Gempa, error) {
 
 	var earthquakes []domain.Gempa
 
 	err := r.App.DB().
-		NewQuery("SELECT id, tanggal, jam, magnitude, kedalaman, wilayah, coordinates, created FROM earthquake ORDER BY created DESC").
+		NewQuery("SELECT " + gempaColumns + " FROM earthquake ORDER BY origin_time DESC").
 		All(&earthquakes)
 
 	if err != nil {
@@ -84,7 +172,7 @@ func (r *BMKG) GetLastGempa() (domain.Gempa, error) {
 	var gempa domain.Gempa
 
 	err := r.App.DB().
-		NewQuery("SELECT id, tanggal, jam, magnitude, kedalaman, wilayah, coordinates, created FROM earthquake ORDER BY created DESC LIMIT 1").
+		NewQuery("SELECT " + gempaColumns + " FROM earthquake ORDER BY origin_time DESC LIMIT 1").
 		One(&gempa)
 
 	if err != nil {
@@ -95,11 +183,12 @@ func (r *BMKG) GetLastGempa() (domain.Gempa, error) {
 }
 
 // get gempa cari yang unik group by Shakemap biar unik gempa hari ini , intinya cari gempa hari ini
+// "hari ini" mengikuti WIB, origin_time disimpan dalam UTC
 func (r *BMKG) GetGempaHariIni() ([]domain.Gempa, error) {
 	var gempa []domain.Gempa
 
 	err := r.App.DB().
-		NewQuery("SELECT id, tanggal, jam, magnitude, kedalaman, wilayah, coordinates, created FROM earthquake WHERE date(created) = date('now') GROUP BY shakemap ORDER BY created DESC").
+		NewQuery("SELECT " + gempaColumns + " FROM earthquake WHERE date(origin_time, '+7 hours') = date('now', '+7 hours') GROUP BY COALESCE(NULLIF(shakemap, ''), id) ORDER BY origin_time DESC").
 		All(&gempa)
 
 	if err != nil {
@@ -108,3 +197,47 @@ func (r *BMKG) GetGempaHariIni() ([]domain.Gempa, error) {
 
 	return gempa, nil
 }
+
+// GetLastEarthquake retrieves the most recent earthquake with its typed fields
+func (r *BMKG) GetLastEarthquake() (*db.Earthquake, error) {
+	earthquakes, err := r.FindEarthquakes(0, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(earthquakes) == 0 {
+		return nil, fmt.Errorf("failed to fetch latest earthquake: %w", sql.ErrNoRows)
+	}
+
+	return earthquakes[0], nil
+}
+
+// GetEarthquake retrieves one earthquake by its record id
+func (r *BMKG) GetEarthquake(id string) (*db.Earthquake, error) {
+	record, err := r.App.FindRecordById("earthquake", id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch earthquake %s: %w", id, err)
+	}
+
+	earthquake := &db.Earthquake{}
+	earthquake.SetProxyRecord(record)
+	return earthquake, nil
+}
+
+// FindEarthquakes retrieves the earthquakes with at least minMagnitude, newest origin time first.
+// A limit of 0 returns every match.
+func (r *BMKG) FindEarthquakes(minMagnitude float64, limit int) ([]*db.Earthquake, error) {
+	var earthquakes []*db.Earthquake
+
+	query := r.App.RecordQuery("earthquake").
+		AndWhere(dbx.NewExp("magnitude >= {:magnitude}", dbx.Params{"magnitude": minMagnitude})).
+		OrderBy("origin_time DESC")
+	if limit > 0 {
+		query = query.Limit(int64(limit))
+	}
+
+	if err := query.All(&earthquakes); err != nil {
+		return nil, fmt.Errorf("failed to fetch earthquakes: %w", err)
+	}
+
+	return earthquakes, nil
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"bmkg/src/db"
+	"bmkg/src/domain"
+	"bmkg/src/utils"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+	"time"
+)
+
+// FeltGeohashPrecision is the size of the community intensity cells, about 4.9 x 4.9 km
+const FeltGeohashPrecision = 5
+
+// Felt repository for the "did you feel it?" reports and the community intensity
+// aggregated from them
+type Felt struct {
+	App core.App
+}
+
+// NewFeltRepository creates a new Felt repository
+func NewFeltRepository(app core.App) *Felt {
+	return &Felt{
+		App: app,
+	}
+}
+
+// SaveReport stores the answers of a subscriber at lat, lon. A subscriber has one report
+// per earthquake, answering again replaces it.
+func (r *Felt) SaveReport(subscriber *db.UserNotify, earthquakeID string, answers domain.FeltAnswers, lat, lon float64) (*db.FeltReport, error) {
+	if err := answers.Validate(); err != nil {
+		return nil, err
+	}
+	if lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+		return nil, fmt.Errorf("%w: coordinates out of range", ErrInvalidLocation)
+	}
+
+	report := &db.FeltReport{}
+	err := r.App.RecordQuery("felt_report").
+		AndWhere(dbx.HashExp{"earthquake": earthquakeID, "subscriber": subscriber.Id}).
+		Limit(1).
+		One(report)
+	if errors.Is(err, sql.ErrNoRows) {
+		report, err = db.NewProxy[db.FeltReport](r.App)
+		if err != nil {
+			return nil, fmt.Errorf("collection not found: %w", err)
+		}
+		report.Set("earthquake", earthquakeID)
+		report.Set("subscriber", subscriber.Id)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to fetch felt report: %w", err)
+	}
+
+	report.SetFelt(answers.Felt)
+	report.Set("shaking", answers.Shaking)
+	report.Set("effect", answers.Effect)
+	report.SetMmi(answers.MMI())
+	report.SetLat(lat)
+	report.SetLon(lon)
+	report.SetGeohash(utils.EncodeGeohash(lat, lon, FeltGeohashPrecision))
+
+	if err := r.App.Save(report); err != nil {
+		return nil, fmt.Errorf("failed to save felt report: %w", err)
+	}
+	return report, nil
+}
+
+// ReportedSince returns the ids of the earthquakes with reports created or changed after since
+func (r *Felt) ReportedSince(since time.Time) ([]string, error) {
+	var ids []string
+
+	err := r.App.DB().
+		Select("earthquake").
+		Distinct(true).
+		From("felt_report").
+		Where(dbx.NewExp("updated > {:since}", dbx.Params{"since": since.UTC().Format(types.DefaultDateLayout)})).
+		Column(&ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch reported earthquakes: %w", err)
+	}
+
+	return ids, nil
+}
+
+// Reports retrieves every report of an earthquake
+func (r *Felt) Reports(earthquakeID string) ([]*db.FeltReport, error) {
+	var reports []*db.FeltReport
+
+	err := r.App.RecordQuery("felt_report").
+		AndWhere(dbx.HashExp{"earthquake": earthquakeID}).
+		All(&reports)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch felt reports: %w", err)
+	}
+
+	return reports, nil
+}
+
+// ReplaceIntensity replaces the community intensity of an earthquake with cells
+func (r *Felt) ReplaceIntensity(earthquakeID string, cells []domain.FeltCell) error {
+	return r.App.RunInTransaction(func(txApp core.App) error {
+		var existing []*db.FeltIntensity
+		err := txApp.RecordQuery("felt_intensity").
+			AndWhere(dbx.HashExp{"earthquake": earthquakeID}).
+			All(&existing)
+		if err != nil {
+			return fmt.Errorf("failed to fetch felt intensity: %w", err)
+		}
+
+		for _, intensity := range existing {
+			if err := txApp.Delete(intensity); err != nil {
+				return fmt.Errorf("failed to delete felt intensity: %w", err)
+			}
+		}
+
+		for _, cell := range cells {
+			intensity, err := db.NewProxy[db.FeltIntensity](txApp)
+			if err != nil {
+				return fmt.Errorf("collection not found: %w", err)
+			}
+			intensity.Set("earthquake", earthquakeID)
+			intensity.SetGeohash(cell.Geohash)
+			intensity.SetLat(cell.Lat)
+			intensity.SetLon(cell.Lon)
+			intensity.SetMmi(cell.MMI)
+			intensity.SetReports(cell.Reports)
+
+			if err := txApp.Save(intensity); err != nil {
+				return fmt.Errorf("failed to save felt intensity: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// Intensity retrieves the community intensity cells of an earthquake
+func (r *Felt) Intensity(earthquakeID string) ([]*db.FeltIntensity, error) {
+	var cells []*db.FeltIntensity
+
+	err := r.App.RecordQuery("felt_intensity").
+		AndWhere(dbx.HashExp{"earthquake": earthquakeID}).
+		OrderBy("geohash ASC").
+		All(&cells)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch felt intensity: %w", err)
+	}
+
+	return cells, nil
+}
@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"bmkg/src/db"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+	"time"
+)
+
+// Outbox repository for planned notification deliveries and their attempts
+type Outbox struct {
+	App core.App
+}
+
+// NewOutboxRepository creates a new Outbox repository
+func NewOutboxRepository(app core.App) *Outbox {
+	return &Outbox{
+		App: app,
+	}
+}
+
+// Enqueue stores a planned delivery. It reports false when a delivery with the same
+// dedupe key already exists, so the same alert is never planned twice.
+func (r *Outbox) Enqueue(data map[string]interface{}) (bool, error) {
+	dedupeKey, _ := data["dedupe_key"].(string)
+
+	exists, err := r.existsDedupeKey(dedupeKey)
+	if err != nil {
+		return false, err
+	}
+	if exists {
+		return false, nil
+	}
+
+	collection, err := r.App.FindCachedCollectionByNameOrId("notification_outbox")
+	if err != nil {
+		return false, fmt.Errorf("collection not found: %w", err)
+	}
+
+	record := core.NewRecord(collection)
+	for key, value := range data {
+		record.Set(key, value)
+	}
+
+	if err := r.App.Save(record); err != nil {
+		return false, fmt.Errorf("failed to enqueue notification: %w", err)
+	}
+
+	return true, nil
+}
+
+func (r *Outbox) existsDedupeKey(dedupeKey string) (bool, error) {
+	_, err := r.App.FindFirstRecordByData("notification_outbox", "dedupe_key", dedupeKey)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to check dedupe key: %w", err)
+	}
+	return true, nil
+}
+
+// Due retrieves pending deliveries of a channel whose next attempt is due.
+// The highest estimated intensity comes first, then the closest recipient.
+func (r *Outbox) Due(channel string, now time.Time, limit int) ([]*db.NotificationOutbox, error) {
+	return r.due(dbx.HashExp{"channel": channel}, now, limit)
+}
+
+// DueExcept is Due for every channel not listed in channels
+func (r *Outbox) DueExcept(channels []string, now time.Time, limit int) ([]*db.NotificationOutbox, error) {
+	values := make([]interface{}, len(channels))
+	for i, channel := range channels {
+		values[i] = channel
+	}
+	return r.due(dbx.NotIn("channel", values...), now, limit)
+}
+
+func (r *Outbox) due(filter dbx.Expression, now time.Time, limit int) ([]*db.NotificationOutbox, error) {
+	var deliveries []*db.NotificationOutbox
+
+	nowDateTime, _ := types.ParseDateTime(now)
+	err := r.App.RecordQuery("notification_outbox").
+		AndWhere(dbx.HashExp{"status": "pending"}).
+		AndWhere(filter).
+		AndWhere(dbx.NewExp("next_attempt_at <= {:now}", dbx.Params{"now": nowDateTime.String()})).
+		OrderBy("mmi DESC", "distance_km ASC", "next_attempt_at ASC").
+		Limit(int64(limit)).
+		All(&deliveries)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch due notifications: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// CountPending returns the number of pending deliveries per channel
+func (r *Outbox) CountPending() (map[string]int, error) {
+	var rows []struct {
+		Channel string `db:"channel"`
+		Count   int    `db:"count"`
+	}
+
+	err := r.App.DB().
+		Select("channel", "COUNT(*) as count").
+		From("notification_outbox").
+		Where(dbx.HashExp{"status": "pending"}).
+		GroupBy("channel").
+		All(&rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count pending notifications: %w", err)
+	}
+
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.Channel] = row.Count
+	}
+	return counts, nil
+}
+
+// FindByStatus retrieves every delivery with the given status
+func (r *Outbox) FindByStatus(status string) ([]*db.NotificationOutbox, error) {
+	var deliveries []*db.NotificationOutbox
+
+	err := r.App.RecordQuery("notification_outbox").
+		AndWhere(dbx.HashExp{"status": status}).
+		All(&deliveries)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s notifications: %w", status, err)
+	}
+
+	return deliveries, nil
+}
+
+// Save stores the changes of a delivery
+func (r *Outbox) Save(delivery *db.NotificationOutbox) error {
+	if err := r.App.Save(delivery); err != nil {
+		return fmt.Errorf("failed to save notification %s: %w", delivery.Id, err)
+	}
+	return nil
+}
+
+// SaveAttempt records the outcome of one delivery attempt
+func (r *Outbox) SaveAttempt(data map[string]interface{}) error {
+	collection, err := r.App.FindCachedCollectionByNameOrId("notification_attempt")
+	if err != nil {
+		return fmt.Errorf("collection not found: %w", err)
+	}
+
+	record := core.NewRecord(collection)
+	for key, value := range data {
+		record.Set(key, value)
+	}
+
+	if err := r.App.Save(record); err != nil {
+		return fmt.Errorf("failed to save notification attempt: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateReceipt stores a delivery receipt of the provider on the matching delivery.
+// Receipts for unknown message ids are ignored.
+func (r *Outbox) UpdateReceipt(messageID, status, errorText string) error {
+	var delivery db.NotificationOutbox
+	err := r.App.RecordQuery("notification_outbox").
+		AndWhere(dbx.HashExp{"provider_message_id": messageID}).
+		Limit(1).
+		One(&delivery)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return fmt.Errorf("failed to find notification %s: %w", messageID, err)
+	}
+
+	delivery.SetProviderStatus(status)
+	if errorText != "" {
+		delivery.SetLastError(errorText)
+	}
+
+	return r.Save(&delivery)
+}
+
+// Replay puts the deliveries of a recipient created since the given time back into the
+// outbox, including those already sent, so the partner receives them again
+func (r *Outbox) Replay(channel, recipient string, since time.Time) (int, error) {
+	var deliveries []*db.NotificationOutbox
+
+	sinceDateTime, _ := types.ParseDateTime(since)
+	err := r.App.RecordQuery("notification_outbox").
+		AndWhere(dbx.HashExp{"channel": channel, "recipient": recipient}).
+		AndWhere(dbx.NewExp("created >= {:since}", dbx.Params{"since": sinceDateTime.String()})).
+		AndWhere(dbx.NotIn("status", "sending")).
+		All(&deliveries)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch deliveries to replay: %w", err)
+	}
+
+	for _, delivery := range deliveries {
+		delivery.SetStatus(db.OutboxPending)
+		delivery.SetAttempts(0)
+		delivery.SetNextAttemptAt(types.NowDateTime())
+		delivery.SetLastError("")
+		delivery.SetProviderMessageId("")
+		delivery.SetProviderStatus("")
+		// A replayed alert is still worth receiving, the partner asked for it
+		delivery.SetExpiresAt(types.DateTime{})
+		if err := r.Save(delivery); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(deliveries), nil
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"bmkg/src/db"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// PushToken repository for the FCM device tokens of the mobile app
+type PushToken struct {
+	App core.App
+}
+
+// NewPushTokenRepository creates a new PushToken repository
+func NewPushTokenRepository(app core.App) *PushToken {
+	return &PushToken{
+		App: app,
+	}
+}
+
+// Register stores a device token for a user. A token is unique per device, so a token
+// that is already known is moved to the user and only its last_seen is refreshed.
+func (r *PushToken) Register(userID, token, platform string) error {
+	record, err := r.App.FindFirstRecordByData("push_token", "token", token)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("failed to find push token: %w", err)
+		}
+
+		collection, err := r.App.FindCachedCollectionByNameOrId("push_token")
+		if err != nil {
+			return fmt.Errorf("collection not found: %w", err)
+		}
+		record = core.NewRecord(collection)
+		record.Set("token", token)
+	}
+
+	record.Set("user", userID)
+	record.Set("platform", platform)
+	record.Set("last_seen", types.NowDateTime())
+
+	if err := r.App.Save(record); err != nil {
+		return fmt.Errorf("failed to save push token: %w", err)
+	}
+
+	return nil
+}
+
+// Tokens returns every device token of a user
+func (r *PushToken) Tokens(userID string) ([]string, error) {
+	var records []*db.PushToken
+
+	err := r.App.RecordQuery("push_token").
+		AndWhere(dbx.HashExp{"user": userID}).
+		All(&records)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch push tokens: %w", err)
+	}
+
+	tokens := make([]string, len(records))
+	for i, record := range records {
+		tokens[i] = record.Token()
+	}
+	return tokens, nil
+}
+
+// Remove deletes a device token, e.g. after FCM reported it as unregistered.
+// Removing an unknown token is not an error.
+func (r *PushToken) Remove(token string) error {
+	return r.remove(dbx.HashExp{"token": token})
+}
+
+// RemoveForUser deletes a device token only when it belongs to the user
+func (r *PushToken) RemoveForUser(userID, token string) error {
+	return r.remove(dbx.HashExp{"token": token, "user": userID})
+}
+
+func (r *PushToken) remove(filter dbx.HashExp) error {
+	var records []*db.PushToken
+
+	if err := r.App.RecordQuery("push_token").AndWhere(filter).All(&records); err != nil {
+		return fmt.Errorf("failed to find push token: %w", err)
+	}
+
+	for _, record := range records {
+		if err := r.App.Delete(record); err != nil {
+			return fmt.Errorf("failed to delete push token: %w", err)
+		}
+	}
+
+	return nil
+}
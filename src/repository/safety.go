@@ -0,0 +1,383 @@
+package repository
+
+import (
+	"bmkg/src/db"
+	"bmkg/src/domain"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/security"
+	"github.com/pocketbase/pocketbase/tools/types"
+	"strings"
+	"time"
+)
+
+// inviteAlphabet leaves out characters that are easily mistaken for each other
+const inviteAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+var (
+	// ErrCheckinNotFound is returned for an unknown check-in token
+	ErrCheckinNotFound = errors.New("check-in not found")
+	// ErrGroupNotFound is returned for an unknown invite code or a group of another owner
+	ErrGroupNotFound = errors.New("group not found")
+	// ErrInvalidGroup is returned for a group without name
+	ErrInvalidGroup = errors.New("invalid group")
+)
+
+// Safety repository for the post-quake check-ins and the groups whose owners follow them
+type Safety struct {
+	App core.App
+}
+
+// NewSafetyRepository creates a new Safety repository
+func NewSafetyRepository(app core.App) *Safety {
+	return &Safety{
+		App: app,
+	}
+}
+
+// OpenCheckin starts the check-in of a subscriber after an earthquake. A revision of the
+// earthquake keeps the existing check-in and only raises its intensity.
+func (r *Safety) OpenCheckin(earthquakeID, subscriberID string, mmi float64) (*db.SafetyCheckin, bool, error) {
+	checkin, err := r.FindCheckin(earthquakeID, subscriberID)
+	if err != nil {
+		return nil, false, err
+	}
+	if checkin != nil {
+		if mmi > checkin.Mmi() {
+			checkin.SetMmi(mmi)
+			if err := r.App.Save(checkin); err != nil {
+				return nil, false, fmt.Errorf("failed to save check-in: %w", err)
+			}
+		}
+		return checkin, false, nil
+	}
+
+	checkin, err = db.NewProxy[db.SafetyCheckin](r.App)
+	if err != nil {
+		return nil, false, fmt.Errorf("collection not found: %w", err)
+	}
+	checkin.Set("earthquake", earthquakeID)
+	checkin.Set("subscriber", subscriberID)
+	checkin.Set("status", domain.CheckinPending)
+	checkin.SetToken(security.RandomString(32))
+	checkin.SetMmi(mmi)
+
+	if err := r.App.Save(checkin); err != nil {
+		return nil, false, fmt.Errorf("failed to save check-in: %w", err)
+	}
+	return checkin, true, nil
+}
+
+// FindCheckin retrieves the check-in of a subscriber after an earthquake, nil when there is none
+func (r *Safety) FindCheckin(earthquakeID, subscriberID string) (*db.SafetyCheckin, error) {
+	checkin := &db.SafetyCheckin{}
+	err := r.App.RecordQuery("safety_checkin").
+		AndWhere(dbx.HashExp{"earthquake": earthquakeID, "subscriber": subscriberID}).
+		Limit(1).
+		One(checkin)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch check-in: %w", err)
+	}
+	return checkin, nil
+}
+
+// FindCheckinByToken retrieves the check-in of the link or buttons sent to the subscriber
+func (r *Safety) FindCheckinByToken(token string) (*db.SafetyCheckin, error) {
+	if token == "" {
+		return nil, ErrCheckinNotFound
+	}
+
+	checkin := &db.SafetyCheckin{}
+	err := r.App.RecordQuery("safety_checkin").
+		AndWhere(dbx.HashExp{"token": token}).
+		Limit(1).
+		One(checkin)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrCheckinNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch check-in: %w", err)
+	}
+	return checkin, nil
+}
+
+// Respond records the answer of the subscriber, a later answer replaces an earlier one
+func (r *Safety) Respond(checkin *db.SafetyCheckin, status string) error {
+	if !domain.IsCheckinAnswer(status) {
+		return fmt.Errorf("invalid check-in status %q", status)
+	}
+
+	checkin.Set("status", status)
+	checkin.SetRespondedAt(types.NowDateTime())
+
+	if err := r.App.Save(checkin); err != nil {
+		return fmt.Errorf("failed to save check-in: %w", err)
+	}
+	return nil
+}
+
+// DueReminders retrieves the unanswered check-ins opened after since whose last message
+// is older than before and that got fewer than maxReminders reminders
+func (r *Safety) DueReminders(before, since time.Time, maxReminders int) ([]*db.SafetyCheckin, error) {
+	var checkins []*db.SafetyCheckin
+
+	err := r.App.RecordQuery("safety_checkin").
+		AndWhere(dbx.HashExp{"status": domain.CheckinPending}).
+		AndWhere(dbx.NewExp("reminders < {:max}", dbx.Params{"max": maxReminders})).
+		AndWhere(dbx.NewExp("created > {:since}", dbx.Params{"since": since.UTC().Format(types.DefaultDateLayout)})).
+		AndWhere(dbx.NewExp("COALESCE(NULLIF(reminded_at, ''), created) < {:before}", dbx.Params{"before": before.UTC().Format(types.DefaultDateLayout)})).
+		All(&checkins)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch due check-ins: %w", err)
+	}
+
+	return checkins, nil
+}
+
+// MarkReminded counts an automatic reminder sent for the check-in
+func (r *Safety) MarkReminded(checkin *db.SafetyCheckin) error {
+	checkin.SetReminders(checkin.Reminders() + 1)
+
+	return r.MarkNudged(checkin)
+}
+
+// MarkNudged records a reminder requested by the group owner. It spaces the automatic
+// reminders like any other message but does not count toward their maximum.
+func (r *Safety) MarkNudged(checkin *db.SafetyCheckin) error {
+	checkin.SetRemindedAt(types.NowDateTime())
+
+	if err := r.App.Save(checkin); err != nil {
+		return fmt.Errorf("failed to save check-in: %w", err)
+	}
+	return nil
+}
+
+// IsMember reports whether the subscriber belongs to at least one group
+func (r *Safety) IsMember(subscriberID string) (bool, error) {
+	var count int
+	err := r.App.RecordQuery("safety_group_member").
+		Select("count(*)").
+		AndWhere(dbx.HashExp{"subscriber": subscriberID}).
+		Row(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to count memberships: %w", err)
+	}
+	return count > 0, nil
+}
+
+// CreateGroup creates a group with a new invite code
+func (r *Safety) CreateGroup(ownerID, name string) (*db.SafetyGroup, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len([]rune(name)) > 80 {
+		return nil, fmt.Errorf("%w: name must be 1 to 80 characters", ErrInvalidGroup)
+	}
+
+	group, err := db.NewProxy[db.SafetyGroup](r.App)
+	if err != nil {
+		return nil, fmt.Errorf("collection not found: %w", err)
+	}
+	group.Set("owner", ownerID)
+	group.SetName(name)
+	group.SetInviteCode(security.RandomStringWithAlphabet(8, inviteAlphabet))
+
+	if err := r.App.Save(group); err != nil {
+		return nil, fmt.Errorf("failed to save group: %w", err)
+	}
+	return group, nil
+}
+
+// OwnedGroups retrieves the groups of an owner
+func (r *Safety) OwnedGroups(ownerID string) ([]*db.SafetyGroup, error) {
+	var groups []*db.SafetyGroup
+
+	err := r.App.RecordQuery("safety_group").
+		AndWhere(dbx.HashExp{"owner": ownerID}).
+		OrderBy("created ASC").
+		All(&groups)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch groups: %w", err)
+	}
+
+	return groups, nil
+}
+
+// OwnedGroup retrieves a group of the owner, ErrGroupNotFound for a group of someone else
+func (r *Safety) OwnedGroup(ownerID, groupID string) (*db.SafetyGroup, error) {
+	group := &db.SafetyGroup{}
+	err := r.App.RecordQuery("safety_group").
+		AndWhere(dbx.HashExp{"id": groupID, "owner": ownerID}).
+		Limit(1).
+		One(group)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrGroupNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch group: %w", err)
+	}
+	return group, nil
+}
+
+// JoinGroup adds the subscriber to the group of the invite code under name. Joining
+// again only changes the name.
+func (r *Safety) JoinGroup(code string, subscriber *db.UserNotify, name string) (*db.SafetyGroup, error) {
+	group := &db.SafetyGroup{}
+	err := r.App.RecordQuery("safety_group").
+		AndWhere(dbx.HashExp{"invite_code": strings.ToUpper(strings.TrimSpace(code))}).
+		Limit(1).
+		One(group)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrGroupNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch group: %w", err)
+	}
+
+	member := &db.SafetyGroupMember{}
+	err = r.App.RecordQuery("safety_group_member").
+		AndWhere(dbx.HashExp{"safety_group": group.Id, "subscriber": subscriber.Id}).
+		Limit(1).
+		One(member)
+	if errors.Is(err, sql.ErrNoRows) {
+		member, err = db.NewProxy[db.SafetyGroupMember](r.App)
+		if err != nil {
+			return nil, fmt.Errorf("collection not found: %w", err)
+		}
+		member.Set("safety_group", group.Id)
+		member.Set("subscriber", subscriber.Id)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to fetch member: %w", err)
+	}
+
+	name = strings.TrimSpace(name)
+	if len([]rune(name)) > 80 {
+		name = string([]rune(name)[:80])
+	}
+	member.SetName(name)
+
+	if err := r.App.Save(member); err != nil {
+		return nil, fmt.Errorf("failed to save member: %w", err)
+	}
+	return group, nil
+}
+
+// Members retrieves the members of a group, oldest first
+func (r *Safety) Members(group *db.SafetyGroup) ([]*db.SafetyGroupMember, error) {
+	var members []*db.SafetyGroupMember
+
+	err := r.App.RecordQuery("safety_group_member").
+		AndWhere(dbx.HashExp{"safety_group": group.Id}).
+		OrderBy("created ASC").
+		All(&members)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch members: %w", err)
+	}
+
+	return members, nil
+}
+
+// RemoveMember removes a member from the group. It reports false when the group has no such member.
+func (r *Safety) RemoveMember(group *db.SafetyGroup, memberID string) (bool, error) {
+	member := &db.SafetyGroupMember{}
+	err := r.App.RecordQuery("safety_group_member").
+		AndWhere(dbx.HashExp{"id": memberID, "safety_group": group.Id}).
+		Limit(1).
+		One(member)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to fetch member: %w", err)
+	}
+
+	if err := r.App.Delete(member); err != nil {
+		return false, fmt.Errorf("failed to delete member: %w", err)
+	}
+	return true, nil
+}
+
+// GroupCheckins retrieves the check-ins of the group members after an earthquake. Without
+// earthquake the latest earthquake that opened a check-in for a member is used.
+func (r *Safety) GroupCheckins(group *db.SafetyGroup, earthquakeID string) (string, []*db.SafetyCheckin, error) {
+	if earthquakeID == "" {
+		err := r.App.DB().
+			Select("safety_checkin.earthquake").
+			From("safety_checkin").
+			InnerJoin("safety_group_member", dbx.NewExp("safety_group_member.subscriber = safety_checkin.subscriber")).
+			Where(dbx.HashExp{"safety_group_member.safety_group": group.Id}).
+			OrderBy("safety_checkin.created DESC").
+			Limit(1).
+			Row(&earthquakeID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil, nil
+		}
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to fetch latest check-in: %w", err)
+		}
+	}
+
+	var checkins []*db.SafetyCheckin
+	err := r.App.RecordQuery("safety_checkin").
+		InnerJoin("safety_group_member", dbx.NewExp("safety_group_member.subscriber = safety_checkin.subscriber")).
+		AndWhere(dbx.HashExp{"safety_group_member.safety_group": group.Id, "safety_checkin.earthquake": earthquakeID}).
+		All(&checkins)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to fetch check-ins: %w", err)
+	}
+
+	return earthquakeID, checkins, nil
+}
+
+// RollUp summarizes who of the group responded after an earthquake, see GroupCheckins
+func (r *Safety) RollUp(group *db.SafetyGroup, earthquakeID string) (domain.CheckinRollUp, error) {
+	earthquakeID, checkins, err := r.GroupCheckins(group, earthquakeID)
+	if err != nil {
+		return domain.CheckinRollUp{}, err
+	}
+
+	members, err := r.Members(group)
+	if err != nil {
+		return domain.CheckinRollUp{}, err
+	}
+
+	bySubscriber := make(map[string]*db.SafetyCheckin, len(checkins))
+	for _, checkin := range checkins {
+		bySubscriber[checkin.GetString("subscriber")] = checkin
+	}
+
+	rollUp := domain.CheckinRollUp{Earthquake: earthquakeID, Members: make([]domain.CheckinMember, 0, len(members))}
+	for _, member := range members {
+		item := domain.CheckinMember{Member: member.Id, Name: member.Name()}
+
+		checkin, ok := bySubscriber[member.GetString("subscriber")]
+		if ok {
+			item.Status = checkin.GetString("status")
+			item.MMI = checkin.Mmi()
+			if !checkin.RespondedAt().IsZero() {
+				respondedAt := checkin.RespondedAt().Time()
+				item.RespondedAt = &respondedAt
+			}
+		}
+
+		switch item.Status {
+		case "":
+			rollUp.NotAffected++
+		case domain.CheckinPending:
+			rollUp.Unresponded++
+		case domain.CheckinSafe:
+			rollUp.Responded++
+			rollUp.Safe++
+		case domain.CheckinNeedHelp:
+			rollUp.Responded++
+			rollUp.NeedHelp++
+		}
+		rollUp.Members = append(rollUp.Members, item)
+	}
+
+	return rollUp, nil
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// State repository for small pieces of worker state that must survive restarts
+type State struct {
+	App core.App
+}
+
+// NewStateRepository creates a new State repository
+func NewStateRepository(app core.App) *State {
+	return &State{
+		App: app,
+	}
+}
+
+// Load decodes the value stored under key into out. It reports false when the key does not exist yet.
+func (r *State) Load(key string, out interface{}) (bool, error) {
+	record, err := r.App.FindFirstRecordByData("worker_state", "key", key)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to load state %s: %w", key, err)
+	}
+
+	if err := record.UnmarshalJSONField("value", out); err != nil {
+		return false, fmt.Errorf("failed to decode state %s: %w", key, err)
+	}
+
+	return true, nil
+}
+
+// Save stores value under key, creating the record on first use
+func (r *State) Save(key string, value interface{}) error {
+	record, err := r.App.FindFirstRecordByData("worker_state", "key", key)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("failed to load state %s: %w", key, err)
+		}
+
+		collection, err := r.App.FindCachedCollectionByNameOrId("worker_state")
+		if err != nil {
+			return fmt.Errorf("collection not found: %w", err)
+		}
+		record = core.NewRecord(collection)
+		record.Set("key", key)
+	}
+
+	record.Set("value", value)
+
+	if err := r.App.Save(record); err != nil {
+		return fmt.Errorf("failed to save state %s: %w", key, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,302 @@
+package repository
+
+import (
+	"bmkg/src/db"
+	"bmkg/src/domain"
+	"bmkg/src/messages"
+	"bmkg/src/utils"
+	"bmkg/src/utils/ngitung"
+	"errors"
+	"fmt"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// MaxLocationsPerSubscriber is the number of places a subscriber may save
+const MaxLocationsPerSubscriber = 5
+
+var (
+	// ErrInvalidPreferences is returned when preferences fail validation
+	ErrInvalidPreferences = errors.New("invalid preferences")
+	// ErrLocationLimit is returned when a subscriber already saved MaxLocationsPerSubscriber places
+	ErrLocationLimit = fmt.Errorf("at most %d locations per subscriber", MaxLocationsPerSubscriber)
+	// ErrInvalidLocation is returned for a location without label or with invalid coordinates
+	ErrInvalidLocation = errors.New("invalid location")
+)
+
+// Subscriber repository for the user_notify subscriptions and their alert preferences
+type Subscriber struct {
+	App core.App
+}
+
+// NewSubscriberRepository creates a new Subscriber repository
+func NewSubscriberRepository(app core.App) *Subscriber {
+	return &Subscriber{
+		App: app,
+	}
+}
+
+// FindByIdentifier retrieves the subscriptions of a chat id, phone number, email or user id
+func (r *Subscriber) FindByIdentifier(identifier string) ([]*db.UserNotify, error) {
+	var subscribers []*db.UserNotify
+
+	err := r.App.RecordQuery("user_notify").
+		AndWhere(dbx.HashExp{"identifier": identifier}).
+		All(&subscribers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch subscribers: %w", err)
+	}
+
+	return subscribers, nil
+}
+
+// Find retrieves a subscription by record id
+func (r *Subscriber) Find(id string) (*db.UserNotify, error) {
+	record, err := r.App.FindRecordById("user_notify", id)
+	if err != nil {
+		return nil, fmt.Errorf("subscriber %s not found: %w", id, err)
+	}
+
+	subscriber := &db.UserNotify{}
+	subscriber.SetProxyRecord(record)
+	return subscriber, nil
+}
+
+// Ensure returns the subscription of identifier on a channel, creating it on first use
+func (r *Subscriber) Ensure(identifier string, channel db.TypeSelectType) (*db.UserNotify, error) {
+	subscribers, err := r.FindByIdentifier(identifier)
+	if err != nil {
+		return nil, err
+	}
+	for _, subscriber := range subscribers {
+		if subscriber.Type() == channel {
+			return subscriber, nil
+		}
+	}
+
+	subscriber, err := db.NewProxy[db.UserNotify](r.App)
+	if err != nil {
+		return nil, fmt.Errorf("collection not found: %w", err)
+	}
+	subscriber.SetIdentifier(identifier)
+	subscriber.SetType(channel)
+
+	if err := r.App.Save(subscriber); err != nil {
+		return nil, fmt.Errorf("failed to save subscriber: %w", err)
+	}
+	return subscriber, nil
+}
+
+// Delete unsubscribes a subscriber. Its saved locations are deleted with it.
+func (r *Subscriber) Delete(subscriber *db.UserNotify) error {
+	if err := r.App.Delete(subscriber); err != nil {
+		return fmt.Errorf("failed to delete subscriber: %w", err)
+	}
+	return nil
+}
+
+// Locations retrieves the saved places of a subscriber, oldest first
+func (r *Subscriber) Locations(subscriber *db.UserNotify) ([]*db.SubscriberLocation, error) {
+	var locations []*db.SubscriberLocation
+
+	err := r.App.RecordQuery("subscriber_location").
+		AndWhere(dbx.HashExp{"subscriber": subscriber.Id}).
+		OrderBy("created ASC").
+		All(&locations)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch locations: %w", err)
+	}
+
+	return locations, nil
+}
+
+// SaveLocation stores a labeled place of a subscriber. A place with the same label is
+// moved, a new one is refused once the subscriber reached MaxLocationsPerSubscriber.
+func (r *Subscriber) SaveLocation(subscriber *db.UserNotify, label string, latitude, longitude float64) (*db.SubscriberLocation, error) {
+	label = strings.TrimSpace(label)
+	if label == "" || len([]rune(label)) > 40 {
+		return nil, fmt.Errorf("%w: label must be 1 to 40 characters", ErrInvalidLocation)
+	}
+	if latitude < -90 || latitude > 90 || longitude < -180 || longitude > 180 {
+		return nil, fmt.Errorf("%w: coordinates out of range", ErrInvalidLocation)
+	}
+
+	locations, err := r.Locations(subscriber)
+	if err != nil {
+		return nil, err
+	}
+
+	var location *db.SubscriberLocation
+	for _, existing := range locations {
+		if strings.EqualFold(existing.Label(), label) {
+			location = existing
+			break
+		}
+	}
+
+	if location == nil {
+		if len(locations) >= MaxLocationsPerSubscriber {
+			return nil, ErrLocationLimit
+		}
+
+		location, err = db.NewProxy[db.SubscriberLocation](r.App)
+		if err != nil {
+			return nil, fmt.Errorf("collection not found: %w", err)
+		}
+		location.Set("subscriber", subscriber.Id)
+	}
+
+	location.SetLabel(label)
+	location.SetLintang(strconv.FormatFloat(latitude, 'f', -1, 64))
+	location.SetBujur(strconv.FormatFloat(longitude, 'f', -1, 64))
+
+	if err := r.App.Save(location); err != nil {
+		return nil, fmt.Errorf("failed to save location: %w", err)
+	}
+	return location, nil
+}
+
+// RemoveLocation deletes a saved place by label. It reports false when no place has the label.
+func (r *Subscriber) RemoveLocation(subscriber *db.UserNotify, label string) (bool, error) {
+	locations, err := r.Locations(subscriber)
+	if err != nil {
+		return false, err
+	}
+
+	for _, location := range locations {
+		if strings.EqualFold(location.Label(), strings.TrimSpace(label)) {
+			if err := r.App.Delete(location); err != nil {
+				return false, fmt.Errorf("failed to delete location: %w", err)
+			}
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// NearestLocation returns the saved place of a subscriber closest to lat, lon, or nil
+// when the subscriber has no saved place
+func (r *Subscriber) NearestLocation(subscriber *db.UserNotify, lat, lon float64) (*db.SubscriberLocation, error) {
+	locations, err := r.Locations(subscriber)
+	if err != nil {
+		return nil, err
+	}
+
+	var nearest *db.SubscriberLocation
+	shortest := math.Inf(1)
+	for _, location := range locations {
+		locationLat, errLat := strconv.ParseFloat(location.Lintang(), 64)
+		locationLon, errLon := strconv.ParseFloat(location.Bujur(), 64)
+		if errLat != nil || errLon != nil {
+			continue
+		}
+
+		distance := ngitung.Distance(ngitung.Location{Lat: lat, Lon: lon}, ngitung.Location{Lat: locationLat, Lon: locationLon})
+		if distance < shortest {
+			nearest, shortest = location, distance
+		}
+	}
+	return nearest, nil
+}
+
+// Preferences returns the current preferences of a subscriber
+func (r *Subscriber) Preferences(subscriber *db.UserNotify) domain.NotifyPreferences {
+	minMagnitude, minMMI, radius := subscriber.MinMagnitude(), subscriber.MinMmi(), subscriber.RadiusKm()
+	quietStart, quietEnd := subscriber.QuietStart(), subscriber.QuietEnd()
+
+	timezone := subscriber.Timezone()
+	if timezone == "" {
+		timezone = utils.DefaultTimezone
+	}
+
+	language := subscriber.GetString("language")
+	if language == "" {
+		language = messages.DefaultLanguage
+	}
+
+	return domain.NotifyPreferences{
+		MinMagnitude: &minMagnitude,
+		MinMMI:       &minMMI,
+		QuietStart:   &quietStart,
+		QuietEnd:     &quietEnd,
+		Timezone:     &timezone,
+		Language:     &language,
+		RadiusKm:     &radius,
+	}
+}
+
+// SavePreferences validates and stores the changed preferences of a subscriber
+func (r *Subscriber) SavePreferences(subscriber *db.UserNotify, preferences domain.NotifyPreferences) error {
+	if err := applyPreferences(subscriber, preferences); err != nil {
+		return err
+	}
+
+	if err := r.App.Save(subscriber); err != nil {
+		return fmt.Errorf("failed to save preferences: %w", err)
+	}
+	return nil
+}
+
+func applyPreferences(subscriber *db.UserNotify, preferences domain.NotifyPreferences) error {
+	if value := preferences.MinMagnitude; value != nil {
+		if *value < 0 || *value > 10 {
+			return fmt.Errorf("%w: min_magnitude must be between 0 and 10", ErrInvalidPreferences)
+		}
+		subscriber.SetMinMagnitude(*value)
+	}
+
+	if value := preferences.MinMMI; value != nil {
+		if *value < 0 || *value > 12 {
+			return fmt.Errorf("%w: min_mmi must be between 0 and 12", ErrInvalidPreferences)
+		}
+		subscriber.SetMinMmi(*value)
+	}
+
+	if preferences.QuietStart != nil || preferences.QuietEnd != nil {
+		start, end := subscriber.QuietStart(), subscriber.QuietEnd()
+		if preferences.QuietStart != nil {
+			start = *preferences.QuietStart
+		}
+		if preferences.QuietEnd != nil {
+			end = *preferences.QuietEnd
+		}
+
+		if (start == "") != (end == "") {
+			return fmt.Errorf("%w: quiet_start and quiet_end must be set together", ErrInvalidPreferences)
+		}
+		for _, clock := range []string{start, end} {
+			if _, err := utils.ParseClock(clock); clock != "" && err != nil {
+				return fmt.Errorf("%w: %v", ErrInvalidPreferences, err)
+			}
+		}
+
+		subscriber.SetQuietStart(start)
+		subscriber.SetQuietEnd(end)
+	}
+
+	if value := preferences.Timezone; value != nil {
+		if _, err := utils.LoadTimezone(*value); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidPreferences, err)
+		}
+		subscriber.SetTimezone(*value)
+	}
+
+	if value := preferences.RadiusKm; value != nil {
+		if *value < 0 || *value > 20000 {
+			return fmt.Errorf("%w: radius_km must be between 0 and 20000", ErrInvalidPreferences)
+		}
+		subscriber.SetRadiusKm(*value)
+	}
+
+	if value := preferences.Language; value != nil {
+		if !messages.IsLanguage(*value) {
+			return fmt.Errorf("%w: language must be one of %s", ErrInvalidPreferences, strings.Join(messages.Languages, ", "))
+		}
+		subscriber.Set("language", *value)
+	}
+
+	return nil
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"bmkg/src/db"
+	"fmt"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// Webhook repository for the webhook subscriptions of partner systems
+type Webhook struct {
+	App core.App
+}
+
+// NewWebhookRepository creates a new Webhook repository
+func NewWebhookRepository(app core.App) *Webhook {
+	return &Webhook{
+		App: app,
+	}
+}
+
+// Active retrieves every subscription that still receives events
+func (r *Webhook) Active() ([]*db.WebhookSubscription, error) {
+	var subscriptions []*db.WebhookSubscription
+
+	err := r.App.RecordQuery("webhook_subscription").
+		AndWhere(dbx.HashExp{"active": true}).
+		All(&subscriptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch webhook subscriptions: %w", err)
+	}
+
+	return subscriptions, nil
+}
+
+// Find retrieves a subscription by id
+func (r *Webhook) Find(id string) (*db.WebhookSubscription, error) {
+	record, err := r.App.FindRecordById("webhook_subscription", id)
+	if err != nil {
+		return nil, fmt.Errorf("webhook subscription %s not found: %w", id, err)
+	}
+
+	subscription := &db.WebhookSubscription{}
+	subscription.SetProxyRecord(record)
+	return subscription, nil
+}
+
+// Target returns the endpoint and signing secret of a subscription
+func (r *Webhook) Target(id string) (string, string, bool, error) {
+	subscription, err := r.Find(id)
+	if err != nil {
+		return "", "", false, err
+	}
+	return subscription.Url(), subscription.Secret(), subscription.Active(), nil
+}
+
+// RecordSuccess resets the failure counter of a subscription
+func (r *Webhook) RecordSuccess(id string) error {
+	subscription, err := r.Find(id)
+	if err != nil {
+		return err
+	}
+
+	subscription.SetConsecutiveFailures(0)
+	subscription.SetLastSuccessAt(types.NowDateTime())
+	return r.save(subscription)
+}
+
+// RecordFailure counts a failed delivery attempt and returns the number of
+// consecutive failures
+func (r *Webhook) RecordFailure(id string) (int, error) {
+	subscription, err := r.Find(id)
+	if err != nil {
+		return 0, err
+	}
+
+	failures := subscription.ConsecutiveFailures() + 1
+	subscription.SetConsecutiveFailures(failures)
+	subscription.SetLastFailureAt(types.NowDateTime())
+	return failures, r.save(subscription)
+}
+
+// Disable stops sending events to a subscription
+func (r *Webhook) Disable(id, reason string) error {
+	subscription, err := r.Find(id)
+	if err != nil {
+		return err
+	}
+
+	subscription.SetActive(false)
+	subscription.SetDisabledReason(reason)
+	return r.save(subscription)
+}
+
+// Enable reactivates a subscription and clears its failure history
+func (r *Webhook) Enable(id string) error {
+	subscription, err := r.Find(id)
+	if err != nil {
+		return err
+	}
+
+	subscription.SetActive(true)
+	subscription.SetDisabledReason("")
+	subscription.SetConsecutiveFailures(0)
+	return r.save(subscription)
+}
+
+func (r *Webhook) save(subscription *db.WebhookSubscription) error {
+	if err := r.App.Save(subscription); err != nil {
+		return fmt.Errorf("failed to save webhook subscription %s: %w", subscription.Id, err)
+	}
+	return nil
+}
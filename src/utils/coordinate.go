@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseCoordinate converts a BMKG coordinate string into a signed decimal degree.
+// The hemisphere suffix decides the sign, for example:
+// "6.77 LS" -> -6.77
+// "2.10 LU" -> 2.10
+// "105.513 BT" -> 105.513
+// "120.5 BB" -> -120.5
+func ParseCoordinate(input string) (float64, error) {
+	value, err := strconv.ParseFloat(ExtractNumber(input), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid coordinate %q: %w", input, err)
+	}
+
+	upper := strings.ToUpper(input)
+	if (strings.HasSuffix(upper, "LS") || strings.HasSuffix(upper, "BB")) && value > 0 {
+		value = -value
+	}
+
+	return value, nil
+}
+
+// ParseCoordinates parses the BMKG "lat,lon" pair, e.g. "-6.77,105.51".
+func ParseCoordinates(input string) (float64, float64, error) {
+	parts := strings.Split(input, ",")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid coordinates %q", input)
+	}
+
+	lat, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid latitude %q: %w", parts[0], err)
+	}
+
+	lon, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid longitude %q: %w", parts[1], err)
+	}
+
+	return lat, lon, nil
+}
+
+// FormatLintang formats a signed latitude the way BMKG does, e.g. -6.77 -> "6.77 LS"
+func FormatLintang(lat float64) string {
+	if lat < 0 {
+		return strconv.FormatFloat(-lat, 'f', 2, 64) + " LS"
+	}
+	return strconv.FormatFloat(lat, 'f', 2, 64) + " LU"
+}
+
+// FormatBujur formats a signed longitude the way BMKG does, e.g. 105.51 -> "105.51 BT"
+func FormatBujur(lon float64) string {
+	if lon < 0 {
+		return strconv.FormatFloat(-lon, 'f', 2, 64) + " BB"
+	}
+	return strconv.FormatFloat(lon, 'f', 2, 64) + " BT"
+}
@@ -0,0 +1,144 @@
+package utils
+
+import (
+	"math"
+	"strings"
+)
+
+const geohashBase32 = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// EncodeGeohash encodes a coordinate into a geohash of the given precision (number of characters).
+// Precision 4 gives cells of about 39 x 20 km, precision 5 about 4.9 x 4.9 km.
+func EncodeGeohash(lat, lon float64, precision int) string {
+	minLat, maxLat := -90.0, 90.0
+	minLon, maxLon := -180.0, 180.0
+
+	var sb strings.Builder
+	sb.Grow(precision)
+
+	bit, ch := 0, 0
+	even := true
+	for sb.Len() < precision {
+		if even {
+			mid := (minLon + maxLon) / 2
+			if lon >= mid {
+				ch |= 1 << (4 - bit)
+				minLon = mid
+			} else {
+				maxLon = mid
+			}
+		} else {
+			mid := (minLat + maxLat) / 2
+			if lat >= mid {
+				ch |= 1 << (4 - bit)
+				minLat = mid
+			} else {
+				maxLat = mid
+			}
+		}
+		even = !even
+
+		if bit < 4 {
+			bit++
+		} else {
+			sb.WriteByte(geohashBase32[ch])
+			bit, ch = 0, 0
+		}
+	}
+
+	return sb.String()
+}
+
+// GeohashCellSize returns the height and width in degrees of a geohash cell of the given precision
+func GeohashCellSize(precision int) (float64, float64) {
+	bits := precision * 5
+	lonBits := (bits + 1) / 2
+	latBits := bits / 2
+	return 180 / math.Pow(2, float64(latBits)), 360 / math.Pow(2, float64(lonBits))
+}
+
+// GeohashesInRadius returns every geohash cell of the given precision that overlaps the
+// bounding box of a circle of radiusKm around lat, lon. The box wraps around the
+// antimeridian and covers every longitude when the circle contains a pole.
+func GeohashesInRadius(lat, lon, radiusKm float64, precision int) []string {
+	// same sphere as ngitung.Distance, so no recipient inside the radius falls outside the box
+	const earthRadius = 6371.0
+
+	angular := radiusKm / earthRadius
+	dLat := angular * 180 / math.Pi
+
+	minLat, maxLat := lat-dLat, lat+dLat
+	minLon, maxLon := -180.0, 180.0
+	if minLat > -90 && maxLat < 90 {
+		// the circle is widest north or south of its centre, not at the centre latitude
+		if sinLon := math.Sin(angular) / math.Cos(lat*math.Pi/180); sinLon < 1 {
+			dLon := math.Asin(sinLon) * 180 / math.Pi
+			minLon, maxLon = lon-dLon, lon+dLon
+		}
+	}
+	minLat, maxLat = math.Max(-90, minLat), math.Min(90, maxLat)
+
+	cellLat, cellLon := GeohashCellSize(precision)
+
+	// Align to the cell grid so every overlapped cell is visited exactly once
+	startLat := math.Floor((minLat+90)/cellLat)*cellLat - 90
+	startLon := math.Floor((minLon+180)/cellLon)*cellLon - 180
+
+	var cells []string
+	seen := make(map[string]struct{})
+	for cLat := startLat; cLat <= maxLat && cLat < 90; cLat += cellLat {
+		for cLon := startLon; cLon <= maxLon; cLon += cellLon {
+			// Encode the centre of the cell to avoid rounding onto a neighbour, longitudes
+			// past the antimeridian continue on the other side
+			hash := EncodeGeohash(cLat+cellLat/2, wrapLongitude(cLon+cellLon/2), precision)
+			if _, ok := seen[hash]; ok {
+				continue
+			}
+			seen[hash] = struct{}{}
+			cells = append(cells, hash)
+		}
+	}
+
+	return cells
+}
+
+// wrapLongitude brings a longitude back into [-180, 180)
+func wrapLongitude(lon float64) float64 {
+	return math.Mod(math.Mod(lon+180, 360)+360, 360) - 180
+}
+
+// GeohashBounds returns the south-west and north-east corners of a geohash cell
+func GeohashBounds(hash string) (minLat, minLon, maxLat, maxLon float64) {
+	minLat, maxLat = -90.0, 90.0
+	minLon, maxLon = -180.0, 180.0
+
+	even := true
+	for i := 0; i < len(hash); i++ {
+		ch := strings.IndexByte(geohashBase32, hash[i])
+		if ch < 0 {
+			break
+		}
+
+		for bit := 4; bit >= 0; bit-- {
+			set := ch&(1<<bit) != 0
+			if even {
+				mid := (minLon + maxLon) / 2
+				if set {
+					minLon = mid
+				} else {
+					maxLon = mid
+				}
+			} else {
+				mid := (minLat + maxLat) / 2
+				if set {
+					minLat = mid
+				} else {
+					maxLat = mid
+				}
+			}
+			even = !even
+		}
+	}
+
+	return minLat, minLon, maxLat, maxLon
+}
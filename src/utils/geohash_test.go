@@ -0,0 +1,120 @@
+package utils
+
+import (
+	"math"
+	"testing"
+)
+
+// destination returns the point at distanceKm from lat, lon in the direction of bearing
+// (degrees), on the same sphere as ngitung.Distance
+func destination(lat, lon, distanceKm, bearing float64) (float64, float64) {
+	const earthRadius = 6371.0
+	const rad = math.Pi / 180
+
+	angular := distanceKm / earthRadius
+	lat1, lon1, theta := lat*rad, lon*rad, bearing*rad
+
+	lat2 := math.Asin(math.Sin(lat1)*math.Cos(angular) + math.Cos(lat1)*math.Sin(angular)*math.Cos(theta))
+	lon2 := lon1 + math.Atan2(math.Sin(theta)*math.Sin(angular)*math.Cos(lat1), math.Cos(angular)-math.Sin(lat1)*math.Sin(lat2))
+
+	return lat2 / rad, wrapLongitude(lon2 / rad)
+}
+
+func TestGeohashesInRadiusCoversCircle(t *testing.T) {
+	const precision = 4
+	cellLat, cellLon := GeohashCellSize(precision)
+
+	tests := []struct {
+		name     string
+		lat, lon float64
+		radius   float64
+	}{
+		{"jakarta", -6.2, 106.8, 300},
+		{"small radius", -6.2, 106.8, 1},
+		// centre on a cell corner and on a cell edge
+		{"cell corner", 0, 0, 50},
+		{"cell edge lat", 2 * cellLat, 106.8, 50},
+		{"cell edge lon", -6.2, 100 * cellLon, 50},
+		{"just inside cell", cellLat - 1e-9, cellLon - 1e-9, 20},
+		// the circle crosses the antimeridian
+		{"antimeridian east", -17.8, 179.9, 300},
+		{"antimeridian west", -17.8, -179.9, 300},
+		{"antimeridian exact", 51.0, 180, 100},
+		// the circle contains or nearly touches a pole
+		{"north pole", 89.9, 45, 200},
+		{"south pole", -89.5, -120, 300},
+		{"high latitude", 80, 10, 500},
+		{"large radius", -6.2, 106.8, 3000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cells := make(map[string]bool)
+			for _, hash := range GeohashesInRadius(tt.lat, tt.lon, tt.radius, precision) {
+				if cells[hash] {
+					t.Errorf("cell %s returned twice", hash)
+				}
+				cells[hash] = true
+			}
+
+			// points on rings inside and on the edge of the circle must fall in a returned cell
+			for _, fraction := range []float64{0, 0.25, 0.5, 0.75, 0.99, 1} {
+				for bearing := 0.0; bearing < 360; bearing += 5 {
+					lat, lon := destination(tt.lat, tt.lon, tt.radius*fraction, bearing)
+					if hash := EncodeGeohash(lat, lon, precision); !cells[hash] {
+						t.Fatalf("point %.4f, %.4f at %.0f%% of the radius (bearing %.0f) is in missing cell %s",
+							lat, lon, fraction*100, bearing, hash)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestGeohashesInRadiusPoleCoversAllLongitudes(t *testing.T) {
+	cells := make(map[string]bool)
+	for _, hash := range GeohashesInRadius(89.99, 0, 50, 3) {
+		cells[hash] = true
+	}
+
+	for lon := -179.5; lon < 180; lon += 1 {
+		if hash := EncodeGeohash(89.999, lon, 3); !cells[hash] {
+			t.Fatalf("cell %s at longitude %.1f near the pole is missing", hash, lon)
+		}
+	}
+}
+
+func TestEncodeGeohash(t *testing.T) {
+	tests := []struct {
+		lat, lon  float64
+		precision int
+		want      string
+	}{
+		// contoh dari geohash.org
+		{57.64911, 10.40744, 11, "u4pruydqqvj"},
+		{-6.2, 106.8166, 5, "qqguw"},
+		{0, 0, 4, "s000"},
+		{-90, -180, 4, "0000"},
+	}
+
+	for _, tt := range tests {
+		if got := EncodeGeohash(tt.lat, tt.lon, tt.precision); got != tt.want {
+			t.Errorf("EncodeGeohash(%v, %v, %d) = %s, want %s", tt.lat, tt.lon, tt.precision, got, tt.want)
+		}
+	}
+}
+
+func TestGeohashBoundsContainsPoint(t *testing.T) {
+	for _, point := range [][2]float64{{-6.2, 106.8166}, {57.64911, 10.40744}, {-89.9, 179.9}, {0, 0}} {
+		hash := EncodeGeohash(point[0], point[1], 5)
+		minLat, minLon, maxLat, maxLon := GeohashBounds(hash)
+		if point[0] < minLat || point[0] > maxLat || point[1] < minLon || point[1] > maxLon {
+			t.Errorf("bounds of %s (%v, %v, %v, %v) do not contain %v", hash, minLat, minLon, maxLat, maxLon, point)
+		}
+
+		cellLat, cellLon := GeohashCellSize(5)
+		if math.Abs(maxLat-minLat-cellLat) > 1e-9 || math.Abs(maxLon-minLon-cellLon) > 1e-9 {
+			t.Errorf("bounds of %s are %v x %v, want %v x %v", hash, maxLat-minLat, maxLon-minLon, cellLat, cellLon)
+		}
+	}
+}
@@ -18,6 +18,34 @@ var (
 	ErrRequestFailed   = errors.New("request failed")
 )
 
+// HTTPError is returned for non-2xx responses. It wraps ErrInvalidResponse and
+// keeps the status code and body so callers can classify the failure.
+type HTTPError struct {
+	StatusCode int
+	Body       []byte
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("%v: status code %d", ErrInvalidResponse, e.StatusCode)
+}
+
+func (e *HTTPError) Unwrap() error {
+	return ErrInvalidResponse
+}
+
+// Validators holds the cache validators of a previous response
+type Validators struct {
+	ETag         string `json:"etag"`
+	LastModified string `json:"last_modified"`
+}
+
+// ConditionalResponse is the result of a conditional GET request
+type ConditionalResponse struct {
+	Body        []byte
+	Validators  Validators
+	NotModified bool
+}
+
 // HTTPClientConfig holds the configuration for the HTTP client
 type HTTPClientConfig struct {
 	MaxConns               int
@@ -80,10 +108,11 @@ func (hc *HTTPClient) doRequest(ctx context.Context, req *fasthttp.Request, resp
 
 	statusCode := resp.StatusCode()
 	if statusCode < 200 || statusCode >= 300 {
-		return nil, fmt.Errorf("%w: status code %d", ErrInvalidResponse, statusCode)
+		return nil, &HTTPError{StatusCode: statusCode, Body: append([]byte(nil), resp.Body()...)}
 	}
 
-	return resp.Body(), nil
+	// Copy the body, resp goes back to the pool once the caller releases it
+	return append([]byte(nil), resp.Body()...), nil
 }
 
 // executeRequest is a helper function to reduce code duplication
@@ -119,6 +148,62 @@ func (hc *HTTPClient) GetWithContext(ctx context.Context, url string, headers ma
 	return hc.executeRequest(ctx, fasthttp.MethodGet, url, headers, nil)
 }
 
+// GetConditional sends a GET request with If-None-Match / If-Modified-Since headers
+func (hc *HTTPClient) GetConditional(url string, validators Validators) (ConditionalResponse, error) {
+	return hc.GetConditionalWithContext(context.Background(), url, validators)
+}
+
+// GetConditionalWithContext sends a conditional GET request. When the server answers
+// 304 Not Modified the result has NotModified set and keeps the given validators.
+func (hc *HTTPClient) GetConditionalWithContext(ctx context.Context, url string, validators Validators) (ConditionalResponse, error) {
+	req := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(req)
+
+	req.Header.SetMethod(fasthttp.MethodGet)
+	req.SetRequestURI(url)
+
+	if validators.ETag != "" {
+		req.Header.Set("If-None-Match", validators.ETag)
+	}
+	if validators.LastModified != "" {
+		req.Header.Set("If-Modified-Since", validators.LastModified)
+	}
+
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseResponse(resp)
+
+	if err := hc.client.DoTimeout(req, resp, 30*time.Second); err != nil {
+		return ConditionalResponse{}, fmt.Errorf("%w: %v", ErrRequestFailed, err)
+	}
+
+	statusCode := resp.StatusCode()
+	if statusCode == fasthttp.StatusNotModified {
+		return ConditionalResponse{Validators: validators, NotModified: true}, nil
+	}
+	if statusCode < 200 || statusCode >= 300 {
+		return ConditionalResponse{}, &HTTPError{StatusCode: statusCode, Body: append([]byte(nil), resp.Body()...)}
+	}
+
+	return ConditionalResponse{
+		Body: append([]byte(nil), resp.Body()...),
+		Validators: Validators{
+			ETag:         peekHeader(resp, "ETag", "Etag", "etag"),
+			LastModified: peekHeader(resp, "Last-Modified", "last-modified"),
+		},
+	}, nil
+}
+
+// peekHeader returns the first non-empty response header among names.
+// Header name normalizing is disabled on the client, so the spelling matters.
+func peekHeader(resp *fasthttp.Response, names ...string) string {
+	for _, name := range names {
+		if value := resp.Header.Peek(name); len(value) > 0 {
+			return string(value)
+		}
+	}
+	return ""
+}
+
 // Post sends a POST request
 func (hc *HTTPClient) Post(url string, body []byte) ([]byte, error) {
 	return hc.PostWithContext(context.Background(), url, nil, body)
@@ -0,0 +1,153 @@
+package ngitung
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// gravity percepatan gravitasi dalam cm/s^2, untuk konversi g ke gal
+const gravity = 980.665
+
+// GMPE (ground-motion prediction equation) memperkirakan PGA di suatu lokasi.
+// distance adalah jarak episentral dan depth kedalaman hiposenter, keduanya dalam km;
+// tiap model memilih sendiri metrik jarak yang dipakainya. Hasil dalam cm/s^2 (gal).
+type GMPE interface {
+	Name() string
+	PGA(magnitude, distance, depth float64) float64
+}
+
+// FukushimaTanaka model Fukushima & Tanaka (1990), dipakai sejak awal oleh aplikasi ini.
+// Jarak yang dipakai adalah jarak hiposenter.
+type FukushimaTanaka struct{}
+
+func (FukushimaTanaka) Name() string { return "fukushima-tanaka" }
+
+func (FukushimaTanaka) PGA(magnitude, distance, depth float64) float64 {
+	// Rumus: log10(A) = 0.41*M - log10(R + 0.032 * 10^(0.41*M)) - 0.0034*R + 1.30
+	r := hypocentralDistance(distance, depth)
+	term1 := 0.41 * magnitude
+	term2 := math.Log10(r + 0.032*math.Pow(10, 0.41*magnitude))
+	term3 := 0.0034 * r
+	logA := term1 - term2 - term3 + 1.30
+	return math.Pow(10, logA) // A dalam cm/s^2
+}
+
+// Youngs97 model Youngs et al. (1997) untuk gempa interface zona subduksi di batuan,
+// cocok untuk megathrust Sunda dan Banda. Jarak rupture didekati dengan jarak hiposenter.
+type Youngs97 struct{}
+
+func (Youngs97) Name() string { return "youngs97" }
+
+func (Youngs97) PGA(magnitude, distance, depth float64) float64 {
+	// ln(y) = 0.2418 + 1.414*M + C3*ln(R + 1.7818*e^(0.554*M)) + 0.00607*H + 0.3846*Zt
+	// Untuk PGA: C1 = C2 = 0, C3 = -2.552; interface Zt = 0. y dalam g.
+	r := hypocentralDistance(distance, depth)
+	lnY := 0.2418 + 1.414*magnitude -
+		2.552*math.Log(r+1.7818*math.Exp(0.554*magnitude)) +
+		0.00607*depth
+	return math.Exp(lnY) * gravity
+}
+
+// bjfMaxDepth batas kedalaman gempa kerak dangkal yang dicakup data BJF97 (km)
+const bjfMaxDepth = 30
+
+// BJF97 model Boore, Joyner & Fumal (1997) untuk gempa kerak dangkal (sesar darat seperti
+// Lembang, Cimandiri, Palu-Koro). Koefisien mekanisme tidak diketahui, Vs30 batuan 760 m/s.
+// Model ini tidak punya suku kedalaman, sehingga gempa slab dalam akan dianggap dangkal dan
+// terlalu kuat. Kedalaman di bawah bjfMaxDepth karena itu ditambahkan ke jarak.
+type BJF97 struct{}
+
+func (BJF97) Name() string { return "bjf97" }
+
+func (BJF97) PGA(magnitude, distance, depth float64) float64 {
+	// ln(Y) = b1 + b2*(M-6) + b3*(M-6)^2 + b5*ln(r) + bV*ln(Vs/Va), r = sqrt(Rjb^2 + h^2)
+	// Jarak Joyner-Boore didekati dengan jarak episentral. Y dalam g.
+	const (
+		b1 = -0.242
+		b2 = 0.527
+		b3 = 0.0
+		b5 = -0.778
+		bV = -0.371
+		va = 1396.0
+		h  = 5.57
+		vs = 760.0
+	)
+	excess := math.Max(0, depth-bjfMaxDepth)
+	r := math.Sqrt(distance*distance + h*h + excess*excess)
+	m := magnitude - 6
+	lnY := b1 + b2*m + b3*m*m + b5*math.Log(r) + bV*math.Log(vs/va)
+	return math.Exp(lnY) * gravity
+}
+
+// models daftar GMPE yang bisa dipilih lewat konfigurasi
+var models = map[string]GMPE{
+	FukushimaTanaka{}.Name(): FukushimaTanaka{},
+	Youngs97{}.Name():        Youngs97{},
+	BJF97{}.Name():           BJF97{},
+}
+
+// model GMPE yang sedang dipakai oleh IsWithinFeltRadius
+var model GMPE = FukushimaTanaka{}
+
+// ModelByName mengembalikan GMPE berdasarkan nama
+func ModelByName(name string) (GMPE, error) {
+	m, ok := models[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return nil, fmt.Errorf("unknown GMPE model %q", name)
+	}
+	return m, nil
+}
+
+// SetModel mengganti GMPE yang dipakai. Nama kosong berarti tetap memakai default.
+func SetModel(name string) error {
+	if name == "" {
+		return nil
+	}
+	m, err := ModelByName(name)
+	if err != nil {
+		return err
+	}
+	model = m
+	return nil
+}
+
+// Model mengembalikan GMPE yang sedang dipakai
+func Model() GMPE {
+	return model
+}
+
+// PGAToMMI mengonversi PGA (cm/s^2) ke MMI menggunakan Worden et al. (2012), dibatasi I–X
+func PGAToMMI(pga float64) float64 {
+	if pga <= 0 {
+		return 1
+	}
+	logPGA := math.Log10(pga)
+	var mmi float64
+	if logPGA <= 1.57 {
+		mmi = 1.78 + 1.55*logPGA
+	} else {
+		mmi = -1.60 + 3.70*logPGA
+	}
+	return math.Max(1, math.Min(10, mmi))
+}
+
+// hypocentralDistance menghitung jarak hiposenter dari jarak episentral dan kedalaman
+func hypocentralDistance(distance, depth float64) float64 {
+	return math.Sqrt(distance*distance + depth*depth)
+}
+
+// romans angka romawi skala MMI I–XII
+var romans = []string{"I", "II", "III", "IV", "V", "VI", "VII", "VIII", "IX", "X", "XI", "XII"}
+
+// MMIRoman mengubah nilai MMI menjadi angka romawi (dibulatkan ke bawah)
+func MMIRoman(mmi float64) string {
+	i := int(math.Floor(mmi)) - 1
+	if i < 0 {
+		i = 0
+	}
+	if i >= len(romans) {
+		i = len(romans) - 1
+	}
+	return romans[i]
+}
@@ -0,0 +1,121 @@
+package ngitung
+
+import (
+	"math"
+	"testing"
+)
+
+// PGA referensi (cm/s^2) dari persamaan dan koefisien yang dipublikasikan tiap model:
+// Fukushima & Tanaka (1990), Youngs et al. (1997) interface di batuan, dan
+// Boore, Joyner & Fumal (1997) dengan Vs30 760 m/s dan mekanisme tidak diketahui.
+func TestGMPEReferenceValues(t *testing.T) {
+	tests := []struct {
+		model     GMPE
+		magnitude float64
+		distance  float64
+		depth     float64
+		want      float64
+	}{
+		{FukushimaTanaka{}, 6.0, 0, 10, 276.72},
+		{FukushimaTanaka{}, 6.0, 50, 10, 64.11},
+		{FukushimaTanaka{}, 7.0, 100, 30, 50.98},
+		{FukushimaTanaka{}, 7.3, 0, 212, 15.34},
+		{Youngs97{}, 6.0, 0, 10, 190.20},
+		{Youngs97{}, 6.0, 50, 10, 49.91},
+		{Youngs97{}, 7.0, 100, 30, 45.28},
+		{Youngs97{}, 7.3, 0, 212, 58.51},
+		// M6 tepat di atas sesar sekitar 0.26 g, sesuai kurva BJF97 untuk batuan
+		{BJF97{}, 6.0, 0, 10, 253.58},
+		{BJF97{}, 6.0, 50, 10, 45.76},
+		{BJF97{}, 7.0, 100, 30, 45.37},
+	}
+
+	for _, tt := range tests {
+		got := tt.model.PGA(tt.magnitude, tt.distance, tt.depth)
+		if math.Abs(got-tt.want)/tt.want > 0.001 {
+			t.Errorf("%s.PGA(M%.1f, %.0f km, %.0f km) = %.2f, want %.2f", tt.model.Name(), tt.magnitude, tt.distance, tt.depth, got, tt.want)
+		}
+	}
+}
+
+func TestGMPEDecreasesWithDistance(t *testing.T) {
+	for _, m := range []GMPE{FukushimaTanaka{}, Youngs97{}, BJF97{}} {
+		previous := math.Inf(1)
+		for _, distance := range []float64{0, 10, 50, 100, 300, 1000} {
+			pga := m.PGA(6.5, distance, 20)
+			if pga >= previous {
+				t.Errorf("%s: PGA at %.0f km = %.2f, want lower than %.2f", m.Name(), distance, pga, previous)
+			}
+			previous = pga
+		}
+	}
+}
+
+func TestBJF97DeepEvent(t *testing.T) {
+	m := BJF97{}
+
+	// di dalam kerak kedalaman tidak berpengaruh
+	if shallow, crustal := m.PGA(6.5, 20, 5), m.PGA(6.5, 20, bjfMaxDepth); shallow != crustal {
+		t.Errorf("PGA at %d km depth = %.2f, want %.2f as at 5 km", bjfMaxDepth, crustal, shallow)
+	}
+
+	// gempa slab dalam tidak boleh sekuat gempa dangkal
+	shallow := m.PGA(7.3, 0, 10)
+	deep := m.PGA(7.3, 0, 212)
+	if deep >= shallow/4 {
+		t.Errorf("PGA of a 212 km deep event = %.2f, want well below the shallow %.2f", deep, shallow)
+	}
+}
+
+// Batas cabang Worden et al. (2012): log10(PGA) = 1.57 memberi MMI 4.22, kedua persamaan
+// bertemu di titik ini.
+func TestPGAToMMIWorden2012(t *testing.T) {
+	tests := []struct {
+		pga  float64
+		want float64
+	}{
+		{math.Pow(10, 1.57), 4.2135},
+		{math.Pow(10, 1.57) * 1.0001, 4.2094},
+		{10, 3.33},
+		{100, 5.80},
+		{1000, 9.50},
+		{math.Pow(10, 0.5), 2.555},
+		// dibatasi I–X
+		{0, 1},
+		{-5, 1},
+		{0.1, 1},
+		{5000, 10},
+	}
+
+	for _, tt := range tests {
+		if got := PGAToMMI(tt.pga); math.Abs(got-tt.want) > 0.001 {
+			t.Errorf("PGAToMMI(%.2f) = %.4f, want %.4f", tt.pga, got, tt.want)
+		}
+	}
+
+	// kedua cabang hampir bersambung di titik batas, 4.22 pada publikasi sudah dibulatkan
+	low, high := PGAToMMI(math.Pow(10, 1.57)), PGAToMMI(math.Pow(10, 1.5701))
+	if math.Abs(low-4.22) > 0.015 || math.Abs(high-4.22) > 0.015 {
+		t.Errorf("MMI at the breakpoint = %.4f / %.4f, want about 4.22", low, high)
+	}
+}
+
+func TestModelByName(t *testing.T) {
+	for _, name := range []string{"fukushima-tanaka", "youngs97", " BJF97 "} {
+		if _, err := ModelByName(name); err != nil {
+			t.Errorf("ModelByName(%q): %v", name, err)
+		}
+	}
+	if _, err := ModelByName("unknown"); err == nil {
+		t.Error("ModelByName(unknown) returned no error")
+	}
+}
+
+func TestMMIRoman(t *testing.T) {
+	tests := map[float64]string{0: "I", 1: "I", 3.9: "III", 6.2: "VI", 12: "XII", 15: "XII"}
+	for mmi, want := range tests {
+		if got := MMIRoman(mmi); got != want {
+			t.Errorf("MMIRoman(%.1f) = %s, want %s", mmi, got, want)
+		}
+	}
+}
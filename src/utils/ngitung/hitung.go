@@ -27,37 +27,30 @@ func haversine(loc1, loc2 Location) float64 {
 	return R * c
 }
 
-// calculatePHA menghitung Peak Horizontal Acceleration (PHA) menggunakan rumus Fukushima-Tanaka
-func calculatePHA(magnitude, distance float64) float64 {
-	// Rumus: log10(A) = 0.41*M - log10(R + 0.032 * 10^(0.41*M)) - 0.0034*R + 1.30
-	term1 := 0.41 * magnitude
-	term2 := math.Log10(distance + 0.032*math.Pow(10, 0.41*magnitude))
-	term3 := 0.0034 * distance
-	logA := term1 - term2 - term3 + 1.30
-	return math.Pow(10, logA) // A dalam cm/s^2
+// Distance menghitung jarak episentral antara dua lokasi (dalam km)
+func Distance(loc1, loc2 Location) float64 {
+	return haversine(loc1, loc2)
 }
 
-// phaToMMI mengonversi PHA ke skala Modified Mercalli Intensity (MMI)
-func phaToMMI(pha float64) float64 {
-	// Hubungan kasar: MMI ≈ 1 + 2 * log10(PHA)
-	return 1 + 2*math.Log10(pha)
-}
-
-// isWithinFeltRadius memeriksa apakah lokasi berada dalam radius guncangan yang terasa (MMI >= 3)
-func IsWithinFeltRadius(quakeLoc, targetLoc Location, magnitude float64) (bool, float64, float64) {
+// isWithinFeltRadius memeriksa apakah lokasi berada dalam radius guncangan yang terasa (MMI >= 3).
+// Intensitas dihitung dari jarak hiposenter, sehingga gempa dalam (mis. 300 km di Laut Banda)
+// tidak lagi dianggap sama dengan gempa dangkal. Jarak yang dikembalikan adalah jarak episentral.
+// PGA dihitung dengan GMPE yang dipilih lewat SetModel, lalu dikonversi ke MMI (Worden et al. 2012).
+func IsWithinFeltRadius(quakeLoc, targetLoc Location, magnitude, depth float64) (bool, float64, float64) {
 	// Hitung jarak menggunakan formula Haversine
 	distance := haversine(quakeLoc, targetLoc)
+	hypocentral := math.Sqrt(distance*distance + depth*depth)
 
 	// Jika jarak sangat kecil, gunakan nilai PHA default (620 cm/s^2) sesuai artikel
 	var pha float64
-	if distance < 1 { // Untuk jarak sangat dekat (< 1 km)
+	if hypocentral < 1 { // Untuk jarak sangat dekat (< 1 km)
 		pha = 620
 	} else {
-		pha = calculatePHA(magnitude, distance)
+		pha = model.PGA(magnitude, distance, depth)
 	}
 
 	// Konversi PHA ke MMI
-	mmi := phaToMMI(pha)
+	mmi := PGAToMMI(pha)
 
 	// Guncangan terasa jika MMI >= 3
 	isFelt := mmi >= 3
@@ -65,17 +58,49 @@ func IsWithinFeltRadius(quakeLoc, targetLoc Location, magnitude float64) (bool,
 	return isFelt, distance, mmi
 }
 
+// maxRadius batas atas pencarian radius terasa (km)
+const maxRadius = 3000
+
+// MaxFeltRadius menghitung jarak episentral terjauh (km) yang masih mencapai minMMI
+// dengan GMPE yang sedang dipakai. Dipakai untuk membatasi kandidat penerima notifikasi.
+func MaxFeltRadius(magnitude, depth, minMMI float64) float64 {
+	felt := func(distance float64) bool {
+		_, _, mmi := IsWithinFeltRadius(Location{}, Location{Lat: distance / 111.195}, magnitude, depth)
+		return mmi >= minMMI
+	}
+
+	if !felt(0) {
+		return 0
+	}
+	if felt(maxRadius) {
+		return maxRadius
+	}
+
+	// Intensitas turun monoton terhadap jarak, jadi cukup bisection
+	low, high := 0.0, float64(maxRadius)
+	for high-low > 1 {
+		mid := (low + high) / 2
+		if felt(mid) {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+	return high
+}
+
 //
 //func main() {
 //	// Data gempa baru
 //	quakeLoc := Location{Lat: -5.42, Lon: 123.12} // Episentrum gempa
 //	magnitude := 3.2                              // Magnitudo gempa
+//	depth := 10.0                                 // Kedalaman gempa (km)
 //
 //	// Lokasi target (Siotapina, Buton, perkiraan koordinat)
 //	targetLoc := Location{Lat: -5.36, Lon: 123.16} // Koordinat Siotapina (perkiraan)
 //
 //	// Periksa apakah lokasi berada dalam radius guncangan yang terasa
-//	isFelt, distance, mmi := isWithinFeltRadius(quakeLoc, targetLoc, magnitude)
+//	isFelt, distance, mmi := isWithinFeltRadius(quakeLoc, targetLoc, magnitude, depth)
 //
 //	// Tampilkan hasil
 //	fmt.Printf("Jarak ke lokasi: %.2f km\n", distance)
@@ -0,0 +1,98 @@
+package ngitung
+
+import (
+	"math"
+	"testing"
+)
+
+// Gempa Laut Banda 24 Juni 2019 (M7.3, hiposenter sekitar 212 km) dibandingkan dengan gempa
+// kerak dangkal bermagnitudo sama di Palu (episenter 28 September 2018, kedalaman 10 km).
+var (
+	bandaSea = Location{Lat: -6.41, Lon: 129.17}
+	palu     = Location{Lat: -0.18, Lon: 119.85}
+)
+
+const (
+	regressionMagnitude = 7.3
+	bandaSeaDepth       = 212.0
+	paluDepth           = 10.0
+)
+
+func TestHaversine(t *testing.T) {
+	// Jakarta - Bandung sekitar 119 km
+	got := Distance(Location{Lat: -6.2, Lon: 106.8166}, Location{Lat: -6.9175, Lon: 107.6191})
+	if math.Abs(got-119) > 2 {
+		t.Errorf("Distance(Jakarta, Bandung) = %.1f km, want about 119 km", got)
+	}
+
+	if got := Distance(bandaSea, bandaSea); got != 0 {
+		t.Errorf("Distance to itself = %f, want 0", got)
+	}
+}
+
+func TestIsWithinFeltRadiusDeepVersusShallow(t *testing.T) {
+	withModel(t, "fukushima-tanaka")
+
+	for _, distance := range []float64{0, 50, 150, 300} {
+		// titik sejauh distance km ke utara dari episenter
+		offset := distance / 111.195
+		_, deepDistance, deepMMI := IsWithinFeltRadius(bandaSea, Location{Lat: bandaSea.Lat + offset, Lon: bandaSea.Lon}, regressionMagnitude, bandaSeaDepth)
+		_, shallowDistance, shallowMMI := IsWithinFeltRadius(palu, Location{Lat: palu.Lat + offset, Lon: palu.Lon}, regressionMagnitude, paluDepth)
+
+		if math.Abs(deepDistance-distance) > 1 || math.Abs(shallowDistance-distance) > 1 {
+			t.Fatalf("epicentral distance = %.1f / %.1f km, want %.0f km", deepDistance, shallowDistance, distance)
+		}
+		if deepMMI >= shallowMMI {
+			t.Errorf("at %.0f km: deep MMI %.2f, want lower than shallow MMI %.2f", distance, deepMMI, shallowMMI)
+		}
+	}
+}
+
+func TestMaxFeltRadiusDeepVersusShallow(t *testing.T) {
+	withModel(t, "fukushima-tanaka")
+
+	for _, minMMI := range []float64{3, 4, 5, 6} {
+		deep := MaxFeltRadius(regressionMagnitude, bandaSeaDepth, minMMI)
+		shallow := MaxFeltRadius(regressionMagnitude, paluDepth, minMMI)
+		if deep >= shallow {
+			t.Errorf("MMI %.0f: deep radius %.0f km, want smaller than shallow radius %.0f km", minMMI, deep, shallow)
+		}
+	}
+}
+
+func TestMaxFeltRadiusMatchesIsWithinFeltRadius(t *testing.T) {
+	withModel(t, "fukushima-tanaka")
+
+	radius := MaxFeltRadius(regressionMagnitude, paluDepth, 5)
+	if radius <= 0 || radius >= maxRadius {
+		t.Fatalf("MaxFeltRadius = %.0f km, want between 0 and %d km", radius, maxRadius)
+	}
+
+	inside := Location{Lat: palu.Lat + (radius-5)/111.195, Lon: palu.Lon}
+	outside := Location{Lat: palu.Lat + (radius+5)/111.195, Lon: palu.Lon}
+	if _, _, mmi := IsWithinFeltRadius(palu, inside, regressionMagnitude, paluDepth); mmi < 5 {
+		t.Errorf("MMI inside the radius = %.2f, want at least 5", mmi)
+	}
+	if _, _, mmi := IsWithinFeltRadius(palu, outside, regressionMagnitude, paluDepth); mmi >= 5 {
+		t.Errorf("MMI outside the radius = %.2f, want below 5", mmi)
+	}
+}
+
+func TestMaxFeltRadiusNotFelt(t *testing.T) {
+	withModel(t, "fukushima-tanaka")
+
+	if got := MaxFeltRadius(2, 300, 5); got != 0 {
+		t.Errorf("MaxFeltRadius of a small deep event = %.0f km, want 0", got)
+	}
+}
+
+// withModel selects a GMPE for the test and restores the previous one afterwards
+func withModel(t *testing.T, name string) {
+	t.Helper()
+
+	previous := model
+	t.Cleanup(func() { model = previous })
+	if err := SetModel(name); err != nil {
+		t.Fatal(err)
+	}
+}
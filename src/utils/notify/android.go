@@ -1,9 +1,345 @@
 package notify
 
+import (
+	"bmkg/src/utils"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// androidMaxLength batas panjang body notifikasi Android yang masih terbaca
+const androidMaxLength = 1024
+
+const (
+	// fcmAPIURL is the FCM HTTP v1 endpoint, %s is the Firebase project id
+	fcmAPIURL = "https://fcm.googleapis.com/v1/projects/%s/messages:send"
+	// fcmScope is the OAuth scope needed to send messages
+	fcmScope = "https://www.googleapis.com/auth/firebase.messaging"
+	// fcmTimeout bounds a single API call
+	fcmTimeout = 10 * time.Second
+	// fcmTokenLeeway renews the access token this long before it expires
+	fcmTokenLeeway = 5 * time.Minute
+)
+
+// PushTokenStore gives the notifier the device tokens of a user and lets it prune
+// tokens FCM no longer accepts
+type PushTokenStore interface {
+	Tokens(userID string) ([]string, error)
+	Remove(token string) error
+}
+
+// FCMServiceAccount is the part of a Google service account key file used for FCM
+type FCMServiceAccount struct {
+	ProjectID    string `json:"project_id"`
+	ClientEmail  string `json:"client_email"`
+	PrivateKey   string `json:"private_key"`
+	PrivateKeyID string `json:"private_key_id"`
+	TokenURI     string `json:"token_uri"`
+}
+
+// LoadFCMServiceAccount reads a service account key file downloaded from the Firebase console
+func LoadFCMServiceAccount(path string) (FCMServiceAccount, error) {
+	var account FCMServiceAccount
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return account, fmt.Errorf("failed to read fcm credentials: %w", err)
+	}
+	if err := json.Unmarshal(data, &account); err != nil {
+		return account, fmt.Errorf("failed to decode fcm credentials: %w", err)
+	}
+	if account.ProjectID == "" || account.ClientEmail == "" || account.PrivateKey == "" {
+		return account, fmt.Errorf("fcm credentials without project_id, client_email or private_key")
+	}
+	if account.TokenURI == "" {
+		account.TokenURI = "https://oauth2.googleapis.com/token"
+	}
+
+	return account, nil
+}
+
+// AndroidNotifier mengirim push notification lewat FCM HTTP v1. Recipient adalah id user,
+// notifikasi dikirim ke semua device token milik user tersebut.
+type AndroidNotifier struct {
+	account FCMServiceAccount
+	tokens  PushTokenStore
+	client  *utils.HTTPClient
+
+	mu          sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+}
+
+// NewAndroidNotifier creates the notifier for the "android" channel. Without a service
+// account every send fails permanently.
+func NewAndroidNotifier(account FCMServiceAccount, tokens PushTokenStore, client *utils.HTTPClient) *AndroidNotifier {
+	return &AndroidNotifier{
+		account: account,
+		tokens:  tokens,
+		client:  client,
+	}
+}
+
+func (n *AndroidNotifier) Name() string { return "android" }
+
+func (n *AndroidNotifier) Capabilities() Capabilities {
+	return Capabilities{Images: true, MaxLength: androidMaxLength}
+}
+
+func (n *AndroidNotifier) Send(recipient string, message Message) error {
+	_, err := n.SendTracked(recipient, message)
+	return err
+}
+
+// SendTracked sends the message to every device of the user and returns the FCM message
+// name of the first successful send. The delivery counts as sent once one device got it,
+// so a retry never alerts the other devices twice. Tokens FCM rejects are pruned.
+func (n *AndroidNotifier) SendTracked(recipient string, message Message) (string, error) {
+	if n.account.ProjectID == "" || n.tokens == nil {
+		return "", fmt.Errorf("fcm is not configured: %w", ErrPermanent)
+	}
+
+	tokens, err := n.tokens.Tokens(recipient)
+	if err != nil {
+		return "", err
+	}
+	if len(tokens) == 0 {
+		return "", fmt.Errorf("%w: user %s has no push token", ErrInvalidRecipient, recipient)
+	}
+
+	var messageID string
+	var lastErr error
+	for _, token := range tokens {
+		name, err := n.post(n.buildMessage(token, message))
+		if err != nil {
+			if errors.Is(err, ErrInvalidRecipient) {
+				log.Printf("Removing invalid push token of user %s: %v", recipient, err)
+				if err := n.tokens.Remove(token); err != nil {
+					log.Printf("Error removing push token: %v", err)
+				}
+			}
+			lastErr = err
+			continue
+		}
+
+		if messageID == "" {
+			messageID = name
+		}
+	}
+
+	if messageID == "" {
+		return "", lastErr
+	}
+	return messageID, nil
+}
+
+// buildMessage creates an FCM message. High priority alerts are data-only, so the app
+// itself shows the full screen alert and sounds the siren even when it is in background.
+func (n *AndroidNotifier) buildMessage(token string, message Message) map[string]interface{} {
+	data := map[string]string{
+		"text":     message.Text,
+		"priority": message.Priority,
+	}
+	if message.ImageURL != "" {
+		data["image_url"] = message.ImageURL
+	}
+	if message.HasLocation {
+		data["lat"] = strconv.FormatFloat(message.Lat, 'f', -1, 64)
+		data["lon"] = strconv.FormatFloat(message.Lon, 'f', -1, 64)
+	}
+	if len(message.Payload) > 0 {
+		data["payload"] = string(message.Payload)
+	}
+
+	body := map[string]interface{}{
+		"token": token,
+		"data":  data,
+	}
+
+	if message.Priority == PriorityHigh {
+		body["android"] = map[string]interface{}{"priority": "HIGH"}
+	} else {
+		title, text, _ := strings.Cut(message.Text, "\n")
+		notification := map[string]interface{}{"title": title, "body": text}
+		if message.ImageURL != "" {
+			notification["image"] = message.ImageURL
+		}
+		body["notification"] = notification
+		body["android"] = map[string]interface{}{"priority": "NORMAL"}
+	}
+
+	return map[string]interface{}{"message": body}
+}
+
+// fcmResponse is the part of the send response and error body we use
+type fcmResponse struct {
+	Name  string `json:"name"`
+	Error *struct {
+		Message string `json:"message"`
+		Status  string `json:"status"`
+		Details []struct {
+			ErrorCode string `json:"errorCode"`
+		} `json:"details"`
+	} `json:"error"`
+}
+
+func (n *AndroidNotifier) post(body map[string]interface{}) (string, error) {
+	accessToken, err := n.token()
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), fcmTimeout)
+	defer cancel()
+
+	headers := map[string]string{"Authorization": "Bearer " + accessToken}
+	data, err := n.client.PostJSONWithContext(ctx, fmt.Sprintf(fcmAPIURL, n.account.ProjectID), headers, body)
+	if err != nil {
+		var httpErr *utils.HTTPError
+		if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusUnauthorized {
+			n.invalidateToken()
+		}
+		return "", classifyFCMError(err)
+	}
+
+	var response fcmResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		return "", fmt.Errorf("failed to decode fcm response: %w", err)
+	}
+
+	return response.Name, nil
+}
+
+// classifyFCMError sorts an API failure into an invalid token, another permanent failure
+// or a transient one. Quota, 5xx and network errors are transient.
+func classifyFCMError(err error) error {
+	var httpErr *utils.HTTPError
+	if !errors.As(err, &httpErr) {
+		return err
+	}
+
+	var response fcmResponse
+	if json.Unmarshal(httpErr.Body, &response) != nil || response.Error == nil {
+		if httpErr.StatusCode >= http.StatusInternalServerError || httpErr.StatusCode == http.StatusTooManyRequests {
+			return err
+		}
+		return fmt.Errorf("%w: %v", ErrPermanent, err)
+	}
+
+	code := response.Error.Status
+	for _, detail := range response.Error.Details {
+		if detail.ErrorCode != "" {
+			code = detail.ErrorCode
+		}
+	}
+
+	switch code {
+	case "UNREGISTERED", "SENDER_ID_MISMATCH":
+		return fmt.Errorf("%w: fcm %s: %s", ErrInvalidRecipient, code, response.Error.Message)
+	case "INVALID_ARGUMENT":
+		// Also used for a malformed message, only a bad token may prune it
+		if strings.Contains(strings.ToLower(response.Error.Message), "registration token") {
+			return fmt.Errorf("%w: fcm %s: %s", ErrInvalidRecipient, code, response.Error.Message)
+		}
+		return fmt.Errorf("%w: fcm %s: %s", ErrPermanent, code, response.Error.Message)
+	case "QUOTA_EXCEEDED", "UNAVAILABLE", "INTERNAL", "UNAUTHENTICATED":
+		return fmt.Errorf("fcm %s: %s: %w", code, response.Error.Message, err)
+	default:
+		if httpErr.StatusCode >= http.StatusInternalServerError || httpErr.StatusCode == http.StatusTooManyRequests {
+			return fmt.Errorf("fcm %s: %s: %w", code, response.Error.Message, err)
+		}
+		return fmt.Errorf("%w: fcm %s: %s", ErrPermanent, code, response.Error.Message)
+	}
+}
+
+// token returns a cached OAuth access token, exchanging a new signed JWT when it is about to expire
+func (n *AndroidNotifier) token() (string, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.accessToken != "" && time.Now().Add(fcmTokenLeeway).Before(n.expiresAt) {
+		return n.accessToken, nil
+	}
+
+	assertion, err := n.signAssertion(time.Now())
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	headers := map[string]string{"Content-Type": "application/x-www-form-urlencoded"}
+
+	ctx, cancel := context.WithTimeout(context.Background(), fcmTimeout)
+	defer cancel()
+
+	data, err := n.client.PostWithContext(ctx, n.account.TokenURI, headers, []byte(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to get fcm access token: %w", err)
+	}
+
+	var response struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.Unmarshal(data, &response); err != nil || response.AccessToken == "" {
+		return "", fmt.Errorf("invalid fcm access token response: %v", err)
+	}
+
+	n.accessToken = response.AccessToken
+	n.expiresAt = time.Now().Add(time.Duration(response.ExpiresIn) * time.Second)
+	return n.accessToken, nil
+}
+
+func (n *AndroidNotifier) invalidateToken() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.accessToken = ""
+}
+
+// signAssertion creates the RS256 JWT of the service account for the token exchange
+func (n *AndroidNotifier) signAssertion(now time.Time) (string, error) {
+	key, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(n.account.PrivateKey))
+	if err != nil {
+		return "", fmt.Errorf("%w: invalid fcm private key: %v", ErrPermanent, err)
+	}
+
+	claims := jwt.MapClaims{
+		"iss":   n.account.ClientEmail,
+		"scope": fcmScope,
+		"aud":   n.account.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	if n.account.PrivateKeyID != "" {
+		token.Header["kid"] = n.account.PrivateKeyID
+	}
+
+	return token.SignedString(key)
+}
+
 // send to firebase to notify android
-// function to send notification to android
-func SendAndroidNotification(deviceToken, message string) error {
-	// Implement the logic to send a notification to Android
-	// This is a placeholder implementation
-	return nil
+// function to send notification to all android devices of a user
+func SendAndroidNotification(userID, message string) error {
+	notifier, ok := Get("android")
+	if !ok {
+		return fmt.Errorf("%w: android", ErrUnknownChannel)
+	}
+	return notifier.Send(userID, Message{Text: message})
 }
@@ -0,0 +1,99 @@
+package notify
+
+import (
+	"bmkg/src/mail"
+	"bmkg/src/messages"
+	"bmkg/src/utils"
+	"context"
+	"fmt"
+	"log"
+	netmail "net/mail"
+	"path"
+	"strings"
+	"time"
+)
+
+// emailImageTimeout bounds downloading the image to attach
+const emailImageTimeout = 15 * time.Second
+
+// EmailNotifier mengirim notifikasi lewat email, untuk pelanggan institusi seperti sekolah
+// dan rumah sakit. Recipient adalah alamat email.
+type EmailNotifier struct {
+	mailer *mail.Mailer
+	client *utils.HTTPClient
+}
+
+// NewEmailNotifier creates the notifier for the "email" channel
+func NewEmailNotifier(mailer *mail.Mailer, client *utils.HTTPClient) *EmailNotifier {
+	return &EmailNotifier{
+		mailer: mailer,
+		client: client,
+	}
+}
+
+func (n *EmailNotifier) Name() string { return "email" }
+
+func (n *EmailNotifier) Capabilities() Capabilities {
+	return Capabilities{RichText: true, Images: true, LocationPin: true}
+}
+
+// Send renders the HTML and text email. The first line of the text is the subject and
+// the image, e.g. the shakemap, is attached. A failed download only drops the attachment.
+// The wording around the text comes from the email.* templates in the message language.
+func (n *EmailNotifier) Send(recipient string, message Message) error {
+	if _, err := netmail.ParseAddress(recipient); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidRecipient, recipient, err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(message.Text), "\n")
+	alert := mail.Alert{Title: lines[0], Lines: lines[1:]}
+
+	selector := messages.Selector{Channel: n.Name(), Language: message.Language}
+	subject, err := messages.Render(messages.KeyEmailSubject, selector, map[string]string{"Title": alert.Title})
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrPermanent, err)
+	}
+	for key, label := range map[string]*string{
+		messages.KeyEmailMapLink:  &alert.MapLabel,
+		messages.KeyEmailShakemap: &alert.ShakemapLabel,
+		messages.KeyEmailFooter:   &alert.Footer,
+	} {
+		if *label, err = messages.Render(key, selector, nil); err != nil {
+			return fmt.Errorf("%w: %v", ErrPermanent, err)
+		}
+	}
+	if message.HasLocation {
+		alert.MapURL = fmt.Sprintf("https://www.google.com/maps?q=%f,%f", message.Lat, message.Lon)
+	}
+
+	var attachments map[string][]byte
+	if message.ImageURL != "" {
+		image, err := n.download(message.ImageURL)
+		if err != nil {
+			log.Printf("Failed to download email attachment %s: %v", message.ImageURL, err)
+		} else {
+			alert.Shakemap = path.Base(message.ImageURL)
+			attachments = map[string][]byte{alert.Shakemap: image}
+		}
+	}
+
+	html, text, err := mail.RenderAlert(alert)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrPermanent, err)
+	}
+
+	return n.mailer.Send(mail.Message{
+		To:          recipient,
+		Subject:     subject,
+		Body:        text,
+		HTML:        html,
+		Attachments: attachments,
+	})
+}
+
+func (n *EmailNotifier) download(url string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), emailImageTimeout)
+	defer cancel()
+
+	return n.client.GetWithContext(ctx, url, nil)
+}
@@ -1,11 +1,79 @@
 package notify
 
-import "bmkg/src/worker/mqtt"
+import (
+	"bmkg/src/worker/mqtt"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// MQTTAlertVersion versi format payload alert MQTT. Naikkan jika field berubah
+// secara tidak kompatibel agar firmware bisa menolak payload yang tidak dikenal.
+const MQTTAlertVersion = 1
+
+// mqttQoS alert harus sampai minimal sekali ke perangkat
+const mqttQoS = 1
+
+// MQTTAlert payload alert gempa untuk perangkat IoT (sirene)
+type MQTTAlert struct {
+	Version       int       `json:"v"`
+	EventID       string    `json:"event_id"`
+	Revision      int       `json:"revision"`
+	Correction    bool      `json:"correction"`
+	Magnitude     float64   `json:"magnitude"`
+	DepthKm       float64   `json:"depth_km"`
+	DistanceKm    float64   `json:"distance_km"`
+	MMI           float64   `json:"mmi"`
+	Tier          string    `json:"tier"`
+	Siren         string    `json:"siren"`
+	SirenDuration int       `json:"siren_duration"`
+	OriginTime    time.Time `json:"origin_time"`
+	ExpiresAt     time.Time `json:"expires_at"`
+}
+
+// MQTTNotifier mengirim alert ke perangkat IoT. Recipient adalah topic MQTT
+// dan Message.Payload berisi MQTTAlert yang sudah di-encode, lihat NewMQTTMessage.
+type MQTTNotifier struct {
+	client mqtt.MQTTClient
+}
+
+// NewMQTTNotifier creates the notifier for the "mqtt" channel
+func NewMQTTNotifier(client mqtt.MQTTClient) *MQTTNotifier {
+	return &MQTTNotifier{client: client}
+}
+
+func (n *MQTTNotifier) Name() string { return "mqtt" }
+
+func (n *MQTTNotifier) Capabilities() Capabilities {
+	return Capabilities{}
+}
+
+func (n *MQTTNotifier) Send(recipient string, message Message) error {
+	if len(message.Payload) == 0 {
+		return fmt.Errorf("mqtt notifier expects a payload")
+	}
+	return n.client.Publish(recipient, mqttQoS, false, []byte(message.Payload))
+}
+
+// NewMQTTMessage encodes alert as the payload of a message for the "mqtt" channel
+func NewMQTTMessage(alert MQTTAlert) (Message, error) {
+	alert.Version = MQTTAlertVersion
+
+	payload, err := json.Marshal(alert)
+	if err != nil {
+		return Message{}, fmt.Errorf("failed to encode mqtt alert: %w", err)
+	}
+
+	return Message{Payload: payload}, nil
+}
 
 // send to mqtt
 // function to send notification to mqtt
-func SendMQTTNotification(mqttclient mqtt.MQTTClient, topic, message string) error {
-	// Implement the logic to send a notification to MQTT
-	// This is a placeholder implementation
-	return nil
+func SendMQTTNotification(mqttclient mqtt.MQTTClient, topic string, alert MQTTAlert) error {
+	message, err := NewMQTTMessage(alert)
+	if err != nil {
+		return err
+	}
+
+	return mqttclient.Publish(topic, mqttQoS, false, []byte(message.Payload))
 }
@@ -0,0 +1,139 @@
+package notify
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+var (
+	// ErrUnknownChannel is returned when no notifier is registered for a channel
+	ErrUnknownChannel = errors.New("unknown notification channel")
+	// ErrPermanent marks failures that retrying cannot fix
+	ErrPermanent = errors.New("permanent delivery failure")
+	// ErrInvalidRecipient is a permanent failure caused by the recipient address
+	ErrInvalidRecipient = fmt.Errorf("invalid recipient: %w", ErrPermanent)
+)
+
+// Capabilities describes what a channel is able to render. The registry uses it to
+// degrade a Message before handing it to the notifier.
+type Capabilities struct {
+	RichText    bool
+	Images      bool
+	LocationPin bool
+	// MaxLength is the maximum text length in characters, 0 means unlimited
+	MaxLength int
+}
+
+// Message is a channel independent notification. It is stored as JSON in the outbox.
+type Message struct {
+	Text     string `json:"text,omitempty"`
+	ImageURL string `json:"image_url,omitempty"`
+	// Location pin, only used when HasLocation is set
+	HasLocation bool    `json:"has_location,omitempty"`
+	Lat         float64 `json:"lat,omitempty"`
+	Lon         float64 `json:"lon,omitempty"`
+	// Payload is the structured body for machine channels such as MQTT
+	Payload json.RawMessage `json:"payload,omitempty"`
+	// Priority asks push channels to deliver immediately, see PriorityHigh
+	Priority string `json:"priority,omitempty"`
+	// EarthquakeID and Language let interactive channels add actions about the
+	// earthquake, worded in the language of the text
+	EarthquakeID string `json:"earthquake_id,omitempty"`
+	Language     string `json:"language,omitempty"`
+	// CheckinToken marks a safety check-in question, interactive channels answer it with buttons
+	CheckinToken string `json:"checkin_token,omitempty"`
+}
+
+// PriorityHigh is the Message priority of alerts that must wake the device
+const PriorityHigh = "high"
+
+// Notifier delivers messages over one channel. Recipient is the channel specific
+// address: a chat id, a phone number, a device token or an MQTT topic.
+type Notifier interface {
+	Name() string
+	Capabilities() Capabilities
+	Send(recipient string, message Message) error
+}
+
+// Tracker is implemented by notifiers whose provider returns a message id, used to
+// match delivery receipts arriving later
+type Tracker interface {
+	SendTracked(recipient string, message Message) (string, error)
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Notifier{}
+)
+
+// Register adds a notifier to the registry, replacing one with the same name
+func Register(notifier Notifier) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	registry[notifier.Name()] = notifier
+}
+
+// Get returns the notifier registered for a channel
+func Get(channel string) (Notifier, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	notifier, ok := registry[channel]
+	return notifier, ok
+}
+
+// Channels returns the names of all registered channels
+func Channels() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Send delivers message to recipient over channel, after fitting it to the channel capabilities.
+// It returns the provider message id when the notifier is a Tracker.
+func Send(channel, recipient string, message Message) (string, error) {
+	notifier, ok := Get(channel)
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownChannel, channel)
+	}
+
+	message = Fit(message, notifier.Capabilities())
+
+	var messageID string
+	var err error
+	if tracker, ok := notifier.(Tracker); ok {
+		messageID, err = tracker.SendTracked(recipient, message)
+	} else {
+		err = notifier.Send(recipient, message)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to send %s notification: %w", channel, err)
+	}
+	return messageID, nil
+}
+
+// Fit drops what the channel cannot show and truncates the text to its maximum length
+func Fit(message Message, caps Capabilities) Message {
+	if !caps.Images {
+		message.ImageURL = ""
+	}
+	if !caps.LocationPin {
+		message.HasLocation = false
+	}
+	if caps.MaxLength > 0 {
+		if runes := []rune(message.Text); len(runes) > caps.MaxLength {
+			message.Text = string(runes[:caps.MaxLength-1]) + "…"
+		}
+	}
+	return message
+}
@@ -1,8 +1,53 @@
 package notify
 
-// function to send notification to telegram
-func SendTelegramNotification(chatId, message string) error {
-	// Implement the logic to send a notification to Telegram
-	// This is a placeholder implementation
-	return nil
+import (
+	"bmkg/src/worker/telegram"
+	"fmt"
+	"strconv"
+)
+
+// telegramMaxLength batas panjang pesan Telegram
+const telegramMaxLength = 4096
+
+// TelegramNotifier mengirim notifikasi lewat bot Telegram. Recipient adalah chat id.
+type TelegramNotifier struct {
+	bot *telegram.Bot
+}
+
+// NewTelegramNotifier creates the notifier for the "telegram" channel
+func NewTelegramNotifier(bot *telegram.Bot) *TelegramNotifier {
+	return &TelegramNotifier{bot: bot}
+}
+
+func (n *TelegramNotifier) Name() string { return "telegram" }
+
+func (n *TelegramNotifier) Capabilities() Capabilities {
+	return Capabilities{RichText: true, Images: true, LocationPin: true, MaxLength: telegramMaxLength}
+}
+
+// Send sends the shakemap with the text as caption, a link to the epicenter and the action
+// buttons in one message. The bot falls back to a text message when the shakemap cannot be
+// fetched.
+// A safety check-in is sent as a question with the answer buttons.
+func (n *TelegramNotifier) Send(recipient string, message Message) error {
+	chatID, err := strconv.ParseInt(recipient, 10, 64)
+	if err != nil {
+		return fmt.Errorf("failed to parse telegram chat ID: %w", err)
+	}
+	if message.CheckinToken != "" {
+		return n.bot.SendCheckin(chatID, telegram.Checkin{
+			Text:     message.Text,
+			Token:    message.CheckinToken,
+			Language: message.Language,
+		})
+	}
+	return n.bot.SendAlert(chatID, telegram.Alert{
+		Text:         message.Text,
+		PhotoURL:     message.ImageURL,
+		HasLocation:  message.HasLocation,
+		Lat:          message.Lat,
+		Lon:          message.Lon,
+		EarthquakeID: message.EarthquakeID,
+		Language:     message.Language,
+	})
 }
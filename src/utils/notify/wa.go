@@ -1,9 +1,262 @@
 package notify
 
+import (
+	"bmkg/src/messages"
+	"bmkg/src/utils"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+// whatsAppMaxLength batas panjang body pesan WhatsApp
+const whatsAppMaxLength = 4096
+
+// whatsAppCaptionMaxLength batas panjang caption gambar WhatsApp
+const whatsAppCaptionMaxLength = 1024
+
+// whatsAppAPIURL is the Graph API endpoint of the WhatsApp Cloud API
+const whatsAppAPIURL = "https://graph.facebook.com"
+
+// whatsAppTimeout bounds a single API call
+const whatsAppTimeout = 10 * time.Second
+
+// WhatsAppConfig holds the WhatsApp Cloud API credentials
+type WhatsAppConfig struct {
+	PhoneNumberID string
+	AccessToken   string
+	APIVersion    string
+	// Template is the approved message template used for alerts. Business initiated
+	// messages outside the 24 hour window must be templates; leave it empty to send
+	// plain text, e.g. while testing with a sandbox number.
+	Template         string
+	TemplateLanguage string
+	// TemplateHeaderImage sends the shakemap as the header of Template, which must then be
+	// approved with an IMAGE header
+	TemplateHeaderImage bool
+}
+
+// WhatsAppNotifier mengirim notifikasi WhatsApp lewat Cloud API. Recipient adalah nomor telepon.
+type WhatsAppNotifier struct {
+	config WhatsAppConfig
+	client *utils.HTTPClient
+}
+
+// NewWhatsAppNotifier creates the notifier for the "wa" channel
+func NewWhatsAppNotifier(config WhatsAppConfig, client *utils.HTTPClient) *WhatsAppNotifier {
+	if config.APIVersion == "" {
+		config.APIVersion = "v21.0"
+	}
+	if config.TemplateLanguage == "" {
+		config.TemplateLanguage = "id"
+	}
+
+	return &WhatsAppNotifier{
+		config: config,
+		client: client,
+	}
+}
+
+func (n *WhatsAppNotifier) Name() string { return "wa" }
+
+// Capabilities only claims images when they are sent: always for text messages, for a
+// template only when it has an image header
+func (n *WhatsAppNotifier) Capabilities() Capabilities {
+	images := n.config.Template == "" || n.config.TemplateHeaderImage
+	return Capabilities{RichText: true, Images: images, LocationPin: true, MaxLength: whatsAppMaxLength}
+}
+
+func (n *WhatsAppNotifier) Send(recipient string, message Message) error {
+	_, err := n.SendTracked(recipient, message)
+	return err
+}
+
+// SendTracked sends the alert and returns the WhatsApp message id (wamid). The epicenter
+// pin goes out as a second message; its failure is only logged so the alert is not resent.
+func (n *WhatsAppNotifier) SendTracked(recipient string, message Message) (string, error) {
+	if n.config.PhoneNumberID == "" || n.config.AccessToken == "" {
+		return "", fmt.Errorf("whatsapp is not configured: %w", ErrPermanent)
+	}
+
+	phone, err := utils.NormalizePhoneID(recipient)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s: %v", ErrInvalidRecipient, recipient, err)
+	}
+
+	messageID, err := n.post(n.buildMessage(phone, message))
+	if err != nil {
+		return "", err
+	}
+
+	if message.HasLocation {
+		location := map[string]interface{}{
+			"messaging_product": "whatsapp",
+			"to":                phone,
+			"type":              "location",
+			"location": map[string]interface{}{
+				"latitude":  message.Lat,
+				"longitude": message.Lon,
+				"name":      n.epicenterName(message.Language),
+			},
+		}
+		if _, err := n.post(location); err != nil {
+			log.Printf("Failed to send whatsapp location to %s: %v", phone, err)
+		}
+	}
+
+	return messageID, nil
+}
+
+// buildMessage creates a template message, or a text message when no template is configured.
+// Without template the shakemap goes as an image with the text as caption when it fits.
+func (n *WhatsAppNotifier) buildMessage(phone string, message Message) map[string]interface{} {
+	body := map[string]interface{}{
+		"messaging_product": "whatsapp",
+		"to":                phone,
+	}
+
+	if n.config.Template == "" {
+		if message.ImageURL != "" && len([]rune(message.Text)) <= whatsAppCaptionMaxLength {
+			body["type"] = "image"
+			body["image"] = map[string]interface{}{"link": message.ImageURL, "caption": message.Text}
+			return body
+		}
+		body["type"] = "text"
+		body["text"] = map[string]interface{}{"body": message.Text}
+		return body
+	}
+
+	// Template parameters may not contain new lines
+	text := strings.Join(strings.Fields(strings.ReplaceAll(message.Text, "\n", " | ")), " ")
+
+	components := []map[string]interface{}{
+		{
+			"type": "body",
+			"parameters": []map[string]interface{}{
+				{"type": "text", "text": text},
+			},
+		},
+	}
+	if n.config.TemplateHeaderImage && message.ImageURL != "" {
+		components = append([]map[string]interface{}{{
+			"type": "header",
+			"parameters": []map[string]interface{}{
+				{"type": "image", "image": map[string]interface{}{"link": message.ImageURL}},
+			},
+		}}, components...)
+	}
+
+	body["type"] = "template"
+	body["template"] = map[string]interface{}{
+		"name":       n.config.Template,
+		"language":   map[string]interface{}{"code": n.config.TemplateLanguage},
+		"components": components,
+	}
+	return body
+}
+
+// whatsAppResponse is the part of the send response and error body we use
+type whatsAppResponse struct {
+	Messages []struct {
+		ID string `json:"id"`
+	} `json:"messages"`
+	Error *struct {
+		Message string `json:"message"`
+		Code    int    `json:"code"`
+	} `json:"error"`
+}
+
+func (n *WhatsAppNotifier) post(body map[string]interface{}) (string, error) {
+	url := fmt.Sprintf("%s/%s/%s/messages", whatsAppAPIURL, n.config.APIVersion, n.config.PhoneNumberID)
+	headers := map[string]string{"Authorization": "Bearer " + n.config.AccessToken}
+
+	ctx, cancel := context.WithTimeout(context.Background(), whatsAppTimeout)
+	defer cancel()
+
+	data, err := n.client.PostJSONWithContext(ctx, url, headers, body)
+	if err != nil {
+		return "", classifyWhatsAppError(err)
+	}
+
+	var response whatsAppResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		return "", fmt.Errorf("failed to decode whatsapp response: %w", err)
+	}
+	if len(response.Messages) == 0 {
+		return "", fmt.Errorf("whatsapp response without message id")
+	}
+
+	return response.Messages[0].ID, nil
+}
+
+// whatsAppInvalidRecipient error codes caused by the number itself: not on WhatsApp,
+// undeliverable, or the business number itself
+var whatsAppInvalidRecipient = map[int]bool{
+	131026: true, // message undeliverable
+	131021: true, // recipient cannot be sender
+	131030: true, // recipient not in allowed list (test numbers)
+	133010: true, // phone number not registered
+}
+
+// whatsAppPermanent error codes that retrying cannot fix
+var whatsAppPermanent = map[int]bool{
+	100:    true, // invalid parameter
+	131008: true, // required parameter missing
+	131009: true, // parameter value invalid
+	131047: true, // outside the 24 hour window, a template is needed
+	131051: true, // unsupported message type
+	132000: true, // template parameter count mismatch
+	132001: true, // template does not exist
+	132007: true, // template policy violation
+}
+
+// classifyWhatsAppError sorts an API failure into an invalid number, another permanent
+// failure or a transient one. Rate limits, 5xx and network errors are transient.
+func classifyWhatsAppError(err error) error {
+	var httpErr *utils.HTTPError
+	if !errors.As(err, &httpErr) {
+		return err
+	}
+
+	var response whatsAppResponse
+	if json.Unmarshal(httpErr.Body, &response) != nil || response.Error == nil {
+		if httpErr.StatusCode >= http.StatusInternalServerError || httpErr.StatusCode == http.StatusTooManyRequests {
+			return err
+		}
+		return fmt.Errorf("%w: %v", ErrPermanent, err)
+	}
+
+	code := response.Error.Code
+	switch {
+	case whatsAppInvalidRecipient[code]:
+		return fmt.Errorf("%w: whatsapp error %d: %s", ErrInvalidRecipient, code, response.Error.Message)
+	case whatsAppPermanent[code]:
+		return fmt.Errorf("%w: whatsapp error %d: %s", ErrPermanent, code, response.Error.Message)
+	default:
+		return fmt.Errorf("whatsapp error %d: %s: %w", code, response.Error.Message, err)
+	}
+}
+
 // send to wa
 // function to send notification to whatsapp
 func SendWhatsAppNotification(phoneNumber, message string) error {
-	// Implement the logic to send a notification to WhatsApp
-	// This is a placeholder implementation
-	return nil
+	notifier, ok := Get("wa")
+	if !ok {
+		return fmt.Errorf("%w: wa", ErrUnknownChannel)
+	}
+	return notifier.Send(phoneNumber, Message{Text: message})
+}
+
+// epicenterName labels the location pin in the language of the alert
+func (n *WhatsAppNotifier) epicenterName(language string) string {
+	name, err := messages.Render(messages.KeyBotEpicenter, messages.Selector{Channel: n.Name(), Language: language}, nil)
+	if err != nil {
+		log.Printf("Failed to render whatsapp location name: %v", err)
+	}
+	return name
 }
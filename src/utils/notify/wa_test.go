@@ -0,0 +1,58 @@
+package notify
+
+import (
+	"bmkg/src/utils"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+)
+
+func TestClassifyWhatsAppError(t *testing.T) {
+	cloudAPIError := func(status int, code int) error {
+		return &utils.HTTPError{
+			StatusCode: status,
+			Body:       []byte(fmt.Sprintf(`{"error":{"message":"test","code":%d}}`, code)),
+		}
+	}
+
+	tests := []struct {
+		name             string
+		err              error
+		invalidRecipient bool
+		permanent        bool
+	}{
+		// nomornya sendiri yang salah
+		{"message undeliverable", cloudAPIError(400, 131026), true, true},
+		{"recipient cannot be sender", cloudAPIError(400, 131021), true, true},
+		{"recipient not in allowed list", cloudAPIError(400, 131030), true, true},
+		{"phone number not registered", cloudAPIError(400, 133010), true, true},
+		// permanen tapi bukan karena nomornya
+		{"invalid parameter", cloudAPIError(400, 100), false, true},
+		{"outside the 24 hour window", cloudAPIError(400, 131047), false, true},
+		{"template does not exist", cloudAPIError(404, 132001), false, true},
+		// bisa dicoba lagi
+		{"rate limit", cloudAPIError(429, 130429), false, false},
+		{"spam rate limit", cloudAPIError(400, 131056), false, false},
+		{"service unavailable", cloudAPIError(503, 131000), false, false},
+		{"5xx without error body", &utils.HTTPError{StatusCode: 502, Body: []byte("bad gateway")}, false, false},
+		{"429 without error body", &utils.HTTPError{StatusCode: 429}, false, false},
+		{"4xx without error body", &utils.HTTPError{StatusCode: 401, Body: []byte("{}")}, false, true},
+		{"network error", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := classifyWhatsAppError(tt.err)
+			if err == nil {
+				t.Fatal("classifyWhatsAppError returned nil")
+			}
+			if got := errors.Is(err, ErrInvalidRecipient); got != tt.invalidRecipient {
+				t.Errorf("errors.Is(%v, ErrInvalidRecipient) = %v, want %v", err, got, tt.invalidRecipient)
+			}
+			if got := errors.Is(err, ErrPermanent); got != tt.permanent {
+				t.Errorf("errors.Is(%v, ErrPermanent) = %v, want %v", err, got, tt.permanent)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package notify
+
+import (
+	"bmkg/src/utils"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// webhookTimeout bounds a single POST to a partner endpoint
+	webhookTimeout = 10 * time.Second
+	// webhookMaxFailures is the number of consecutive failed attempts after which a
+	// subscription is disabled. With the outbox backoff this is a few hours of downtime.
+	webhookMaxFailures = 20
+)
+
+// WebhookStore gives the notifier the subscriptions and keeps their delivery health
+type WebhookStore interface {
+	Target(id string) (url, secret string, active bool, err error)
+	RecordSuccess(id string) error
+	RecordFailure(id string) (int, error)
+	Disable(id, reason string) error
+}
+
+// WebhookNotifier POSTs events to the endpoints of partner systems. Recipient is the id of
+// the webhook_subscription and the body is the Payload of the message.
+//
+// Every request is signed: X-Webhook-Signature is "sha256=" followed by the hex HMAC-SHA256
+// of "<X-Webhook-Timestamp>.<body>" with the subscription secret. Receivers should reject
+// timestamps older than a few minutes to prevent replays by third parties.
+type WebhookNotifier struct {
+	store  WebhookStore
+	client *utils.HTTPClient
+}
+
+// NewWebhookNotifier creates the notifier for the "webhook" channel
+func NewWebhookNotifier(store WebhookStore, client *utils.HTTPClient) *WebhookNotifier {
+	return &WebhookNotifier{
+		store:  store,
+		client: client,
+	}
+}
+
+func (n *WebhookNotifier) Name() string { return "webhook" }
+
+func (n *WebhookNotifier) Capabilities() Capabilities {
+	return Capabilities{}
+}
+
+func (n *WebhookNotifier) Send(recipient string, message Message) error {
+	url, secret, active, err := n.store.Target(recipient)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidRecipient, err)
+	}
+	if !active {
+		return fmt.Errorf("webhook subscription %s is disabled: %w", recipient, ErrPermanent)
+	}
+	if len(message.Payload) == 0 {
+		return fmt.Errorf("webhook message without payload: %w", ErrPermanent)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	headers := map[string]string{
+		"Content-Type":        "application/json",
+		"User-Agent":          "bmkg-iot-webhook/1",
+		"X-Webhook-Timestamp": timestamp,
+		"X-Webhook-Signature": SignWebhook(secret, timestamp, message.Payload),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), webhookTimeout)
+	defer cancel()
+
+	if _, err := n.client.PostWithContext(ctx, url, headers, message.Payload); err != nil {
+		return n.failed(recipient, err)
+	}
+
+	if err := n.store.RecordSuccess(recipient); err != nil {
+		log.Printf("Error saving webhook success of %s: %v", recipient, err)
+	}
+	return nil
+}
+
+// failed records the failure and disables the subscription when the endpoint is gone
+// or kept failing. Failures stay retryable, a partner outage is usually temporary.
+func (n *WebhookNotifier) failed(id string, err error) error {
+	failures, recordErr := n.store.RecordFailure(id)
+	if recordErr != nil {
+		log.Printf("Error saving webhook failure of %s: %v", id, recordErr)
+	}
+
+	var httpErr *utils.HTTPError
+	gone := errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusGone
+
+	if gone || failures >= webhookMaxFailures {
+		reason := fmt.Sprintf("disabled after %d consecutive failures: %v", failures, err)
+		if gone {
+			reason = "endpoint answered 410 Gone"
+		}
+
+		log.Printf("Disabling webhook subscription %s: %s", id, reason)
+		if err := n.store.Disable(id, reason); err != nil {
+			log.Printf("Error disabling webhook subscription %s: %v", id, err)
+		}
+		return fmt.Errorf("%w: %s", ErrPermanent, reason)
+	}
+
+	return fmt.Errorf("webhook %s failed: %w", id, err)
+}
+
+// SignWebhook returns the X-Webhook-Signature header value of a body
+func SignWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultTimezone is used for subscribers without a time zone
+const DefaultTimezone = "Asia/Jakarta"
+
+// ErrInvalidClock is returned for a time of day that is not HH:MM
+var ErrInvalidClock = errors.New("invalid time of day, use HH:MM")
+
+// ParseClock parses a time of day "HH:MM" into minutes after midnight
+func ParseClock(value string) (int, error) {
+	parsed, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidClock, value)
+	}
+	return parsed.Hour()*60 + parsed.Minute(), nil
+}
+
+// LoadTimezone loads an IANA time zone such as "Asia/Makassar", empty means DefaultTimezone
+func LoadTimezone(name string) (*time.Location, error) {
+	if name == "" {
+		name = DefaultTimezone
+	}
+
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time zone %q: %w", name, err)
+	}
+	return location, nil
+}
+
+// InQuietHours reports whether now falls in the quiet window from start to end (HH:MM)
+// in the given time zone. The window may wrap around midnight, e.g. 22:00–06:00.
+// An empty or zero-length window is never quiet.
+func InQuietHours(start, end, timezone string, now time.Time) (bool, error) {
+	if start == "" || end == "" {
+		return false, nil
+	}
+
+	from, err := ParseClock(start)
+	if err != nil {
+		return false, err
+	}
+	to, err := ParseClock(end)
+	if err != nil {
+		return false, err
+	}
+
+	location, err := LoadTimezone(timezone)
+	if err != nil {
+		return false, err
+	}
+
+	local := now.In(location)
+	minute := local.Hour()*60 + local.Minute()
+
+	switch {
+	case from == to:
+		return false, nil
+	case from < to:
+		return minute >= from && minute < to, nil
+	default:
+		return minute >= from || minute < to, nil
+	}
+}
@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestInQuietHours(t *testing.T) {
+	// at returns the moment hh:mm on 16 May 2025 in the given UTC offset
+	at := func(hour, minute, offsetHours int) time.Time {
+		return time.Date(2025, time.May, 16, hour, minute, 0, 0, time.FixedZone("", offsetHours*60*60))
+	}
+
+	tests := []struct {
+		name       string
+		start, end string
+		timezone   string
+		now        time.Time
+		want       bool
+	}{
+		// overnight window wrapping around midnight
+		{"overnight before start", "22:00", "06:00", "Asia/Jakarta", at(21, 59, 7), false},
+		{"overnight at start", "22:00", "06:00", "Asia/Jakarta", at(22, 0, 7), true},
+		{"overnight before midnight", "22:00", "06:00", "Asia/Jakarta", at(23, 30, 7), true},
+		{"overnight after midnight", "22:00", "06:00", "Asia/Jakarta", at(3, 0, 7), true},
+		{"overnight at end", "22:00", "06:00", "Asia/Jakarta", at(6, 0, 7), false},
+		{"overnight midday", "22:00", "06:00", "Asia/Jakarta", at(12, 0, 7), false},
+		// same-day window
+		{"daytime inside", "13:00", "15:00", "Asia/Jakarta", at(14, 0, 7), true},
+		{"daytime outside", "13:00", "15:00", "Asia/Jakarta", at(15, 0, 7), false},
+		// start == end and empty windows are never quiet
+		{"start equals end at start", "22:00", "22:00", "Asia/Jakarta", at(22, 0, 7), false},
+		{"start equals end later", "22:00", "22:00", "Asia/Jakarta", at(3, 0, 7), false},
+		{"no start", "", "06:00", "Asia/Jakarta", at(3, 0, 7), false},
+		{"no end", "22:00", "", "Asia/Jakarta", at(23, 0, 7), false},
+		// the window is read in the subscriber's zone, not in WIB or UTC:
+		// 14:30 UTC is 21:30 WIB, 22:30 WITA and 23:30 WIT
+		{"WIB at 14:30 UTC", "22:00", "06:00", "Asia/Jakarta", at(14, 30, 0), false},
+		{"WITA at 14:30 UTC", "22:00", "06:00", "Asia/Makassar", at(14, 30, 0), true},
+		{"WIT at 14:30 UTC", "22:00", "06:00", "Asia/Jayapura", at(14, 30, 0), true},
+		{"WIT morning is past the window", "22:00", "06:00", "Asia/Jayapura", at(6, 30, 9), false},
+		{"WIT morning still quiet in WIB", "22:00", "06:00", "Asia/Jakarta", at(6, 30, 9), true},
+		// empty zone falls back to DefaultTimezone
+		{"default time zone", "22:00", "06:00", "", at(14, 30, 0), false},
+		{"default time zone inside", "22:00", "06:00", "", at(15, 30, 0), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := InQuietHours(tt.start, tt.end, tt.timezone, tt.now)
+			if err != nil {
+				t.Fatalf("InQuietHours(%q, %q, %q) error: %v", tt.start, tt.end, tt.timezone, err)
+			}
+			if got != tt.want {
+				t.Errorf("InQuietHours(%q, %q, %q, %s) = %v, want %v", tt.start, tt.end, tt.timezone, tt.now.UTC().Format(time.Kitchen), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInQuietHoursInvalid(t *testing.T) {
+	now := time.Date(2025, time.May, 16, 16, 0, 0, 0, time.UTC)
+
+	if _, err := InQuietHours("25:00", "06:00", "Asia/Jakarta", now); !errors.Is(err, ErrInvalidClock) {
+		t.Errorf("invalid start error = %v, want ErrInvalidClock", err)
+	}
+	if _, err := InQuietHours("22:00", "6 pagi", "Asia/Jakarta", now); !errors.Is(err, ErrInvalidClock) {
+		t.Errorf("invalid end error = %v, want ErrInvalidClock", err)
+	}
+	if _, err := InQuietHours("22:00", "06:00", "Asia/Bandung", now); err == nil {
+		t.Error("unknown time zone returned no error")
+	}
+}
@@ -0,0 +1,115 @@
+package utils
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// TokenBucket limits an average rate with bursts. Tokens refill at rate per second
+// up to burst, and every Wait takes one token.
+type TokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewTokenBucket creates a full bucket. A rate of 0 or less means unlimited.
+func NewTokenBucket(rate float64, burst int) *TokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &TokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Wait blocks until a token is available or ctx is done
+func (b *TokenBucket) Wait(ctx context.Context) error {
+	if b.rate <= 0 {
+		return nil
+	}
+
+	b.mu.Lock()
+	now := time.Now()
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+
+	// Take the token right away, going negative reserves a future one
+	b.tokens--
+	var delay time.Duration
+	if b.tokens < 0 {
+		delay = time.Duration(-b.tokens / b.rate * float64(time.Second))
+	}
+	b.mu.Unlock()
+
+	return sleepContext(ctx, delay)
+}
+
+// KeyedInterval enforces a minimum interval between two calls with the same key,
+// e.g. one message per second to the same chat.
+type KeyedInterval struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     map[string]time.Time
+	swept    time.Time
+}
+
+// NewKeyedInterval creates a limiter. An interval of 0 or less means unlimited.
+func NewKeyedInterval(interval time.Duration) *KeyedInterval {
+	return &KeyedInterval{
+		interval: interval,
+		next:     make(map[string]time.Time),
+		swept:    time.Now(),
+	}
+}
+
+// Wait blocks until the key may be used again or ctx is done
+func (k *KeyedInterval) Wait(ctx context.Context, key string) error {
+	if k.interval <= 0 {
+		return nil
+	}
+
+	k.mu.Lock()
+	now := time.Now()
+	at := now
+	if next, ok := k.next[key]; ok && next.After(now) {
+		at = next
+	}
+	k.next[key] = at.Add(k.interval)
+
+	// Forget keys that are free again so the map does not grow forever
+	if now.Sub(k.swept) > time.Minute {
+		for stale, next := range k.next {
+			if next.Before(now) {
+				delete(k.next, stale)
+			}
+		}
+		k.swept = now
+	}
+	k.mu.Unlock()
+
+	return sleepContext(ctx, at.Sub(now))
+}
+
+func sleepContext(ctx context.Context, delay time.Duration) error {
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
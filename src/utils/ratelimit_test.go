@@ -0,0 +1,146 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+// waitFor runs wait with a context that gives up after limit and reports how long it took
+func waitFor(t *testing.T, limit time.Duration, wait func(ctx context.Context) error) (time.Duration, error) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), limit)
+	defer cancel()
+
+	start := time.Now()
+	err := wait(ctx)
+	return time.Since(start), err
+}
+
+func TestTokenBucketBurst(t *testing.T) {
+	bucket := NewTokenBucket(1, 3)
+
+	// a full bucket lets burst calls through at once
+	for i := 0; i < 3; i++ {
+		if elapsed, err := waitFor(t, time.Second, bucket.Wait); err != nil || elapsed > 50*time.Millisecond {
+			t.Fatalf("call %d waited %s, error %v, want no wait", i+1, elapsed, err)
+		}
+	}
+
+	// the next one needs a token that refills in about a second
+	if _, err := waitFor(t, 50*time.Millisecond, bucket.Wait); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("call past the burst error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestTokenBucketRefill(t *testing.T) {
+	bucket := NewTokenBucket(20, 2)
+
+	for i := 0; i < 2; i++ {
+		if err := bucket.Wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// empty: the next token comes after 1/rate = 50ms
+	elapsed, err := waitFor(t, time.Second, bucket.Wait)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if elapsed < 40*time.Millisecond || elapsed > 500*time.Millisecond {
+		t.Errorf("call on an empty bucket waited %s, want about 50ms", elapsed)
+	}
+
+	// a long pause refills the bucket, but never beyond burst
+	bucket.mu.Lock()
+	bucket.last = bucket.last.Add(-time.Hour)
+	bucket.mu.Unlock()
+	if err := bucket.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	bucket.mu.Lock()
+	tokens := bucket.tokens
+	bucket.mu.Unlock()
+	if math.Abs(tokens-1) > 0.1 {
+		t.Errorf("tokens after a long pause and one call = %.2f, want 1 (burst 2 minus 1)", tokens)
+	}
+}
+
+func TestTokenBucketUnlimited(t *testing.T) {
+	bucket := NewTokenBucket(0, 1)
+	for i := 0; i < 100; i++ {
+		if elapsed, err := waitFor(t, time.Second, bucket.Wait); err != nil || elapsed > 50*time.Millisecond {
+			t.Fatalf("unlimited call %d waited %s, error %v", i+1, elapsed, err)
+		}
+	}
+}
+
+func TestKeyedIntervalPerKey(t *testing.T) {
+	const interval = 60 * time.Millisecond
+	limiter := NewKeyedInterval(interval)
+
+	wait := func(key string) time.Duration {
+		t.Helper()
+		elapsed, err := waitFor(t, time.Second, func(ctx context.Context) error { return limiter.Wait(ctx, key) })
+		if err != nil {
+			t.Fatal(err)
+		}
+		return elapsed
+	}
+
+	// the first call of every key goes through at once
+	if elapsed := wait("chat-a"); elapsed > 30*time.Millisecond {
+		t.Errorf("first chat-a call waited %s", elapsed)
+	}
+	if elapsed := wait("chat-b"); elapsed > 30*time.Millisecond {
+		t.Errorf("first chat-b call waited %s, other keys must not delay it", elapsed)
+	}
+
+	// the same key waits for the interval
+	if elapsed := wait("chat-a"); elapsed < interval-30*time.Millisecond || elapsed > interval+200*time.Millisecond {
+		t.Errorf("second chat-a call waited %s, want about %s", elapsed, interval)
+	}
+
+	// after the interval the key is free again
+	time.Sleep(interval + 10*time.Millisecond)
+	if elapsed := wait("chat-b"); elapsed > 30*time.Millisecond {
+		t.Errorf("chat-b call after the interval waited %s", elapsed)
+	}
+}
+
+func TestKeyedIntervalCancel(t *testing.T) {
+	limiter := NewKeyedInterval(time.Second)
+
+	if err := limiter.Wait(context.Background(), "chat"); err != nil {
+		t.Fatal(err)
+	}
+	_, err := waitFor(t, 20*time.Millisecond, func(ctx context.Context) error { return limiter.Wait(ctx, "chat") })
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestKeyedIntervalForgetsFreeKeys(t *testing.T) {
+	limiter := NewKeyedInterval(time.Millisecond)
+
+	limiter.mu.Lock()
+	limiter.next["stale"] = time.Now().Add(-time.Hour)
+	limiter.swept = time.Now().Add(-2 * time.Minute)
+	limiter.mu.Unlock()
+
+	if err := limiter.Wait(context.Background(), "chat"); err != nil {
+		t.Fatal(err)
+	}
+
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+	if _, ok := limiter.next["stale"]; ok {
+		t.Error("stale key was not forgotten")
+	}
+	if _, ok := limiter.next["chat"]; !ok {
+		t.Error("the key just used was forgotten")
+	}
+}
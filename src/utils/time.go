@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// WIB is Western Indonesia Time (UTC+7), the zone BMKG publishes Tanggal and Jam in
+var WIB = time.FixedZone("WIB", 7*60*60)
+
+// bmkgMonths maps the Indonesian month abbreviations used by BMKG to the ones time.Parse knows
+var bmkgMonths = strings.NewReplacer(
+	"Mei", "May",
+	"Agu", "Aug",
+	"Agt", "Aug",
+	"Okt", "Oct",
+	"Des", "Dec",
+)
+
+// ParseWIB parses the BMKG Tanggal and Jam pair, e.g. "03 Apr 2025" and "12:12:34 WIB",
+// and returns the moment in UTC
+func ParseWIB(tanggal, jam string) (time.Time, error) {
+	value := bmkgMonths.Replace(strings.TrimSpace(tanggal)) + " " + strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(jam), "WIB"))
+
+	t, err := time.ParseInLocation("02 Jan 2006 15:04:05", value, WIB)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid WIB time %q: %w", value, err)
+	}
+
+	return t.UTC(), nil
+}
+
+// indonesianMonths are the month abbreviations BMKG uses in Tanggal
+var indonesianMonths = [...]string{"Jan", "Feb", "Mar", "Apr", "Mei", "Jun", "Jul", "Agu", "Sep", "Okt", "Nov", "Des"}
+
+// FormatTanggal formats t in WIB the way BMKG formats Tanggal, e.g. "16 Mei 2025"
+func FormatTanggal(t time.Time) string {
+	local := t.In(WIB)
+	return fmt.Sprintf("%02d %s %d", local.Day(), indonesianMonths[local.Month()-1], local.Year())
+}
+
+// FormatJam formats t in WIB the way BMKG formats Jam, e.g. "12:12:34 WIB"
+func FormatJam(t time.Time) string {
+	return t.In(WIB).Format("15:04:05") + " WIB"
+}
@@ -1,6 +1,10 @@
 package utils
 
-import "regexp"
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
 
 // Pre-compiled regex for email validation - only compiled once at package initialization
 var emailRegex = regexp.MustCompile(`^[a-z0-9._%+\-]+@[a-z0-9.\-]+\.[a-z]{2,}$`)
@@ -60,3 +64,48 @@ func IsNumeric(s string) bool {
 	}
 	return true
 }
+
+// ErrInvalidPhone is returned for numbers that cannot be turned into E.164
+var ErrInvalidPhone = errors.New("invalid phone number")
+
+// NormalizePhoneID converts an Indonesian phone number to E.164, for example:
+// "0812-3456-7890" -> "+6281234567890"
+// "62 812 3456 7890" -> "+6281234567890"
+// "812 3456 7890" -> "+6281234567890"
+// Numbers that already start with + are kept in their country.
+func NormalizePhoneID(phone string) (string, error) {
+	var digits strings.Builder
+	international := false
+	for i, char := range strings.TrimSpace(phone) {
+		switch {
+		case char >= '0' && char <= '9':
+			digits.WriteRune(char)
+		case char == '+' && i == 0:
+			international = true
+		case char == ' ' || char == '-' || char == '.' || char == '(' || char == ')':
+			// Separator, skip
+		default:
+			return "", ErrInvalidPhone
+		}
+	}
+
+	number := digits.String()
+	if !international {
+		switch {
+		case strings.HasPrefix(number, "62"):
+		case strings.HasPrefix(number, "0"):
+			number = "62" + number[1:]
+		case strings.HasPrefix(number, "8"):
+			number = "62" + number
+		default:
+			return "", ErrInvalidPhone
+		}
+	}
+
+	// E.164 allows at most 15 digits; Indonesian mobile numbers have 10 to 13 after the 0
+	if len(number) < 10 || len(number) > 15 || number[0] == '0' {
+		return "", ErrInvalidPhone
+	}
+
+	return "+" + number, nil
+}
@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizePhoneID(t *testing.T) {
+	tests := []struct {
+		name  string
+		phone string
+		want  string
+		err   error
+	}{
+		{"local 08xx", "081234567890", "+6281234567890", nil},
+		{"local with dashes", "0812-3456-7890", "+6281234567890", nil},
+		{"without leading zero", "812 3456 7890", "+6281234567890", nil},
+		{"country code 628xx", "6281234567890", "+6281234567890", nil},
+		{"country code with spaces", "62 812 3456 7890", "+6281234567890", nil},
+		{"plus with spaces and dashes", "+62 812-3456-7890", "+6281234567890", nil},
+		{"plus with brackets", " +62 (812) 3456.7890 ", "+6281234567890", nil},
+		{"too short", "0812345", "", ErrInvalidPhone},
+		{"too short with country code", "+62812", "", ErrInvalidPhone},
+		{"too long", "+6281234567890123", "", ErrInvalidPhone},
+		{"empty", "", "", ErrInvalidPhone},
+		{"letters", "0812-3456-abcd", "", ErrInvalidPhone},
+		{"plus in the middle", "62+81234567890", "", ErrInvalidPhone},
+		// nomor luar negeri dengan + tetap di negaranya, tanpa + ditolak
+		{"non-Indonesian with plus", "+1 202-555-0123", "+12025550123", nil},
+		{"non-Indonesian without plus", "1 202 555 0123", "", ErrInvalidPhone},
+		{"non-Indonesian trunk zero", "+0 812 3456 7890", "", ErrInvalidPhone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizePhoneID(tt.phone)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("NormalizePhoneID(%q) error = %v, want %v", tt.phone, err, tt.err)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizePhoneID(%q) = %q, want %q", tt.phone, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,81 +1,361 @@
 package bmkg
 
 import (
-	"bmkg/src/domain"
+	"bmkg/src/db"
 	"bmkg/src/repository"
 	"bmkg/src/utils"
-	"bmkg/src/worker/mqtt"
-	"bmkg/src/worker/telegram"
+	"bmkg/src/worker/outbox"
+	"bmkg/src/worker/safety"
 	"context"
 	"encoding/json"
 	"github.com/pocketbase/pocketbase/core"
 	"log"
+	"sort"
 	"time"
 )
 
-// BMKGWorker handles earthquake data processing from BMKG
+// BMKGWorker handles earthquake data processing from BMKG and the other sources
 type BMKGWorker struct {
-	repo        *repository.BMKG
-	cancelFunc  context.CancelFunc
-	ctx         context.Context
-	app         core.App
-	mqtt        mqtt.MQTTClient
-	bottelegram *telegram.Bot
+	repo         *repository.BMKG
+	state        *repository.State
+	cancelFunc   context.CancelFunc
+	ctx          context.Context
+	app          core.App
+	client       *utils.HTTPClient
+	sources      []Source
+	events       chan Event
+	catalog      *catalog
+	sourceStates map[string]*sourceState
+	recipients   *RecipientIndex
+	outbox       *outbox.Worker
+	checkins     *safety.Worker
+	webhooks     *repository.Webhook
 }
 
 // NewBMKGWorker creates a new instance of BMKGWorker
-func NewBMKGWorker(repo *repository.BMKG, app core.App, mqtt mqtt.MQTTClient, tele *telegram.Bot) *BMKGWorker {
+func NewBMKGWorker(repo *repository.BMKG, state *repository.State, webhooks *repository.Webhook, app core.App, deliveries *outbox.Worker, checkins *safety.Worker) *BMKGWorker {
 	ctx, cancel := context.WithCancel(context.Background())
+
+	sources := defaultSources()
+	sourceStates := make(map[string]*sourceState, len(sources))
+	for _, source := range sources {
+		sourceStates[source.Name()] = &sourceState{}
+	}
+
 	return &BMKGWorker{
-		repo:        repo,
-		ctx:         ctx,
-		cancelFunc:  cancel,
-		app:         app,
-		mqtt:        mqtt,
-		bottelegram: tele,
+		repo:         repo,
+		state:        state,
+		ctx:          ctx,
+		cancelFunc:   cancel,
+		app:          app,
+		client:       utils.NewHTTPClient(),
+		sources:      sources,
+		events:       make(chan Event, eventBuffer),
+		catalog:      &catalog{},
+		sourceStates: sourceStates,
+		recipients:   NewRecipientIndex(app),
+		outbox:       deliveries,
+		checkins:     checkins,
+		webhooks:     webhooks,
 	}
 }
 
-// StartWorker initiates a worker that periodically fetches earthquake data from the BMKG API.
+// StartWorker starts one poller per source and the single event pipeline they all feed into.
 func (w *BMKGWorker) StartWorker() {
-	// Run as a goroutine to prevent blocking
-	go func() {
-		log.Println("Starting BMKG worker...")
-
-		// Use ticker for periodic execution
-		ticker := time.NewTicker(timing * time.Second)
-		defer ticker.Stop()
-
-		// Do initial fetch immediately
-		w.fetchAndProcessData()
-
-		for {
-			select {
-			case <-ticker.C:
-				w.fetchAndProcessData()
-			case <-w.ctx.Done():
-				log.Println("BMKG worker stopped")
-				return
+	log.Println("Starting BMKG worker...")
+
+	// Reload recent earthquakes so reports arriving after a restart still merge
+	w.warmCatalog()
+
+	// Index devices and subscribers by location, record hooks keep it current
+	if err := w.recipients.Load(w.app); err != nil {
+		log.Printf("Error loading recipient index: %v", err)
+	}
+	w.recipients.logSize()
+
+	go w.processEvents()
+
+	for _, source := range w.sources {
+		// Run as a goroutine to prevent blocking
+		go w.pollSource(source)
+	}
+}
+
+// pollSource periodically fetches a single source and pushes its events into the pipeline
+func (w *BMKGWorker) pollSource(source Source) {
+	// Restore the validators and last fingerprint from the previous run
+	if _, err := w.state.Load(stateKey(source), w.sourceStates[source.Name()]); err != nil {
+		log.Printf("Error loading state of the %s source: %v", source.Name(), err)
+	}
+
+	// Use ticker for periodic execution
+	ticker := time.NewTicker(source.Interval())
+	defer ticker.Stop()
+
+	// Do initial fetch immediately
+	w.fetchAndProcessData(source)
+
+	for {
+		select {
+		case <-ticker.C:
+			w.fetchAndProcessData(source)
+		case <-w.ctx.Done():
+			log.Printf("%s poller stopped", source.Name())
+			return
+		}
+	}
+}
+
+// fetchAndProcessData handles the API call and hands new events to the pipeline.
+// Unchanged payloads (304) and events already in the previous payload are dropped
+// right here, a revised older entry of a list has a new fingerprint and goes through.
+func (w *BMKGWorker) fetchAndProcessData(source Source) {
+	state := w.sourceStates[source.Name()]
+
+	result, err := source.Fetch(w.client, state.Validators)
+	if err != nil {
+		log.Printf("Error fetching data from the %s source: %v", source.Name(), err)
+		return
+	}
+	if result.NotModified || len(result.Events) == 0 {
+		return
+	}
+
+	events := result.Events
+
+	// Oldest first, so the pipeline sees events in the order they happened
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].OriginTime.Before(events[j].OriginTime)
+	})
+
+	previous := make(map[string]bool, len(state.Fingerprints))
+	for _, fingerprint := range state.Fingerprints {
+		previous[fingerprint] = true
+	}
+
+	fingerprints := make([]string, 0, len(events))
+	changed := len(events) != len(state.Fingerprints)
+	for _, event := range events {
+		fingerprints = append(fingerprints, event.Fingerprint)
+		if previous[event.Fingerprint] {
+			continue
+		}
+		changed = true
+
+		select {
+		case w.events <- event:
+		case <-w.ctx.Done():
+			return
+		}
+	}
+
+	// BMKG sends no validators, so most polls change nothing and need no write
+	if result.Validators == state.Validators && !changed {
+		return
+	}
+
+	state.Validators = result.Validators
+	state.Fingerprints = fingerprints
+	if err := w.state.Save(stateKey(source), state); err != nil {
+		log.Printf("Error saving state of the %s source: %v", source.Name(), err)
+	}
+}
+
+// processEvents is the single consumer of the pipeline. Because only this goroutine
+// touches the catalog, every physical event is saved and notified exactly once.
+func (w *BMKGWorker) processEvents() {
+	for {
+		select {
+		case event := <-w.events:
+			if err := w.processEvent(event); err != nil {
+				log.Printf("Error processing earthquake data: %v", err)
 			}
+		case <-w.ctx.Done():
+			log.Println("BMKG worker stopped")
+			return
 		}
-	}()
+	}
 }
 
-// fetchAndProcessData handles the API call and data processing
-func (w *BMKGWorker) fetchAndProcessData() {
-	response, err := hitAPI()
+// processEvent either updates a known earthquake or stores it as a new one
+func (w *BMKGWorker) processEvent(event Event) error {
+	now := time.Now()
+	w.catalog.prune(now)
+
+	if tracked := w.catalog.match(event); tracked != nil {
+		return w.updateEvent(tracked, event)
+	}
+
+	// The catalog only covers seenTTL, the stored fingerprint covers everything
+	exists, err := w.repo.ExistsFingerprint(event.Fingerprint)
 	if err != nil {
-		log.Printf("Error fetching data from the BMKG API: %v", err)
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	tracked := &trackedEvent{
+		Event:   event,
+		Sources: []EventSource{newEventSource(event)},
+		SeenAt:  now,
+		Seen:    map[string]bool{event.Fingerprint: true},
+	}
+
+	if tracked.RecordID, err = w.processAndSaveEarthquake(event, tracked.Sources); err != nil {
+		return err
+	}
+	w.catalog.add(tracked)
+
+	if err := w.saveRevision(tracked); err != nil {
+		log.Printf("Error saving revision of earthquake %s: %v", tracked.RecordID, err)
+	}
+
+	log.Printf("Successfully processed and saved earthquake %s from %s", event.Key, event.Feed)
+
+	return nil
+}
+
+// updateEvent handles a report of a known earthquake. A report of an agency that did
+// not contribute yet is merged into the sources list, and BMKG values replace those of
+// a foreign first reporter since BMKG is authoritative for Indonesia. A report of the
+// owning agency is a revision when it carries values never reported for the earthquake,
+// whichever feed brings them first. A list feed still showing an older version never
+// rolls a revision back.
+func (w *BMKGWorker) updateEvent(tracked *trackedEvent, event Event) error {
+	known := tracked.Seen[event.Fingerprint]
+	tracked.Seen[event.Fingerprint] = true
+
+	switch {
+	case !tracked.hasAgency(event.Agency):
+		tracked.Sources = append(tracked.Sources, newEventSource(event))
+		if event.Agency == agencyBMKG && tracked.Agency != agencyBMKG {
+			return w.reviseEvent(tracked, event)
+		}
+
+		log.Printf("Merged %s report into earthquake %s", event.Feed, tracked.RecordID)
+
+		return w.repo.UpdateGempa(tracked.RecordID, map[string]interface{}{
+			"sources": tracked.Sources,
+		})
+	case event.Agency == tracked.Agency && !known:
+		tracked.setSource(event)
+		return w.reviseEvent(tracked, event)
+	default:
+		return nil
+	}
+}
+
+// reviseEvent replaces the stored values of an earthquake, records the new revision
+// and sends corrections to the recipients whose alert tier changed
+func (w *BMKGWorker) reviseEvent(tracked *trackedEvent, event Event) error {
+	previous := tracked.Event
+
+	tracked.Event = event
+	tracked.Revision++
+
+	data := earthquakeData(event)
+	data["sources"] = tracked.Sources
+	data["revision"] = tracked.Revision
+
+	if err := w.repo.UpdateGempa(tracked.RecordID, data); err != nil {
+		return err
+	}
+
+	if err := w.saveRevision(tracked); err != nil {
+		log.Printf("Error saving revision of earthquake %s: %v", tracked.RecordID, err)
+	}
+
+	log.Printf("Earthquake %s revised by %s: M%.1f -> M%.1f", tracked.RecordID, event.Feed, previous.Magnitude, event.Magnitude)
+
+	if time.Since(event.OriginTime) <= notifyWindow*time.Minute {
+		// tracked keeps changing with the next reports, the goroutine only gets copies
+		ref := EarthquakeRef{ID: tracked.RecordID, Revision: tracked.Revision}
+		go func(ref EarthquakeRef, event, previous Event) {
+			err := CalculateAndNotify(w.recipients, w.outbox, w.checkins, ref, event, &previous)
+			if err != nil {
+				log.Printf("Error sending earthquake correction: %v", err)
+			}
+			w.notifyWebhooks(ref, event, &previous)
+		}(ref, event, previous)
+	}
+
+	return nil
+}
+
+// saveRevision appends the current values of the earthquake to its revision history
+func (w *BMKGWorker) saveRevision(tracked *trackedEvent) error {
+	return w.repo.SaveRevision(map[string]interface{}{
+		"earthquake":  tracked.RecordID,
+		"revision":    tracked.Revision,
+		"source":      tracked.Feed,
+		"magnitude":   tracked.Magnitude,
+		"depth_km":    tracked.DepthKm,
+		"lat":         tracked.Lat,
+		"lon":         tracked.Lon,
+		"origin_time": tracked.OriginTime,
+		"fingerprint": tracked.Fingerprint,
+	})
+}
+
+// warmCatalog loads the earthquakes stored during the last seenTTL into the catalog
+func (w *BMKGWorker) warmCatalog() {
+	records, err := w.repo.GetRecentRecords(time.Now().Add(-seenTTL * time.Hour))
+	if err != nil {
+		log.Printf("Error loading recent earthquakes: %v", err)
 		return
 	}
 
-	// Process and save the response
-	err = w.processAndSaveEarthquake(response)
+	for _, record := range records {
+		var earthquake db.Earthquake
+		earthquake.SetProxyRecord(record)
+
+		// Rows the typed backfill could not parse have no epicenter to match against
+		if earthquake.OriginTime().IsZero() {
+			continue
+		}
+		event := eventFromRecord(&earthquake)
+
+		var sources []EventSource
+		if raw := earthquake.Sources(); len(raw) > 0 {
+			if err := json.Unmarshal(raw, &sources); err != nil {
+				log.Printf("Error decoding sources of earthquake %s: %v", record.Id, err)
+			}
+		}
+		if len(sources) > 0 {
+			event.Feed = sources[0].Source
+			event.Agency = sources[0].Agency
+		} else {
+			// Stored before sources were tracked, those always came from BMKG
+			sources = []EventSource{newEventSource(event)}
+		}
+
+		w.catalog.add(&trackedEvent{
+			Event:    event,
+			RecordID: record.Id,
+			Sources:  sources,
+			SeenAt:   record.GetDateTime("created").Time(),
+			Revision: earthquake.Revision(),
+			Seen:     w.seenFingerprints(record.Id, event.Fingerprint),
+		})
+	}
+}
+
+// seenFingerprints collects the versions stored in the revision history of an earthquake,
+// so a revision published while the service was down is still recognized after a restart
+func (w *BMKGWorker) seenFingerprints(recordID, current string) map[string]bool {
+	seen := map[string]bool{current: true}
+
+	revisions, err := w.repo.GetRevisions(recordID)
 	if err != nil {
-		log.Printf("Error processing earthquake data: %v", err)
-	} else {
-		log.Println("Successfully processed and saved earthquake data")
+		log.Printf("Error loading revisions of earthquake %s: %v", recordID, err)
+		return seen
 	}
+	for _, revision := range revisions {
+		seen[revision.Fingerprint()] = true
+	}
+
+	return seen
 }
 
 // StopWorker gracefully stops the BMKG worker
@@ -86,55 +366,29 @@ func (w *BMKGWorker) StopWorker() {
 	}
 }
 
-// processAndSaveEarthquake converts BMKG API response to a map and saves it to the database
-func (w *BMKGWorker) processAndSaveEarthquake(response domain.ResponseBmkgAPI) error {
-	// Convert struct to map for flexible storage
-	gempaData := utils.StructToMap(response.Infogempa.Gempa)
-
-	// Add timestamp for when this data was fetched
-	gempaData["fetchedAt"] = time.Now().Format(time.RFC3339)
-
-	// jalankan go routine untuk menghitung lokasi device
-	go func() {
-		err := CalculateAndNotify(w.app, w.mqtt, response, w.bottelegram)
-		if err != nil {
-			log.Printf("Error calculating and notifying device location: %v", err)
-			return
-		}
-	}()
+// processAndSaveEarthquake saves a new event to the database and notifies recipients
+func (w *BMKGWorker) processAndSaveEarthquake(event Event, sources []EventSource) (string, error) {
+	gempaData := earthquakeData(event)
+	gempaData["sources"] = sources
 
 	// Save to repository
-	return w.repo.SaveGempa(gempaData)
-}
-
-// hitAPI fetches the earthquake data from the BMKG API.
-//
-// This function makes an HTTP GET request to the BMKG API endpoint.
-// It then parses the JSON response into a domain.ResponseBmkgAPI struct.
-//
-// Returns:
-//   - domain.ResponseBmkgAPI: A struct containing the parsed response from the BMKG API.
-//   - error: An error if the request fails or if the response cannot be parsed.
-//
-// Example:
-//
-//	response, err := hitAPI()
-//	if err != nil {
-//	    log.Fatalf("Failed to fetch earthquake data: %v", err)
-//	}
-//	fmt.Printf("Latest earthquake: %+v\n", response.Infogempa.Gempa)
-func hitAPI() (domain.ResponseBmkgAPI, error) {
-	// hit api bmkg
-	respons, err := utils.NewHTTPClient().Get(bmkgAPIURL)
-
+	id, err := w.repo.SaveGempa(gempaData)
 	if err != nil {
-		return domain.ResponseBmkgAPI{}, err
+		return "", err
 	}
 
-	// decode response
-	var response domain.ResponseBmkgAPI
-	if err := json.Unmarshal(respons, &response); err != nil {
-		return domain.ResponseBmkgAPI{}, err
+	// Old entries of the list feeds are stored but must not alert anyone
+	if time.Since(event.OriginTime) <= notifyWindow*time.Minute {
+		// jalankan go routine untuk menghitung lokasi device
+		go func() {
+			ref := EarthquakeRef{ID: id}
+			err := CalculateAndNotify(w.recipients, w.outbox, w.checkins, ref, event, nil)
+			if err != nil {
+				log.Printf("Error calculating and notifying device location: %v", err)
+			}
+			w.notifyWebhooks(ref, event, nil)
+		}()
 	}
-	return response, nil
+
+	return id, nil
 }
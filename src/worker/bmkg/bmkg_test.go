@@ -0,0 +1,248 @@
+package bmkg
+
+import (
+	"testing"
+	"time"
+
+	_ "bmkg/migrations"
+	"bmkg/src/db"
+	"bmkg/src/domain"
+	"bmkg/src/repository"
+	"bmkg/src/utils"
+	"bmkg/src/worker/outbox"
+	"bmkg/src/worker/safety"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// newTestApp returns a migrated scratch PocketBase app
+func newTestApp(t *testing.T) core.App {
+	t.Helper()
+
+	app := pocketbase.NewWithConfig(pocketbase.Config{DefaultDataDir: t.TempDir()})
+	if err := app.Bootstrap(); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.RunAllMigrations(); err != nil {
+		t.Fatal(err)
+	}
+	return app
+}
+
+// newTestWorker builds a worker on app the way main does, without starting the pollers
+func newTestWorker(t *testing.T, app core.App) *BMKGWorker {
+	t.Helper()
+
+	deliveries := outbox.NewWorker(repository.NewOutboxRepository(app))
+	checkins := safety.NewWorker(app, repository.NewSafetyRepository(app), repository.NewBMKGRepository(app), repository.NewSubscriberRepository(app), deliveries)
+	w := NewBMKGWorker(repository.NewBMKGRepository(app), repository.NewStateRepository(app), repository.NewWebhookRepository(app), app, deliveries, checkins)
+	t.Cleanup(w.StopWorker)
+
+	if err := w.recipients.Load(app); err != nil {
+		t.Fatal(err)
+	}
+	return w
+}
+
+// testEvent is a BMKG report of the same quake with the given magnitude
+func testEvent(t *testing.T, feed, magnitude string, origin time.Time) Event {
+	t.Helper()
+
+	event, err := newEventFromGempa(feed, domain.Gempa{
+		Tanggal:     "17 Okt 2026",
+		Jam:         "10:00:00 WIB",
+		DateTime:    origin,
+		Coordinates: "-6.90,106.90",
+		Lintang:     "6.90 LS",
+		Bujur:       "106.90 BT",
+		Magnitude:   magnitude,
+		Kedalaman:   "10 km",
+		Wilayah:     "Pusat gempa berada di darat 10 km Tenggara Sukabumi",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return event
+}
+
+// storedRevision returns the revision of the only stored earthquake
+func storedRevision(t *testing.T, app core.App) (string, int) {
+	t.Helper()
+
+	records, err := app.FindAllRecords("earthquake")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("got %d earthquakes, want 1", len(records))
+	}
+
+	var earthquake db.Earthquake
+	earthquake.SetProxyRecord(records[0])
+	return earthquake.Id, earthquake.Revision()
+}
+
+// waitDeliveries waits for the notifying goroutine to plan want deliveries about the earthquake
+func waitDeliveries(t *testing.T, app core.App, earthquakeID string, want int) {
+	t.Helper()
+
+	var count int64
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(50 * time.Millisecond) {
+		var err error
+		count, err = app.CountRecords("notification_outbox", dbx.HashExp{"earthquake": earthquakeID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if int(count) >= want {
+			return
+		}
+	}
+	t.Fatalf("got %d deliveries, want %d", count, want)
+}
+
+func TestRevisionAfterRestart(t *testing.T) {
+	app := newTestApp(t)
+	origin := time.Now().Add(-5 * time.Minute).UTC().Truncate(time.Second)
+
+	// a subscriber right at the epicenter
+	subscribers := repository.NewSubscriberRepository(app)
+	subscriber, err := subscribers.Ensure("123", db.Telegram)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := subscribers.SaveLocation(subscriber, "Rumah", -6.9, 106.9); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := newTestWorker(t, app).processEvent(testEvent(t, "autogempa", "3.0", origin)); err != nil {
+		t.Fatal(err)
+	}
+	id, _ := storedRevision(t, app)
+	waitDeliveries(t, app, id, 1)
+
+	// BMKG revised the quake while the service was restarting
+	restarted := newTestWorker(t, app)
+	restarted.warmCatalog()
+
+	// a list feed repeating the stored version is not a revision
+	if err := restarted.processEvent(testEvent(t, "gempaterkini", "3.0", origin)); err != nil {
+		t.Fatal(err)
+	}
+	if _, revision := storedRevision(t, app); revision != 0 {
+		t.Fatalf("repeated report stored as revision %d", revision)
+	}
+
+	if err := restarted.processEvent(testEvent(t, "autogempa", "6.0", origin)); err != nil {
+		t.Fatal(err)
+	}
+	if _, revision := storedRevision(t, app); revision != 1 {
+		t.Fatalf("revision after restart = %d, want 1", revision)
+	}
+	waitDeliveries(t, app, id, 2)
+
+	// after another restart the old version of a lagging list feed does not roll back
+	restarted = newTestWorker(t, app)
+	restarted.warmCatalog()
+	if err := restarted.processEvent(testEvent(t, "gempadirasakan", "3.0", origin)); err != nil {
+		t.Fatal(err)
+	}
+	if _, revision := storedRevision(t, app); revision != 1 {
+		t.Errorf("stale report rolled the earthquake back to revision %d", revision)
+	}
+}
+
+func TestRevisionFirstSeenOnAnotherFeed(t *testing.T) {
+	app := newTestApp(t)
+	origin := time.Now().Add(-5 * time.Minute).UTC().Truncate(time.Second)
+	w := newTestWorker(t, app)
+
+	if err := w.processEvent(testEvent(t, "autogempa", "5.0", origin)); err != nil {
+		t.Fatal(err)
+	}
+	// the list feed is the first to publish the revised magnitude
+	if err := w.processEvent(testEvent(t, "gempaterkini", "5.4", origin)); err != nil {
+		t.Fatal(err)
+	}
+	if _, revision := storedRevision(t, app); revision != 1 {
+		t.Fatalf("revision = %d, want 1", revision)
+	}
+
+	// autogempa catching up with the same values is no new revision
+	if err := w.processEvent(testEvent(t, "autogempa", "5.4", origin)); err != nil {
+		t.Fatal(err)
+	}
+	if _, revision := storedRevision(t, app); revision != 1 {
+		t.Errorf("catch-up report stored as revision %d", revision)
+	}
+}
+
+// fakeSource serves a fixed list of events
+type fakeSource struct {
+	events []Event
+}
+
+func (s *fakeSource) Name() string            { return "gempaterkini" }
+func (s *fakeSource) Agency() string          { return agencyBMKG }
+func (s *fakeSource) Interval() time.Duration { return time.Minute }
+func (s *fakeSource) Fetch(*utils.HTTPClient, utils.Validators) (FetchResult, error) {
+	return FetchResult{Events: append([]Event(nil), s.events...)}, nil
+}
+
+// forwarded drains the magnitudes of the events handed to the pipeline
+func forwarded(w *BMKGWorker) []float64 {
+	var magnitudes []float64
+	for {
+		select {
+		case event := <-w.events:
+			magnitudes = append(magnitudes, event.Magnitude)
+		default:
+			return magnitudes
+		}
+	}
+}
+
+func TestFetchForwardsRevisedOlderEntry(t *testing.T) {
+	app := newTestApp(t)
+	w := newTestWorker(t, app)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	source := &fakeSource{events: []Event{
+		testEvent(t, "gempaterkini", "5.0", now.Add(-2*time.Hour)),
+		testEvent(t, "gempaterkini", "5.5", now.Add(-time.Hour)),
+	}}
+	w.sourceStates[source.Name()] = &sourceState{}
+
+	w.fetchAndProcessData(source)
+	if got := forwarded(w); len(got) != 2 {
+		t.Fatalf("first poll forwarded %v, want both events", got)
+	}
+
+	w.fetchAndProcessData(source)
+	if got := forwarded(w); len(got) != 0 {
+		t.Fatalf("unchanged poll forwarded %v", got)
+	}
+
+	// BMKG revises the older entry, the newest one stays the same
+	source.events[0] = testEvent(t, "gempaterkini", "5.2", now.Add(-2*time.Hour))
+	w.fetchAndProcessData(source)
+	if got := forwarded(w); len(got) != 1 || got[0] != 5.2 {
+		t.Fatalf("revised older entry: forwarded %v, want [5.2]", got)
+	}
+
+	// a restart remembers the whole payload
+	var state sourceState
+	if _, err := w.state.Load(stateKey(source), &state); err != nil {
+		t.Fatal(err)
+	}
+	if len(state.Fingerprints) != 2 {
+		t.Errorf("stored %d fingerprints, want 2", len(state.Fingerprints))
+	}
+	restarted := newTestWorker(t, app)
+	restarted.sourceStates[source.Name()] = &state
+	restarted.fetchAndProcessData(source)
+	if got := forwarded(restarted); len(got) != 0 {
+		t.Errorf("poll after restart forwarded %v", got)
+	}
+}
@@ -2,171 +2,267 @@ package bmkg
 
 import (
 	"bmkg/src/db"
-	"bmkg/src/domain"
-	"bmkg/src/utils"
+	"bmkg/src/messages"
 	"bmkg/src/utils/ngitung"
 	"bmkg/src/utils/notify"
-	"bmkg/src/worker/mqtt"
-	"bmkg/src/worker/telegram"
+	"bmkg/src/worker/outbox"
+	"bmkg/src/worker/safety"
 	"fmt"
-	"github.com/pocketbase/pocketbase/core"
-	"strconv"
+	"log"
+	"math"
+	"sort"
+	"time"
 )
 
-// CalculateAndNotify processes earthquake data and sends notifications to affected users and devices
-func CalculateAndNotify(app core.App, mqtt mqtt.MQTTClient, response domain.ResponseBmkgAPI, telegrambot *telegram.Bot) error {
-	// Extract earthquake data
-	magnitude, err := strconv.ParseFloat(response.Infogempa.Gempa.Magnitude, 64)
-	if err != nil {
-		return fmt.Errorf("failed to parse magnitude: %w", err)
-	}
-
-	earthquakeLat, err := strconv.ParseFloat(utils.ExtractNumber(response.Infogempa.Gempa.Lintang), 64)
-	if err != nil {
-		return fmt.Errorf("failed to parse earthquake latitude: %w", err)
-	}
-
-	earthquakeLon, err := strconv.ParseFloat(utils.ExtractNumber(response.Infogempa.Gempa.Bujur), 64)
-	if err != nil {
-		return fmt.Errorf("failed to parse earthquake longitude: %w", err)
-	}
-
-	earthquakeLocation := ngitung.Location{
-		Lat: earthquakeLat,
-		Lon: earthquakeLon,
-	}
-
-	// Build notification message with earthquake details
-	notificationMsg := buildEarthquakeNotificationMessage(response)
-
+// CalculateAndNotify processes earthquake data and sends notifications to affected users and devices.
+// Each recipient is alerted according to the tier of their estimated MMI. When previous is set the
+// event is a revision of previous: only recipients whose alert tier changed are notified, with a
+// correction message.
+// Deliveries are written to the outbox, which sends them over each recipient's channel.
+// Group members shaken at safety.CheckinMMI or more are also asked to check in.
+func CalculateAndNotify(recipients *RecipientIndex, deliveries *outbox.Worker, checkins *safety.Worker, earthquake EarthquakeRef, event Event, previous *Event) error {
 	// Notify IoT devices in affected areas
-	if err := notifyAffectedDevices(app, mqtt, earthquakeLocation, magnitude, notificationMsg); err != nil {
+	if err := notifyAffectedDevices(recipients, deliveries, earthquake, event, previous); err != nil {
 		return fmt.Errorf("error notifying devices: %w", err)
 	}
 
 	// Notify users in affected areas
-	if err := notifyAffectedUsers(app, earthquakeLocation, magnitude, notificationMsg, telegrambot); err != nil {
+	if err := notifyAffectedUsers(recipients, deliveries, checkins, earthquake, event, previous); err != nil {
 		return fmt.Errorf("error notifying users: %w", err)
 	}
 
 	return nil
 }
 
-// NotifyAffectedDevices sends notifications to IoT devices that would feel the earthquake
-func notifyAffectedDevices(app core.App, mqtt mqtt.MQTTClient, earthquakeLocation ngitung.Location, magnitude float64, message string) error {
-	// Get all IoT devices
-	iotDevices, err := app.FindAllRecords("iot_device")
-	if err != nil {
-		return fmt.Errorf("failed to retrieve IoT devices: %w", err)
-	}
+// EarthquakeRef identifies the stored earthquake an alert is about
+type EarthquakeRef struct {
+	ID       string
+	Revision int
+}
 
-	// Loop through each device and check if it would feel the earthquake
-	for _, device := range iotDevices {
-		var deviceInfo db.IotDevice
-		deviceInfo.SetProxyRecord(device)
+// recipientAlert is the estimated shaking at one recipient and the tier it falls into
+type recipientAlert struct {
+	Tier     *AlertTier
+	MMI      float64
+	Distance float64
+	// PreviousMMI and PreviousDistance are the estimate of the previous revision, 0 for a first report
+	PreviousMMI      float64
+	PreviousDistance float64
+}
 
-		// Get device location
-		deviceLat, err := strconv.ParseFloat(deviceInfo.Lintang(), 64)
-		if err != nil {
-			continue // Skip devices with invalid latitude
-		}
+// assessRecipient decides whether a recipient at target is notified about event. A first
+// report notifies everyone within a tier, a revision only those whose tier changed. A
+// recipient downgraded below every tier still gets the correction through the lowest tier.
+func assessRecipient(event Event, previous *Event, target ngitung.Location) (recipientAlert, bool) {
+	_, distance, mmi := ngitung.IsWithinFeltRadius(ngitung.Location{Lat: event.Lat, Lon: event.Lon}, target, event.Magnitude, event.DepthKm)
+	alert := recipientAlert{Tier: tierFor(mmi), MMI: mmi, Distance: distance}
+	if previous == nil {
+		return alert, alert.Tier != nil
+	}
 
-		deviceLon, err := strconv.ParseFloat(deviceInfo.Bujur(), 64)
-		if err != nil {
-			continue // Skip devices with invalid longitude
-		}
+	_, previousDistance, previousMMI := ngitung.IsWithinFeltRadius(ngitung.Location{Lat: previous.Lat, Lon: previous.Lon}, target, previous.Magnitude, previous.DepthKm)
+	alert.PreviousMMI, alert.PreviousDistance = previousMMI, previousDistance
+	previousTier := tierFor(previousMMI)
+	if alert.Tier == previousTier {
+		return alert, false
+	}
+	if alert.Tier == nil {
+		alert.Tier = &tiers[0]
+	}
+	return alert, true
+}
 
-		deviceLocation := ngitung.Location{
-			Lat: deviceLat,
-			Lon: deviceLon,
-		}
+// NotifyAffectedDevices sends notifications to IoT devices that would feel the earthquake
+func notifyAffectedDevices(recipients *RecipientIndex, deliveries *outbox.Worker, earthquake EarthquakeRef, event Event, previous *Event) error {
+	// Only devices inside the maximum felt radius are evaluated
+	for _, device := range recipients.candidates(recipientDevices, event, previous) {
+		var deviceInfo db.IotDevice
+		deviceInfo.SetProxyRecord(device.Record)
 
 		// Check if the device would feel the earthquake
-		isFelt, _, _ := ngitung.IsWithinFeltRadius(earthquakeLocation, deviceLocation, magnitude)
-		if !isFelt {
+		alert, ok := assessRecipient(event, previous, device.Location)
+		if !ok || !alert.Tier.HasChannel(channelMQTT) {
 			continue
 		}
 
-		// Send notification to device
-		if err := notify.SendMQTTNotification(mqtt, "device/"+deviceInfo.Id, message); err != nil {
+		message, err := notify.NewMQTTMessage(buildMQTTAlert(earthquake, event, previous, alert))
+		if err != nil {
+			return err
+		}
+
+		// Plan notification to device
+		if err := enqueueAlert(deliveries, earthquake, event, alert, channelMQTT, "device/"+deviceInfo.Id, message); err != nil {
 			// Log error but continue processing other devices
-			fmt.Printf("Failed to send notification to device %s: %v\n", deviceInfo.Id, err)
+			log.Printf("Failed to plan notification to device %s: %v", deviceInfo.Id, err)
 		}
 	}
 
 	return nil
 }
 
-// NotifyAffectedUsers sends notifications to users who would feel the earthquake
-// NotifyAffectedUsers sends notifications to users who would feel the earthquake
-func notifyAffectedUsers(app core.App, earthquakeLocation ngitung.Location, magnitude float64, message string, telegrambot *telegram.Bot) error {
-	// Get all users with notification preferences
-	usersToNotify, err := app.FindAllRecords("user_notify")
-	if err != nil {
-		return fmt.Errorf("failed to retrieve users: %w", err)
+// NotifyAffectedUsers sends notifications to users who would feel the earthquake at one of
+// their saved locations. A subscriber gets one alert for the most affected place, listing
+// every affected place.
+func notifyAffectedUsers(recipients *RecipientIndex, deliveries *outbox.Worker, checkins *safety.Worker, earthquake EarthquakeRef, event Event, previous *Event) error {
+	// Only locations inside the maximum felt radius are evaluated, grouped per subscriber
+	places := make(map[string][]placeAlert)
+	var subscribers []string
+	for _, location := range recipients.candidates(recipientLocations, event, previous) {
+		// Check if the place would feel the earthquake
+		alert, ok := assessRecipient(event, previous, location.Location)
+		if !ok {
+			continue
+		}
+
+		id := location.Record.GetString("subscriber")
+		if _, seen := places[id]; !seen {
+			subscribers = append(subscribers, id)
+		}
+		places[id] = append(places[id], placeAlert{Label: location.Record.GetString("label"), Alert: alert})
 	}
 
-	// Loop through each user and send notification if they would feel the earthquake
-	for _, user := range usersToNotify {
+	for _, id := range subscribers {
+		record := recipients.Subscriber(id)
+		if record == nil {
+			continue
+		}
+
 		var userInfo db.UserNotify
-		userInfo.SetProxyRecord(user)
+		userInfo.SetProxyRecord(record)
 
-		// Get user location
-		userLat, err := strconv.ParseFloat(userInfo.Lintang(), 64)
-		if err != nil {
-			continue // Skip users with invalid latitude
+		affected := places[id]
+		sort.SliceStable(affected, func(i, j int) bool { return affected[i].Alert.MMI > affected[j].Alert.MMI })
+		alert := affected[0].Alert
+
+		channel := record.GetString("type")
+		if !alert.Tier.HasChannel(channel) || !wantsAlert(&userInfo, event, previous, alert, time.Now()) {
+			continue
 		}
 
-		userLon, err := strconv.ParseFloat(userInfo.Bujur(), 64)
+		// Wording in the subscriber's language, picked per channel and tier
+		selector := messages.Selector{Channel: channel, Tier: alert.Tier.Name, Language: record.GetString("language")}
+		text, err := buildAlertMessage(event, previous, alert, affected, selector)
 		if err != nil {
-			continue // Skip users with invalid longitude
+			return err
 		}
 
-		userLocation := ngitung.Location{
-			Lat: userLat,
-			Lon: userLon,
+		// Plan notification over the user's preferred channel, with a pin on the epicenter
+		message := notify.Message{
+			Text:         text,
+			HasLocation:  true,
+			Lat:          event.Lat,
+			Lon:          event.Lon,
+			Priority:     alert.Tier.Priority,
+			EarthquakeID: earthquake.ID,
+			Language:     selector.Language,
 		}
-
-		// Check if the user would feel the earthquake
-		isFelt, _, _ := ngitung.IsWithinFeltRadius(earthquakeLocation, userLocation, magnitude)
-		if !isFelt {
-			continue
+		if event.Gempa.Shakemap != "" {
+			message.ImageURL = shakemapBaseURL + event.Gempa.Shakemap
+		}
+		if err := enqueueAlert(deliveries, earthquake, event, alert, channel, userInfo.Identifier(), message); err != nil {
+			// Log error but continue processing other users
+			log.Printf("Failed to plan notification to user %s: %v", userInfo.Id, err)
 		}
 
-		// Send notification based on user preference
-		if err := sendNotificationByType(userInfo, message, *telegrambot); err != nil {
-			return fmt.Errorf("failed to send notification to user: %w", err)
+		// Ask members of a safety group whether they are safe
+		if checkins != nil && alert.MMI >= safety.CheckinMMI() {
+			if err := checkins.Open(earthquake.ID, &userInfo, alert.MMI); err != nil {
+				log.Printf("Failed to open check-in of user %s: %v", userInfo.Id, err)
+			}
 		}
 	}
 
 	return nil
 }
 
-// BuildEarthquakeNotificationMessage creates a formatted notification message with earthquake details
-func buildEarthquakeNotificationMessage(response domain.ResponseBmkgAPI) string {
-	gempa := response.Infogempa.Gempa
-	return "Earthquake Alert! Magnitude: " + gempa.Magnitude +
-		"\nLocation: " + gempa.Lintang + ", " + gempa.Bujur +
-		"\nDepth: " + gempa.Kedalaman +
-		"\nTime: " + gempa.Tanggal + " " + gempa.Jam +
-		"\nRegion: " + gempa.Wilayah
+// placeAlert is the alert for one saved location of a subscriber
+type placeAlert struct {
+	Label string
+	Alert recipientAlert
 }
 
-// SendNotificationByType sends a notification using the user's preferred notification method
-// SendNotificationByType sends a notification using the user's preferred notification method
-func sendNotificationByType(userInfo db.UserNotify, message string, telegrambot telegram.Bot) error {
-	identifier := userInfo.Identifier()
+// enqueueAlert writes one delivery to the outbox. It expires with the notification window.
+func enqueueAlert(deliveries *outbox.Worker, earthquake EarthquakeRef, event Event, alert recipientAlert, channel, recipient string, message notify.Message) error {
+	return deliveries.Enqueue(outbox.Delivery{
+		Channel:      channel,
+		Recipient:    recipient,
+		EarthquakeID: earthquake.ID,
+		Revision:     earthquake.Revision,
+		Message:      message,
+		MMI:          alert.MMI,
+		DistanceKm:   alert.Distance,
+		ExpiresAt:    event.OriginTime.Add(notifyWindow * time.Minute),
+	})
+}
+
+// buildAlertMessage renders the "alert" template of the recipient's tier with their estimated
+// MMI and distance, followed by the list of affected saved places. Revisions are clearly
+// marked as a correction and show what changed.
+func buildAlertMessage(event Event, previous *Event, alert recipientAlert, places []placeAlert, selector messages.Selector) (string, error) {
+	gempa := event.Gempa
+	data := alertData{
+		Tier:      alert.Tier.Name,
+		Magnitude: gempa.Magnitude,
+		Kedalaman: gempa.Kedalaman,
+		Lintang:   gempa.Lintang,
+		Bujur:     gempa.Bujur,
+		Tanggal:   gempa.Tanggal,
+		Jam:       gempa.Jam,
+		Wilayah:   gempa.Wilayah,
+		MMI:       alert.MMI,
+		MMIRoman:  ngitung.MMIRoman(alert.MMI),
+		Distance:  alert.Distance,
+	}
+	for _, place := range places {
+		data.Places = append(data.Places, alertPlace{
+			Label:    place.Label,
+			MMI:      place.Alert.MMI,
+			MMIRoman: ngitung.MMIRoman(place.Alert.MMI),
+			Distance: place.Alert.Distance,
+		})
+	}
 
-	switch userInfo.Type() {
-	case db.Telegram:
-		chatID, err := strconv.ParseInt(identifier, 10, 64)
+	message, err := messages.Render(messages.KeyAlert, selector, data)
+	if err != nil {
+		return "", err
+	}
+
+	if len(places) > 0 {
+		list, err := messages.Render(messages.KeyPlaces, selector, data)
 		if err != nil {
-			return fmt.Errorf("failed to parse telegram chat ID: %w", err)
+			return "", err
 		}
-		return telegrambot.SendMessage(chatID, message)
-	case db.Wa:
-		return notify.SendWhatsAppNotification(identifier, message)
-	default:
-		return nil
+		message += "\n\n" + list
+	}
+
+	if previous != nil {
+		data.PreviousMagnitude = previous.Gempa.Magnitude
+		data.PreviousKedalaman = previous.Gempa.Kedalaman
+
+		correction, err := messages.Render(messages.KeyCorrection, selector, data)
+		if err != nil {
+			return "", err
+		}
+		message = correction + "\n" + message
+	}
+	return message, nil
+}
+
+// buildMQTTAlert creates the structured payload for a device. The alert expires together
+// with the notification window, so a device coming back online late stays silent.
+func buildMQTTAlert(earthquake EarthquakeRef, event Event, previous *Event, alert recipientAlert) notify.MQTTAlert {
+	return notify.MQTTAlert{
+		EventID:       earthquake.ID,
+		Revision:      earthquake.Revision,
+		Correction:    previous != nil,
+		Magnitude:     event.Magnitude,
+		DepthKm:       event.DepthKm,
+		DistanceKm:    math.Round(alert.Distance*10) / 10,
+		MMI:           math.Round(alert.MMI*10) / 10,
+		Tier:          alert.Tier.Name,
+		Siren:         alert.Tier.Siren,
+		SirenDuration: alert.Tier.SirenDuration,
+		OriginTime:    event.OriginTime.UTC(),
+		ExpiresAt:     event.OriginTime.Add(notifyWindow * time.Minute).UTC(),
 	}
 }
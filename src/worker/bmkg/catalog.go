@@ -0,0 +1,160 @@
+package bmkg
+
+import (
+	"bmkg/src/utils/ngitung"
+	"math"
+	"time"
+)
+
+// EventSource is one agency report merged into an earthquake record
+type EventSource struct {
+	Source     string    `json:"source"`
+	Agency     string    `json:"agency"`
+	ID         string    `json:"id"`
+	Magnitude  float64   `json:"magnitude"`
+	DepthKm    float64   `json:"depth_km"`
+	ReportedAt time.Time `json:"reported_at"`
+}
+
+// trackedEvent is a known physical earthquake together with its stored record.
+// Event holds the values currently stored on the record.
+type trackedEvent struct {
+	Event
+	RecordID string
+	Sources  []EventSource
+	SeenAt   time.Time
+	Revision int
+	Seen     map[string]bool // fingerprints reported for the earthquake by any feed
+}
+
+// tolerance bounds the differences between two reports of the same quake
+type tolerance struct {
+	seconds    float64
+	distanceKm float64
+	magnitude  float64
+}
+
+var (
+	// agencyTolerance matches reports of different agencies
+	agencyTolerance = tolerance{matchTimeSeconds, matchDistanceKm, matchMagnitude}
+	// revisionTolerance matches a revised report of the agency that owns the record
+	revisionTolerance = tolerance{revisionTimeSeconds, revisionDistanceKm, revisionMagnitude}
+)
+
+// catalog keeps the recent earthquakes in memory so that reports from other
+// feeds and agencies can be matched against them. It is only used by the
+// pipeline goroutine and therefore needs no locking.
+type catalog struct {
+	events []*trackedEvent
+}
+
+// newEventSource describes the report of event as a source entry
+func newEventSource(event Event) EventSource {
+	return EventSource{
+		Source:     event.Feed,
+		Agency:     event.Agency,
+		ID:         event.SourceID,
+		Magnitude:  event.Magnitude,
+		DepthKm:    event.DepthKm,
+		ReportedAt: time.Now().UTC(),
+	}
+}
+
+// hasAgency reports whether the agency already contributed to the earthquake
+func (t *trackedEvent) hasAgency(agency string) bool {
+	for _, source := range t.Sources {
+		if source.Agency == agency {
+			return true
+		}
+	}
+	return false
+}
+
+// hasSourceID reports whether the agency already reported the earthquake under id
+func (t *trackedEvent) hasSourceID(agency, id string) bool {
+	for _, source := range t.Sources {
+		if source.Agency == agency && source.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// setSource replaces the source entry of the event's agency with the new report
+func (t *trackedEvent) setSource(event Event) {
+	for i, source := range t.Sources {
+		if source.Agency == event.Agency {
+			t.Sources[i] = newEventSource(event)
+			return
+		}
+	}
+	t.Sources = append(t.Sources, newEventSource(event))
+}
+
+// add starts tracking an earthquake
+func (c *catalog) add(t *trackedEvent) {
+	c.events = append(c.events, t)
+}
+
+// prune forgets earthquakes seen longer than seenTTL ago
+func (c *catalog) prune(now time.Time) {
+	kept := c.events[:0]
+	for _, t := range c.events {
+		if now.Sub(t.SeenAt) <= seenTTL*time.Hour {
+			kept = append(kept, t)
+		}
+	}
+	c.events = kept
+}
+
+// match finds the tracked earthquake that event describes. A report matches on
+// identity or on the agency's event id. Otherwise a report of the agency owning
+// the record matches within revisionTolerance, and a report of an agency that
+// did not contribute yet matches within agencyTolerance. The closest candidate wins.
+func (c *catalog) match(event Event) *trackedEvent {
+	var best *trackedEvent
+	bestScore := math.MaxFloat64
+
+	for _, t := range c.events {
+		if t.Key == event.Key || t.hasSourceID(event.Agency, event.SourceID) {
+			return t
+		}
+
+		var score float64
+		var ok bool
+		switch {
+		case t.Agency == event.Agency:
+			score, ok = matchScore(t.Event, event, revisionTolerance)
+		case !t.hasAgency(event.Agency):
+			score, ok = matchScore(t.Event, event, agencyTolerance)
+		}
+
+		if ok && score < bestScore {
+			best = t
+			bestScore = score
+		}
+	}
+
+	return best
+}
+
+// matchScore reports whether a and b are within every bound of tol and how close
+// they are, each difference normalized by its bound
+func matchScore(a, b Event, tol tolerance) (float64, bool) {
+	dt := math.Abs(a.OriginTime.Sub(b.OriginTime).Seconds())
+	if dt > tol.seconds {
+		return 0, false
+	}
+
+	distance := ngitung.Distance(ngitung.Location{Lat: a.Lat, Lon: a.Lon}, ngitung.Location{Lat: b.Lat, Lon: b.Lon})
+	if distance > tol.distanceKm {
+		return 0, false
+	}
+
+	dm := math.Abs(a.Magnitude - b.Magnitude)
+	if dm > tol.magnitude {
+		return 0, false
+	}
+
+	return dt/tol.seconds + distance/tol.distanceKm + dm/tol.magnitude, true
+}
@@ -4,6 +4,55 @@ package bmkg
 const (
 	// bmkgAPIURL is the URL of the BMKG API.
 	bmkgAPIURL = "https://data.bmkg.go.id/DataMKG/TEWS/autogempa.json"
+	// gempaTerkiniURL is the list of the latest M5+ earthquakes.
+	gempaTerkiniURL = "https://data.bmkg.go.id/DataMKG/TEWS/gempaterkini.json"
+	// gempaDirasakanURL is the list of the latest felt earthquakes.
+	gempaDirasakanURL = "https://data.bmkg.go.id/DataMKG/TEWS/gempadirasakan.json"
+	// shakemapBaseURL is where the shakemap image named in a BMKG report is published.
+	shakemapBaseURL = "https://data.bmkg.go.id/DataMKG/TEWS/"
+	// usgsFeedURL is the USGS GeoJSON summary feed of M2.5+ events of the past hour.
+	usgsFeedURL = "https://earthquake.usgs.gov/earthquakes/feed/v1.0/summary/2.5_hour.geojson"
+	// emscFDSNURL is the EMSC FDSN event service, limited to the region below.
+	emscFDSNURL = "https://www.seismicportal.eu/fdsnws/event/1/query?format=text&limit=50&minlat=-15&maxlat=10&minlon=90&maxlon=145"
 	// timing is the time interval for fetching the data from the BMKG API.
 	timing = 1
+	// listTiming is the time interval (seconds) for fetching the list feeds.
+	listTiming = 30
+	// foreignTiming is the time interval (seconds) for fetching the USGS and EMSC feeds.
+	foreignTiming = 60
+	// seenTTL is how long (hours) an event is kept in memory for deduplication and matching.
+	seenTTL = 48
+	// notifyWindow is the maximum age (minutes) of an event that still triggers notifications.
+	notifyWindow = 60
+	// eventBuffer is the capacity of the normalized event pipeline.
+	eventBuffer = 64
+)
+
+// Agencies publishing events. Events of different agencies are merged into one earthquake.
+const (
+	agencyBMKG = "bmkg"
+	agencyUSGS = "usgs"
+	agencyEMSC = "emsc"
+)
+
+// Region of interest for foreign agencies: Indonesia and its borders.
+const (
+	regionMinLat = -15.0
+	regionMaxLat = 10.0
+	regionMinLon = 90.0
+	regionMaxLon = 145.0
+)
+
+// Tolerances used to decide that reports of different agencies describe the same quake.
+const (
+	matchTimeSeconds = 60
+	matchDistanceKm  = 100.0
+	matchMagnitude   = 0.8
+)
+
+// Tolerances used to recognize a revised report of an event by the same agency.
+const (
+	revisionTimeSeconds = 30
+	revisionDistanceKm  = 50.0
+	revisionMagnitude   = 1.0
 )
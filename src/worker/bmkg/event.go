@@ -0,0 +1,168 @@
+package bmkg
+
+import (
+	"bmkg/src/db"
+	"bmkg/src/domain"
+	"bmkg/src/utils"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Event is the normalized earthquake that flows through the worker pipeline,
+// regardless of which feed reported it
+type Event struct {
+	Key         string       // identity of the physical event
+	Fingerprint string       // identity plus the reported values, changes on every revision
+	Feed        string       // source that reported the event
+	Agency      string       // agency of the source
+	SourceID    string       // event id assigned by the agency
+	OriginTime  time.Time    // origin time in UTC
+	Lat         float64      // signed latitude, south is negative
+	Lon         float64      // signed longitude, west is negative
+	DepthKm     float64      // hypocenter depth in km
+	Magnitude   float64      // reported magnitude
+	Gempa       domain.Gempa // original BMKG payload
+}
+
+// newEventFromGempa normalizes a BMKG feed item into an Event
+func newEventFromGempa(feedName string, gempa domain.Gempa) (Event, error) {
+	magnitude, err := strconv.ParseFloat(gempa.Magnitude, 64)
+	if err != nil {
+		return Event{}, fmt.Errorf("failed to parse magnitude: %w", err)
+	}
+
+	// Coordinates already carries the sign, Lintang/Bujur only as a suffix
+	lat, lon, err := utils.ParseCoordinates(gempa.Coordinates)
+	if err != nil {
+		if lat, err = utils.ParseCoordinate(gempa.Lintang); err != nil {
+			return Event{}, fmt.Errorf("failed to parse earthquake latitude: %w", err)
+		}
+		if lon, err = utils.ParseCoordinate(gempa.Bujur); err != nil {
+			return Event{}, fmt.Errorf("failed to parse earthquake longitude: %w", err)
+		}
+	}
+
+	depth, err := strconv.ParseFloat(utils.ExtractNumber(gempa.Kedalaman), 64)
+	if err != nil {
+		return Event{}, fmt.Errorf("failed to parse depth: %w", err)
+	}
+
+	event := Event{
+		Feed:       feedName,
+		Agency:     agencyBMKG,
+		OriginTime: gempa.DateTime.UTC(),
+		Lat:        lat,
+		Lon:        lon,
+		DepthKm:    depth,
+		Magnitude:  magnitude,
+		Gempa:      gempa,
+	}
+	event.Key = eventKey(event)
+	event.SourceID = event.Key
+	event.Fingerprint = eventFingerprint(event)
+
+	return event, nil
+}
+
+// newForeignEvent builds an Event for agencies other than BMKG. The BMKG shaped
+// payload is synthesized so storage and messages stay the same for every source.
+func newForeignEvent(feedName, agency, sourceID string, originTime time.Time, lat, lon, depth, magnitude float64, region string) Event {
+	event := Event{
+		Feed:       feedName,
+		Agency:     agency,
+		SourceID:   sourceID,
+		OriginTime: originTime.UTC(),
+		Lat:        lat,
+		Lon:        lon,
+		DepthKm:    depth,
+		Magnitude:  magnitude,
+		Gempa: domain.Gempa{
+			Tanggal:     utils.FormatTanggal(originTime),
+			Jam:         utils.FormatJam(originTime),
+			DateTime:    originTime.UTC(),
+			Coordinates: fmt.Sprintf("%.2f,%.2f", lat, lon),
+			Lintang:     utils.FormatLintang(lat),
+			Bujur:       utils.FormatBujur(lon),
+			Magnitude:   strconv.FormatFloat(magnitude, 'f', 1, 64),
+			Kedalaman:   strconv.FormatFloat(depth, 'f', 0, 64) + " km",
+			Wilayah:     region,
+		},
+	}
+	event.Key = eventKey(event)
+	event.Fingerprint = eventFingerprint(event)
+
+	return event
+}
+
+// eventKey builds the identity of an event. Every BMKG feed publishes the same
+// DateTime and coordinates for the same quake, so they are used as the key.
+func eventKey(event Event) string {
+	return fmt.Sprintf("%s|%.2f|%.2f", event.OriginTime.Format(time.RFC3339), event.Lat, event.Lon)
+}
+
+// eventFingerprint hashes the identity together with magnitude and depth. It is stored
+// with the earthquake record so an event is never persisted or notified twice, even across restarts.
+func eventFingerprint(event Event) string {
+	sum := sha1.Sum([]byte(fmt.Sprintf("%s|%.1f|%.0f", event.Key, event.Magnitude, event.DepthKm)))
+	return hex.EncodeToString(sum[:])
+}
+
+// eventFromRecord rebuilds an Event from a stored earthquake using its typed fields
+func eventFromRecord(earthquake *db.Earthquake) Event {
+	event := Event{
+		OriginTime: earthquake.OriginTime().Time().UTC(),
+		Lat:        earthquake.Lat(),
+		Lon:        earthquake.Lon(),
+		DepthKm:    earthquake.DepthKm(),
+		Magnitude:  earthquake.Magnitude(),
+		Agency:     agencyBMKG,
+		Gempa: domain.Gempa{
+			Tanggal:     earthquake.Tanggal(),
+			Jam:         earthquake.Jam(),
+			DateTime:    earthquake.DateTime().Time(),
+			Coordinates: earthquake.Coordinates(),
+			Lintang:     earthquake.Lintang(),
+			Bujur:       earthquake.Bujur(),
+			Magnitude:   earthquake.MagnitudeText(),
+			Kedalaman:   earthquake.Kedalaman(),
+			Wilayah:     earthquake.Wilayah(),
+			Potensi:     earthquake.Potensi(),
+			Dirasakan:   earthquake.Dirasakan(),
+			Shakemap:    earthquake.Shakemap(),
+		},
+	}
+	event.Key = eventKey(event)
+	event.SourceID = event.Key
+	event.Fingerprint = eventFingerprint(event)
+
+	return event
+}
+
+// earthquakeData maps an Event onto the fields of the earthquake collection,
+// the BMKG text as published plus the typed values parsed from it
+func earthquakeData(event Event) map[string]interface{} {
+	gempa := event.Gempa
+	return map[string]interface{}{
+		"Coordinates":   gempa.Coordinates,
+		"Lintang":       gempa.Lintang,
+		"Bujur":         gempa.Bujur,
+		"MagnitudeText": gempa.Magnitude,
+		"Kedalaman":     gempa.Kedalaman,
+		"Wilayah":       gempa.Wilayah,
+		"Potensi":       gempa.Potensi,
+		"Dirasakan":     gempa.Dirasakan,
+		"Shakemap":      gempa.Shakemap,
+		"Jam":           gempa.Jam,
+		"Tanggal":       gempa.Tanggal,
+		"DateTime":      gempa.DateTime,
+		"magnitude":     event.Magnitude,
+		"depth_km":      event.DepthKm,
+		"lat":           event.Lat,
+		"lon":           event.Lon,
+		"origin_time":   event.OriginTime,
+		"fingerprint":   event.Fingerprint,
+	}
+}
@@ -0,0 +1,49 @@
+package bmkg
+
+import (
+	"bmkg/src/db"
+	"bmkg/src/utils"
+	"log"
+	"math"
+	"time"
+)
+
+// wantsAlert applies the preferences of a subscriber: minimum magnitude, minimum estimated
+// MMI, radius and quiet hours. A tier with OverrideQuietHours, by default severe, ignores
+// quiet hours. For a revision the filters are checked against the stronger and nearer of
+// both reports, so whoever received the first alert also receives its correction.
+func wantsAlert(user *db.UserNotify, event Event, previous *Event, alert recipientAlert, now time.Time) bool {
+	magnitude := event.Magnitude
+	if previous != nil {
+		magnitude = math.Max(magnitude, previous.Magnitude)
+	}
+	if magnitude < user.MinMagnitude() {
+		return false
+	}
+
+	if math.Max(alert.MMI, alert.PreviousMMI) < user.MinMmi() {
+		return false
+	}
+
+	if radius := user.RadiusKm(); radius > 0 {
+		distance := alert.Distance
+		if previous != nil {
+			distance = math.Min(distance, alert.PreviousDistance)
+		}
+		if distance > radius {
+			return false
+		}
+	}
+
+	if alert.Tier.OverrideQuietHours {
+		return true
+	}
+
+	quiet, err := utils.InQuietHours(user.QuietStart(), user.QuietEnd(), user.Timezone(), now)
+	if err != nil {
+		// Broken preferences must not silence an alert
+		log.Printf("Invalid quiet hours of subscriber %s: %v", user.Id, err)
+		return true
+	}
+	return !quiet
+}
@@ -0,0 +1,141 @@
+package bmkg
+
+import (
+	"bmkg/src/db"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// testSubscriber builds a user_notify record with the given quiet hours and time zone
+func testSubscriber(quietStart, quietEnd, timezone string) *db.UserNotify {
+	collection := core.NewBaseCollection("user_notify")
+	collection.Fields.Add(
+		&core.NumberField{Name: "min_magnitude"},
+		&core.NumberField{Name: "min_mmi"},
+		&core.NumberField{Name: "radius_km"},
+		&core.TextField{Name: "quiet_start"},
+		&core.TextField{Name: "quiet_end"},
+		&core.TextField{Name: "timezone"},
+	)
+
+	user := &db.UserNotify{}
+	user.SetProxyRecord(core.NewRecord(collection))
+	user.Id = "subscriber"
+	user.SetQuietStart(quietStart)
+	user.SetQuietEnd(quietEnd)
+	user.SetTimezone(timezone)
+	return user
+}
+
+func TestWantsAlertQuietHours(t *testing.T) {
+	defaults := defaultTiers()
+	informational, warning, severe := &defaults[0], &defaults[1], &defaults[2]
+
+	// 23:00 WIB, 00:00 WITA, 01:00 WIT
+	night := time.Date(2025, time.May, 16, 16, 0, 0, 0, time.UTC)
+	// 12:00 WIB
+	midday := time.Date(2025, time.May, 16, 5, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		quietStart string
+		quietEnd   string
+		timezone   string
+		tier       *AlertTier
+		now        time.Time
+		want       bool
+	}{
+		{"no quiet hours", "", "", "", informational, night, true},
+		{"overnight window, informational at night", "22:00", "06:00", "Asia/Jakarta", informational, night, false},
+		{"overnight window, warning at night", "22:00", "06:00", "Asia/Jakarta", warning, night, false},
+		{"overnight window, informational at midday", "22:00", "06:00", "Asia/Jakarta", informational, midday, true},
+		// severe ignores quiet hours
+		{"overnight window, severe at night", "22:00", "06:00", "Asia/Jakarta", severe, night, true},
+		{"start equals end", "22:00", "22:00", "Asia/Jakarta", informational, night, true},
+		// 16:00 UTC is 01:00 WIT, inside 00:00–05:00 in Jayapura but 23:00 in Jakarta
+		{"non-WIB zone inside the window", "00:00", "05:00", "Asia/Jayapura", informational, night, false},
+		{"same window in WIB", "00:00", "05:00", "Asia/Jakarta", informational, night, true},
+		{"non-WIB zone, severe", "00:00", "05:00", "Asia/Jayapura", severe, night, true},
+		// broken preferences must not silence an alert
+		{"invalid quiet hours", "malam", "06:00", "Asia/Jakarta", informational, night, true},
+		{"invalid time zone", "22:00", "06:00", "Asia/Bandung", informational, night, true},
+	}
+
+	event := Event{Magnitude: 5.0}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := testSubscriber(tt.quietStart, tt.quietEnd, tt.timezone)
+			alert := recipientAlert{Tier: tt.tier, MMI: tt.tier.MinMMI, Distance: 50}
+
+			if got := wantsAlert(user, event, nil, alert, tt.now); got != tt.want {
+				t.Errorf("wantsAlert() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWantsAlertFilters(t *testing.T) {
+	defaults := defaultTiers()
+	severe := &defaults[2]
+	now := time.Date(2025, time.May, 16, 16, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		setup    func(user *db.UserNotify)
+		event    Event
+		previous *Event
+		alert    recipientAlert
+		want     bool
+	}{
+		{
+			name:  "below minimum magnitude",
+			setup: func(user *db.UserNotify) { user.SetMinMagnitude(5) },
+			event: Event{Magnitude: 4.9},
+			alert: recipientAlert{Tier: severe, MMI: 7, Distance: 10},
+			want:  false,
+		},
+		{
+			name:     "revision below minimum magnitude after a first report above it",
+			setup:    func(user *db.UserNotify) { user.SetMinMagnitude(5) },
+			event:    Event{Magnitude: 4.8},
+			previous: &Event{Magnitude: 5.2},
+			alert:    recipientAlert{Tier: severe, MMI: 7, Distance: 10},
+			want:     true,
+		},
+		{
+			name:  "below minimum MMI",
+			setup: func(user *db.UserNotify) { user.SetMinMmi(8) },
+			event: Event{Magnitude: 6},
+			alert: recipientAlert{Tier: severe, MMI: 7, Distance: 10},
+			want:  false,
+		},
+		{
+			name:  "outside the radius",
+			setup: func(user *db.UserNotify) { user.SetRadiusKm(100) },
+			event: Event{Magnitude: 6},
+			alert: recipientAlert{Tier: severe, MMI: 7, Distance: 150},
+			want:  false,
+		},
+		{
+			name:     "revision moved out of the radius",
+			setup:    func(user *db.UserNotify) { user.SetRadiusKm(100) },
+			event:    Event{Magnitude: 6},
+			previous: &Event{Magnitude: 6},
+			alert:    recipientAlert{Tier: severe, MMI: 7, Distance: 150, PreviousMMI: 7, PreviousDistance: 80},
+			want:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := testSubscriber("22:00", "06:00", "Asia/Jakarta")
+			tt.setup(user)
+
+			if got := wantsAlert(user, tt.event, tt.previous, tt.alert, now); got != tt.want {
+				t.Errorf("wantsAlert() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
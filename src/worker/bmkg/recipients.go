@@ -0,0 +1,228 @@
+package bmkg
+
+import (
+	"bmkg/src/utils"
+	"bmkg/src/utils/ngitung"
+	"fmt"
+	"github.com/pocketbase/pocketbase/core"
+	"log"
+	"strconv"
+	"sync"
+)
+
+// Collections whose records receive earthquake alerts. Subscribers are alerted for
+// each of their saved locations.
+const (
+	recipientDevices   = "iot_device"
+	recipientUsers     = "user_notify"
+	recipientLocations = "subscriber_location"
+)
+
+// locatedCollections are indexed by geohash, subscribers only by id
+var locatedCollections = []string{recipientDevices, recipientLocations}
+
+// recipientPrecision geohash precision of the index cells (about 39 x 20 km)
+const recipientPrecision = 4
+
+// recipient is an indexed alert target with its parsed location
+type recipient struct {
+	Record   *core.Record
+	Location ngitung.Location
+	cell     string
+}
+
+// RecipientIndex keeps devices and saved subscriber locations bucketed by geohash so an
+// event only evaluates the recipients inside its maximum felt radius. It is loaded once
+// on start and kept in sync through PocketBase record hooks.
+type RecipientIndex struct {
+	mu          sync.RWMutex
+	cells       map[string]map[string]map[string]*recipient // collection -> geohash -> record id
+	byID        map[string]map[string]*recipient            // collection -> record id
+	subscribers map[string]*core.Record                     // user_notify id
+}
+
+// NewRecipientIndex creates an empty index and binds the record hooks that keep it in sync
+func NewRecipientIndex(app core.App) *RecipientIndex {
+	index := newRecipientIndex()
+	index.bindHooks(app)
+	return index
+}
+
+func newRecipientIndex() *RecipientIndex {
+	index := &RecipientIndex{
+		cells:       make(map[string]map[string]map[string]*recipient),
+		byID:        make(map[string]map[string]*recipient),
+		subscribers: make(map[string]*core.Record),
+	}
+
+	for _, collection := range locatedCollections {
+		index.cells[collection] = make(map[string]map[string]*recipient)
+		index.byID[collection] = make(map[string]*recipient)
+	}
+	return index
+}
+
+func (idx *RecipientIndex) bindHooks(app core.App) {
+	app.OnRecordAfterCreateSuccess(recipientDevices, recipientUsers, recipientLocations).BindFunc(func(e *core.RecordEvent) error {
+		idx.Put(e.Record)
+		return e.Next()
+	})
+
+	app.OnRecordAfterUpdateSuccess(recipientDevices, recipientUsers, recipientLocations).BindFunc(func(e *core.RecordEvent) error {
+		idx.Put(e.Record)
+		return e.Next()
+	})
+
+	app.OnRecordAfterDeleteSuccess(recipientDevices, recipientUsers, recipientLocations).BindFunc(func(e *core.RecordEvent) error {
+		idx.Remove(e.Record.Collection().Name, e.Record.Id)
+		return e.Next()
+	})
+}
+
+// Load fills the index with every device, subscriber and saved location
+func (idx *RecipientIndex) Load(app core.App) error {
+	for _, collection := range []string{recipientDevices, recipientUsers, recipientLocations} {
+		records, err := app.FindAllRecords(collection)
+		if err != nil {
+			return fmt.Errorf("failed to load %s recipients: %w", collection, err)
+		}
+		for _, record := range records {
+			idx.Put(record)
+		}
+	}
+	return nil
+}
+
+// Put adds or moves a record. Records without a valid location are removed from the index.
+func (idx *RecipientIndex) Put(record *core.Record) {
+	collection := record.Collection().Name
+	if collection == recipientUsers {
+		idx.mu.Lock()
+		idx.subscribers[record.Id] = record.Fresh()
+		idx.mu.Unlock()
+		return
+	}
+	if _, ok := idx.byID[collection]; !ok {
+		return
+	}
+
+	lat, errLat := strconv.ParseFloat(record.GetString("lintang"), 64)
+	lon, errLon := strconv.ParseFloat(record.GetString("bujur"), 64)
+	if errLat != nil || errLon != nil {
+		idx.Remove(collection, record.Id)
+		return
+	}
+
+	entry := &recipient{
+		// Keep our own copy, the hook caller may keep using its record
+		Record:   record.Fresh(),
+		Location: ngitung.Location{Lat: lat, Lon: lon},
+		cell:     utils.EncodeGeohash(lat, lon, recipientPrecision),
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.removeLocked(collection, record.Id)
+
+	cell, ok := idx.cells[collection][entry.cell]
+	if !ok {
+		cell = make(map[string]*recipient)
+		idx.cells[collection][entry.cell] = cell
+	}
+	cell[record.Id] = entry
+	idx.byID[collection][record.Id] = entry
+}
+
+// Remove drops a record from the index
+func (idx *RecipientIndex) Remove(collection, id string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.removeLocked(collection, id)
+}
+
+func (idx *RecipientIndex) removeLocked(collection, id string) {
+	if collection == recipientUsers {
+		delete(idx.subscribers, id)
+		return
+	}
+
+	existing, ok := idx.byID[collection][id]
+	if !ok {
+		return
+	}
+
+	delete(idx.byID[collection], id)
+	if cell := idx.cells[collection][existing.cell]; cell != nil {
+		delete(cell, id)
+		if len(cell) == 0 {
+			delete(idx.cells[collection], existing.cell)
+		}
+	}
+}
+
+// Subscriber returns the user_notify record of a saved location, or nil when it is unknown
+func (idx *RecipientIndex) Subscriber(id string) *core.Record {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	return idx.subscribers[id]
+}
+
+// Near returns the recipients of a collection within radiusKm of center. The geohash
+// cells give a coarse candidate set which is then filtered by the exact distance.
+func (idx *RecipientIndex) Near(collection string, center ngitung.Location, radiusKm float64) []*recipient {
+	if radiusKm <= 0 {
+		return nil
+	}
+
+	cells := utils.GeohashesInRadius(center.Lat, center.Lon, radiusKm, recipientPrecision)
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	var found []*recipient
+	for _, hash := range cells {
+		for _, entry := range idx.cells[collection][hash] {
+			if ngitung.Distance(center, entry.Location) <= radiusKm {
+				found = append(found, entry)
+			}
+		}
+	}
+	return found
+}
+
+// candidates returns the recipients that may need an alert for event. For a revision the
+// felt area of the previous version is included too, so downgraded recipients are corrected.
+func (idx *RecipientIndex) candidates(collection string, event Event, previous *Event) []*recipient {
+	minMMI := tiers[0].MinMMI
+
+	found := idx.Near(collection, ngitung.Location{Lat: event.Lat, Lon: event.Lon},
+		ngitung.MaxFeltRadius(event.Magnitude, event.DepthKm, minMMI))
+	if previous == nil {
+		return found
+	}
+
+	seen := make(map[string]struct{}, len(found))
+	for _, entry := range found {
+		seen[entry.Record.Id] = struct{}{}
+	}
+
+	for _, entry := range idx.Near(collection, ngitung.Location{Lat: previous.Lat, Lon: previous.Lon},
+		ngitung.MaxFeltRadius(previous.Magnitude, previous.DepthKm, minMMI)) {
+		if _, ok := seen[entry.Record.Id]; !ok {
+			found = append(found, entry)
+		}
+	}
+	return found
+}
+
+// logSize logs how many recipients were indexed
+func (idx *RecipientIndex) logSize() {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	log.Printf("Recipient index loaded: %d devices, %d subscribers, %d locations",
+		len(idx.byID[recipientDevices]), len(idx.subscribers), len(idx.byID[recipientLocations]))
+}
@@ -0,0 +1,133 @@
+package bmkg
+
+import (
+	"bmkg/src/utils/ngitung"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// benchmarkRecipients is the number of synthetic saved locations of the benchmark
+const benchmarkRecipients = 100_000
+
+// syntheticIndex fills an index with n locations spread over Indonesia, ids "loc<i>"
+func syntheticIndex(tb testing.TB, n int) *RecipientIndex {
+	tb.Helper()
+
+	collection := core.NewBaseCollection(recipientLocations)
+	collection.Fields.Add(&core.TextField{Name: "lintang"}, &core.TextField{Name: "bujur"})
+
+	random := rand.New(rand.NewSource(1))
+	index := newRecipientIndex()
+	for i := 0; i < n; i++ {
+		record := core.NewRecord(collection)
+		record.Id = fmt.Sprintf("loc%d", i)
+		record.Set("lintang", strconv.FormatFloat(-11+random.Float64()*17, 'f', 5, 64))
+		record.Set("bujur", strconv.FormatFloat(95+random.Float64()*46, 'f', 5, 64))
+		index.Put(record)
+	}
+	return index
+}
+
+// fullScan is the lookup without the geohash index: every location is checked
+func fullScan(index *RecipientIndex, collection string, center ngitung.Location, radiusKm float64) []*recipient {
+	index.mu.RLock()
+	defer index.mu.RUnlock()
+
+	var found []*recipient
+	for _, entry := range index.byID[collection] {
+		if ngitung.Distance(center, entry.Location) <= radiusKm {
+			found = append(found, entry)
+		}
+	}
+	return found
+}
+
+func recipientIDs(recipients []*recipient) []string {
+	ids := make([]string, 0, len(recipients))
+	for _, entry := range recipients {
+		ids = append(ids, entry.Record.Id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func TestRecipientIndexNearMatchesFullScan(t *testing.T) {
+	index := syntheticIndex(t, 20_000)
+
+	centers := []ngitung.Location{
+		{Lat: -6.2, Lon: 106.8},   // Jakarta
+		{Lat: -0.18, Lon: 119.85}, // Palu
+		{Lat: -6.41, Lon: 129.17}, // Laut Banda
+		{Lat: 0, Lon: 112.5},      // sudut sel geohash
+	}
+	for _, center := range centers {
+		for _, radius := range []float64{1, 25, 100, 400} {
+			want := recipientIDs(fullScan(index, recipientLocations, center, radius))
+			got := recipientIDs(index.Near(recipientLocations, center, radius))
+			if fmt.Sprint(got) != fmt.Sprint(want) {
+				t.Errorf("Near(%v, %.0f km) found %d recipients, full scan %d", center, radius, len(got), len(want))
+			}
+		}
+	}
+}
+
+func TestRecipientIndexPutMovesAndRemoves(t *testing.T) {
+	collection := core.NewBaseCollection(recipientLocations)
+	collection.Fields.Add(&core.TextField{Name: "lintang"}, &core.TextField{Name: "bujur"})
+
+	index := newRecipientIndex()
+	record := core.NewRecord(collection)
+	record.Id = "home"
+	record.Set("lintang", "-6.2")
+	record.Set("bujur", "106.8")
+	index.Put(record)
+
+	jakarta := ngitung.Location{Lat: -6.2, Lon: 106.8}
+	palu := ngitung.Location{Lat: -0.18, Lon: 119.85}
+	if got := index.Near(recipientLocations, jakarta, 10); len(got) != 1 {
+		t.Fatalf("Near(Jakarta) = %d recipients, want 1", len(got))
+	}
+
+	// moved to Palu
+	record.Set("lintang", "-0.18")
+	record.Set("bujur", "119.85")
+	index.Put(record)
+	if got := index.Near(recipientLocations, jakarta, 10); len(got) != 0 {
+		t.Errorf("Near(Jakarta) after the move = %d recipients, want 0", len(got))
+	}
+	if got := index.Near(recipientLocations, palu, 10); len(got) != 1 {
+		t.Errorf("Near(Palu) after the move = %d recipients, want 1", len(got))
+	}
+
+	// an invalid location drops the record
+	record.Set("lintang", "")
+	index.Put(record)
+	if got := index.Near(recipientLocations, palu, 10); len(got) != 0 {
+		t.Errorf("Near(Palu) without location = %d recipients, want 0", len(got))
+	}
+}
+
+// BenchmarkRecipientIndex compares the geohash lookup with a full scan of 100k saved
+// locations for a typical felt radius
+func BenchmarkRecipientIndex(b *testing.B) {
+	index := syntheticIndex(b, benchmarkRecipients)
+	center := ngitung.Location{Lat: -6.2, Lon: 106.8}
+
+	for _, radius := range []float64{50, 150, 400} {
+		b.Run(fmt.Sprintf("FullScan/%.0fkm", radius), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				fullScan(index, recipientLocations, center, radius)
+			}
+		})
+		b.Run(fmt.Sprintf("Near/%.0fkm", radius), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				index.Near(recipientLocations, center, radius)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package bmkg
+
+import (
+	"bmkg/src/domain"
+	"bmkg/src/utils"
+	"time"
+)
+
+// Source is an earthquake catalog polled by the worker. Every source normalizes
+// its own format into Events, so the pipeline does not care who reported a quake.
+type Source interface {
+	// Name identifies the source in logs, state keys and the earthquake sources list
+	Name() string
+	// Agency is the publishing agency, events of the same agency are never merged with each other
+	Agency() string
+	// Interval is the polling schedule of the source
+	Interval() time.Duration
+	// Fetch downloads the catalog using the cache validators of the previous fetch
+	Fetch(client *utils.HTTPClient, validators utils.Validators) (FetchResult, error)
+}
+
+// FetchResult is the outcome of a single Source.Fetch
+type FetchResult struct {
+	Events      []Event
+	Validators  utils.Validators
+	NotModified bool
+}
+
+// sourceState is persisted per source so a restart neither refetches unchanged
+// payloads nor reprocesses the events it already saw
+type sourceState struct {
+	Validators   utils.Validators `json:"validators"`
+	Fingerprints []string         `json:"fingerprints"` // fingerprints of the last processed payload
+}
+
+// defaultSources returns every source consumed by the worker
+func defaultSources() []Source {
+	return []Source{
+		newBMKGSource("autogempa", bmkgAPIURL, timing*time.Second, domain.DecodeAutoGempa),
+		newBMKGSource("gempaterkini", gempaTerkiniURL, listTiming*time.Second, domain.DecodeGempaTerkini),
+		newBMKGSource("gempadirasakan", gempaDirasakanURL, listTiming*time.Second, domain.DecodeGempaDirasakan),
+		newUSGSSource(usgsFeedURL, foreignTiming*time.Second),
+		newEMSCSource(emscFDSNURL, foreignTiming*time.Second),
+	}
+}
+
+// stateKey is the worker_state key of a source
+func stateKey(source Source) string {
+	return "source:" + source.Name()
+}
+
+// withinRegion reports whether an epicenter lies in the area the worker cares about
+func withinRegion(lat, lon float64) bool {
+	return lat >= regionMinLat && lat <= regionMaxLat && lon >= regionMinLon && lon <= regionMaxLon
+}
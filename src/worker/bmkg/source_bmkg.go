@@ -0,0 +1,68 @@
+package bmkg
+
+import (
+	"bmkg/src/domain"
+	"bmkg/src/utils"
+	"fmt"
+	"log"
+	"time"
+)
+
+// bmkgSource polls one BMKG endpoint with its own decoder and polling schedule
+type bmkgSource struct {
+	name     string
+	url      string
+	interval time.Duration
+	decode   func(data []byte) ([]domain.Gempa, error)
+}
+
+// newBMKGSource creates a Source for a BMKG feed
+func newBMKGSource(name, url string, interval time.Duration, decode func(data []byte) ([]domain.Gempa, error)) *bmkgSource {
+	return &bmkgSource{
+		name:     name,
+		url:      url,
+		interval: interval,
+		decode:   decode,
+	}
+}
+
+func (s *bmkgSource) Name() string {
+	return s.name
+}
+
+func (s *bmkgSource) Agency() string {
+	return agencyBMKG
+}
+
+func (s *bmkgSource) Interval() time.Duration {
+	return s.interval
+}
+
+// Fetch downloads the feed and normalizes every item into an Event.
+// Items that cannot be parsed are skipped so one bad entry does not drop the whole list.
+func (s *bmkgSource) Fetch(client *utils.HTTPClient, validators utils.Validators) (FetchResult, error) {
+	response, err := client.GetConditional(s.url, validators)
+	if err != nil {
+		return FetchResult{}, err
+	}
+	if response.NotModified {
+		return FetchResult{Validators: validators, NotModified: true}, nil
+	}
+
+	items, err := s.decode(response.Body)
+	if err != nil {
+		return FetchResult{}, fmt.Errorf("failed to decode %s: %w", s.name, err)
+	}
+
+	events := make([]Event, 0, len(items))
+	for _, item := range items {
+		event, err := newEventFromGempa(s.name, item)
+		if err != nil {
+			log.Printf("Skipping invalid %s item: %v", s.name, err)
+			continue
+		}
+		events = append(events, event)
+	}
+
+	return FetchResult{Events: events, Validators: response.Validators}, nil
+}
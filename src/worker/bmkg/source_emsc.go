@@ -0,0 +1,63 @@
+package bmkg
+
+import (
+	"bmkg/src/domain"
+	"bmkg/src/utils"
+	"fmt"
+	"time"
+)
+
+// emscSource polls the EMSC FDSN event web service in text format
+type emscSource struct {
+	url      string
+	interval time.Duration
+}
+
+// newEMSCSource creates a Source for an FDSN event endpoint
+func newEMSCSource(url string, interval time.Duration) *emscSource {
+	return &emscSource{
+		url:      url,
+		interval: interval,
+	}
+}
+
+func (s *emscSource) Name() string {
+	return "emsc"
+}
+
+func (s *emscSource) Agency() string {
+	return agencyEMSC
+}
+
+func (s *emscSource) Interval() time.Duration {
+	return s.interval
+}
+
+// Fetch downloads the FDSN rows and converts them into Events. The query already
+// limits the area, the region check only guards against a changed URL.
+func (s *emscSource) Fetch(client *utils.HTTPClient, validators utils.Validators) (FetchResult, error) {
+	response, err := client.GetConditional(s.url, validators)
+	if err != nil {
+		return FetchResult{}, err
+	}
+	if response.NotModified {
+		return FetchResult{Validators: validators, NotModified: true}, nil
+	}
+
+	rows, err := domain.DecodeFDSNText(response.Body)
+	if err != nil {
+		return FetchResult{}, fmt.Errorf("failed to decode emsc: %w", err)
+	}
+
+	events := make([]Event, 0, len(rows))
+	for _, row := range rows {
+		if !withinRegion(row.Latitude, row.Longitude) {
+			continue
+		}
+
+		events = append(events, newForeignEvent(s.Name(), agencyEMSC, row.EventID,
+			row.Time, row.Latitude, row.Longitude, row.DepthKm, row.Magnitude, row.EventLocationName))
+	}
+
+	return FetchResult{Events: events, Validators: response.Validators}, nil
+}
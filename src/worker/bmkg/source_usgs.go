@@ -0,0 +1,69 @@
+package bmkg
+
+import (
+	"bmkg/src/domain"
+	"bmkg/src/utils"
+	"fmt"
+	"time"
+)
+
+// usgsSource polls the USGS GeoJSON summary feed and keeps the events around Indonesia
+type usgsSource struct {
+	url      string
+	interval time.Duration
+}
+
+// newUSGSSource creates a Source for a USGS GeoJSON summary feed
+func newUSGSSource(url string, interval time.Duration) *usgsSource {
+	return &usgsSource{
+		url:      url,
+		interval: interval,
+	}
+}
+
+func (s *usgsSource) Name() string {
+	return "usgs"
+}
+
+func (s *usgsSource) Agency() string {
+	return agencyUSGS
+}
+
+func (s *usgsSource) Interval() time.Duration {
+	return s.interval
+}
+
+// Fetch downloads the summary feed and converts every feature into an Event
+func (s *usgsSource) Fetch(client *utils.HTTPClient, validators utils.Validators) (FetchResult, error) {
+	response, err := client.GetConditional(s.url, validators)
+	if err != nil {
+		return FetchResult{}, err
+	}
+	if response.NotModified {
+		return FetchResult{Validators: validators, NotModified: true}, nil
+	}
+
+	features, err := domain.DecodeUSGS(response.Body)
+	if err != nil {
+		return FetchResult{}, fmt.Errorf("failed to decode usgs: %w", err)
+	}
+
+	events := make([]Event, 0, len(features))
+	for _, feature := range features {
+		if feature.Properties.Type != "earthquake" || len(feature.Geometry.Coordinates) < 3 {
+			continue
+		}
+
+		lon := feature.Geometry.Coordinates[0]
+		lat := feature.Geometry.Coordinates[1]
+		if !withinRegion(lat, lon) {
+			continue
+		}
+
+		events = append(events, newForeignEvent(s.Name(), agencyUSGS, feature.ID,
+			time.UnixMilli(feature.Properties.Time), lat, lon,
+			feature.Geometry.Coordinates[2], feature.Properties.Mag, feature.Properties.Place))
+	}
+
+	return FetchResult{Events: events, Validators: response.Validators}, nil
+}
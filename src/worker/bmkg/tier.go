@@ -0,0 +1,135 @@
+package bmkg
+
+import (
+	"bmkg/src/utils/notify"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// Channel names used in a tier's channel set
+const (
+	channelMQTT     = "mqtt"
+	channelTelegram = "telegram"
+	channelWA       = "wa"
+	channelAndroid  = "android"
+	channelEmail    = "email"
+)
+
+// AlertTier describes how recipients within an estimated intensity band are alerted.
+// A recipient falls into the highest tier whose MinMMI is not above their estimated MMI.
+// The wording is the "alert" message template of the tier, see package messages.
+type AlertTier struct {
+	Name   string  `json:"name"`
+	MinMMI float64 `json:"min_mmi"`
+	Siren  string  `json:"siren"`
+	// SirenDuration is how long (seconds) the device siren sounds
+	SirenDuration int `json:"siren_duration"`
+	// Priority is the push priority, "high" wakes the device even in doze mode
+	Priority string `json:"priority"`
+	// OverrideQuietHours alerts subscribers even during their quiet hours
+	OverrideQuietHours bool     `json:"override_quiet_hours"`
+	Channels           []string `json:"channels"`
+}
+
+// alertData is the data passed to the alert, correction and places templates
+type alertData struct {
+	Tier      string
+	Magnitude string
+	Kedalaman string
+	Lintang   string
+	Bujur     string
+	Tanggal   string
+	Jam       string
+	Wilayah   string
+	MMI       float64
+	MMIRoman  string
+	Distance  float64
+	// PreviousMagnitude and PreviousKedalaman are the values a revision replaces
+	PreviousMagnitude string
+	PreviousKedalaman string
+	// Places are the affected saved locations of the subscriber, strongest first
+	Places []alertPlace
+}
+
+// alertPlace is the estimated shaking at one saved location
+type alertPlace struct {
+	Label    string
+	MMI      float64
+	MMIRoman string
+	Distance float64
+}
+
+// defaultTiers informational (III–IV), warning (V–VI) dan severe (VII+)
+func defaultTiers() []AlertTier {
+	return []AlertTier{
+		{
+			Name:          "informational",
+			MinMMI:        3,
+			Siren:         "none",
+			SirenDuration: 0,
+			Channels:      []string{channelMQTT, channelTelegram, channelWA, channelAndroid, channelEmail},
+		},
+		{
+			Name:          "warning",
+			MinMMI:        5,
+			Siren:         "pulse",
+			SirenDuration: 30,
+			Channels:      []string{channelMQTT, channelTelegram, channelWA, channelAndroid, channelEmail},
+		},
+		{
+			Name:               "severe",
+			MinMMI:             7,
+			Siren:              "continuous",
+			SirenDuration:      120,
+			Priority:           notify.PriorityHigh,
+			OverrideQuietHours: true,
+			Channels:           []string{channelMQTT, channelTelegram, channelWA, channelAndroid, channelEmail},
+		},
+	}
+}
+
+// tiers is the active tier set, sorted by MinMMI ascending
+var tiers = sortTiers(defaultTiers())
+
+// SetTiers replaces the alert tiers with a JSON array of AlertTier.
+// An empty string keeps the default tiers.
+func SetTiers(raw string) error {
+	if raw == "" {
+		return nil
+	}
+
+	var parsed []AlertTier
+	if err := json.Unmarshal([]byte(raw), &parsed); err != nil {
+		return fmt.Errorf("invalid alert tiers: %w", err)
+	}
+	if len(parsed) == 0 {
+		return fmt.Errorf("invalid alert tiers: at least one tier is required")
+	}
+
+	tiers = sortTiers(parsed)
+	return nil
+}
+
+// sortTiers orders the tiers by MinMMI ascending
+func sortTiers(list []AlertTier) []AlertTier {
+	sort.Slice(list, func(i, j int) bool { return list[i].MinMMI < list[j].MinMMI })
+	return list
+}
+
+// tierFor returns the tier for an estimated MMI, or nil when it is below every tier
+func tierFor(mmi float64) *AlertTier {
+	var found *AlertTier
+	for i := range tiers {
+		if mmi >= tiers[i].MinMMI {
+			found = &tiers[i]
+		}
+	}
+	return found
+}
+
+// HasChannel reports whether the tier alerts through channel
+func (t *AlertTier) HasChannel(channel string) bool {
+	return slices.Contains(t.Channels, channel)
+}
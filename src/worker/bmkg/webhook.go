@@ -0,0 +1,176 @@
+package bmkg
+
+import (
+	"bmkg/src/db"
+	"bmkg/src/utils/ngitung"
+	"bmkg/src/utils/notify"
+	"bmkg/src/worker/outbox"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"slices"
+	"time"
+)
+
+// Webhook event types, see the webhook_subscription event_types select
+const (
+	webhookEarthquakeCreated = "earthquake.created"
+	webhookEarthquakeRevised = "earthquake.revised"
+)
+
+// webhookEventVersion is the version of the webhook payload, raised on breaking changes
+const webhookEventVersion = 1
+
+// channelWebhook is the outbox channel of partner webhooks
+const channelWebhook = "webhook"
+
+// webhookEvent is the JSON body POSTed to partner systems
+type webhookEvent struct {
+	Version      int              `json:"v"`
+	ID           string           `json:"id"`
+	Type         string           `json:"type"`
+	EarthquakeID string           `json:"earthquake_id"`
+	Revision     int              `json:"revision"`
+	OriginTime   time.Time        `json:"origin_time"`
+	Lat          float64          `json:"lat"`
+	Lon          float64          `json:"lon"`
+	DepthKm      float64          `json:"depth_km"`
+	Magnitude    float64          `json:"magnitude"`
+	Region       string           `json:"region,omitempty"`
+	Agency       string           `json:"agency"`
+	ShakemapURL  string           `json:"shakemap_url,omitempty"`
+	Previous     *webhookPrevious `json:"previous,omitempty"`
+	// Point is the estimated shaking at the point of the subscription, when it has one
+	Point *webhookPoint `json:"point,omitempty"`
+}
+
+// webhookPrevious are the values a revision replaces
+type webhookPrevious struct {
+	Lat       float64 `json:"lat"`
+	Lon       float64 `json:"lon"`
+	DepthKm   float64 `json:"depth_km"`
+	Magnitude float64 `json:"magnitude"`
+}
+
+type webhookPoint struct {
+	Lat        float64 `json:"lat"`
+	Lon        float64 `json:"lon"`
+	MMI        float64 `json:"mmi"`
+	DistanceKm float64 `json:"distance_km"`
+}
+
+// notifyWebhooks plans a delivery for every active subscription whose filters match the event.
+// A revision also goes to subscriptions the previous values matched, so they get the correction.
+func (w *BMKGWorker) notifyWebhooks(earthquake EarthquakeRef, event Event, previous *Event) {
+	subscriptions, err := w.webhooks.Active()
+	if err != nil {
+		log.Printf("Error loading webhook subscriptions: %v", err)
+		return
+	}
+
+	eventType, typeName := db.EarthquakeCreated, webhookEarthquakeCreated
+	if previous != nil {
+		eventType, typeName = db.EarthquakeRevised, webhookEarthquakeRevised
+	}
+
+	for _, subscription := range subscriptions {
+		if !subscribedTo(subscription, eventType) {
+			continue
+		}
+
+		point, matches := matchWebhook(subscription, event)
+		if !matches && previous != nil {
+			_, matches = matchWebhook(subscription, *previous)
+		}
+		if !matches {
+			continue
+		}
+
+		payload, err := json.Marshal(buildWebhookEvent(earthquake, typeName, event, previous, point))
+		if err != nil {
+			log.Printf("Failed to encode webhook event: %v", err)
+			return
+		}
+
+		delivery := outbox.Delivery{
+			Channel:      channelWebhook,
+			Recipient:    subscription.Id,
+			EarthquakeID: earthquake.ID,
+			Revision:     earthquake.Revision,
+			Message:      notify.Message{Payload: payload},
+		}
+		if point != nil {
+			delivery.MMI, delivery.DistanceKm = point.MMI, point.DistanceKm
+		}
+
+		if err := w.outbox.Enqueue(delivery); err != nil {
+			log.Printf("Failed to plan webhook to %s: %v", subscription.Id, err)
+		}
+	}
+}
+
+// subscribedTo reports whether the subscription wants the event type, none selected means all
+func subscribedTo(subscription *db.WebhookSubscription, eventType db.EventTypesSelectType) bool {
+	types := subscription.EventTypes()
+	return len(types) == 0 || slices.Contains(types, eventType)
+}
+
+// matchWebhook applies the filters of a subscription. The bounding box applies when it
+// has an area, the point filter when min_mmi is set.
+func matchWebhook(subscription *db.WebhookSubscription, event Event) (*webhookPoint, bool) {
+	if event.Magnitude < subscription.MinMagnitude() {
+		return nil, false
+	}
+
+	if subscription.MaxLat() > subscription.MinLat() && subscription.MaxLon() > subscription.MinLon() {
+		if event.Lat < subscription.MinLat() || event.Lat > subscription.MaxLat() ||
+			event.Lon < subscription.MinLon() || event.Lon > subscription.MaxLon() {
+			return nil, false
+		}
+	}
+
+	if subscription.MinMmi() <= 0 {
+		return nil, true
+	}
+
+	target := ngitung.Location{Lat: subscription.PointLat(), Lon: subscription.PointLon()}
+	_, distance, mmi := ngitung.IsWithinFeltRadius(ngitung.Location{Lat: event.Lat, Lon: event.Lon}, target, event.Magnitude, event.DepthKm)
+	point := &webhookPoint{
+		Lat:        target.Lat,
+		Lon:        target.Lon,
+		MMI:        math.Round(mmi*10) / 10,
+		DistanceKm: math.Round(distance*10) / 10,
+	}
+	return point, mmi >= subscription.MinMmi()
+}
+
+func buildWebhookEvent(earthquake EarthquakeRef, eventType string, event Event, previous *Event, point *webhookPoint) webhookEvent {
+	payload := webhookEvent{
+		Version:      webhookEventVersion,
+		ID:           fmt.Sprintf("%s:%d", earthquake.ID, earthquake.Revision),
+		Type:         eventType,
+		EarthquakeID: earthquake.ID,
+		Revision:     earthquake.Revision,
+		OriginTime:   event.OriginTime.UTC(),
+		Lat:          event.Lat,
+		Lon:          event.Lon,
+		DepthKm:      event.DepthKm,
+		Magnitude:    event.Magnitude,
+		Region:       event.Gempa.Wilayah,
+		Agency:       event.Agency,
+		Point:        point,
+	}
+	if event.Gempa.Shakemap != "" {
+		payload.ShakemapURL = shakemapBaseURL + event.Gempa.Shakemap
+	}
+	if previous != nil {
+		payload.Previous = &webhookPrevious{
+			Lat:       previous.Lat,
+			Lon:       previous.Lon,
+			DepthKm:   previous.DepthKm,
+			Magnitude: previous.Magnitude,
+		}
+	}
+	return payload
+}
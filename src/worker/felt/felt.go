@@ -0,0 +1,132 @@
+package felt
+
+import (
+	"bmkg/src/domain"
+	"bmkg/src/repository"
+	"bmkg/src/utils"
+	"context"
+	"log"
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	// aggregateInterval is how often new reports are turned into community intensity
+	aggregateInterval = 5 * time.Minute
+	// stateKey stores the time of the last aggregation in worker_state
+	stateKey = "felt_aggregated_at"
+)
+
+// Worker aggregates the "did you feel it?" reports into community intensity per geohash cell
+type Worker struct {
+	repo       *repository.Felt
+	state      *repository.State
+	ctx        context.Context
+	cancelFunc context.CancelFunc
+}
+
+// NewWorker creates a new instance of Worker
+func NewWorker(repo *repository.Felt, state *repository.State) *Worker {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Worker{
+		repo:       repo,
+		state:      state,
+		ctx:        ctx,
+		cancelFunc: cancel,
+	}
+}
+
+// StartWorker starts the periodic aggregation
+func (w *Worker) StartWorker() {
+	log.Println("Starting felt report worker...")
+	go w.run()
+}
+
+// StopWorker gracefully stops the worker
+func (w *Worker) StopWorker() {
+	log.Println("Stopping felt report worker...")
+	if w.cancelFunc != nil {
+		w.cancelFunc()
+	}
+}
+
+func (w *Worker) run() {
+	ticker := time.NewTicker(aggregateInterval)
+	defer ticker.Stop()
+
+	w.aggregate()
+
+	for {
+		select {
+		case <-ticker.C:
+			w.aggregate()
+		case <-w.ctx.Done():
+			return
+		}
+	}
+}
+
+// aggregate recomputes the earthquakes that received reports since the last run
+func (w *Worker) aggregate() {
+	var since time.Time
+	if _, err := w.state.Load(stateKey, &since); err != nil {
+		log.Printf("Error loading felt aggregation state: %v", err)
+		return
+	}
+
+	// reports saved while this run is busy are picked up by the next one
+	started := time.Now()
+
+	earthquakes, err := w.repo.ReportedSince(since)
+	if err != nil {
+		log.Printf("Error loading reported earthquakes: %v", err)
+		return
+	}
+
+	for _, earthquakeID := range earthquakes {
+		if err := w.aggregateEarthquake(earthquakeID); err != nil {
+			log.Printf("Error aggregating felt reports of %s: %v", earthquakeID, err)
+			return
+		}
+	}
+
+	if err := w.state.Save(stateKey, started); err != nil {
+		log.Printf("Error saving felt aggregation state: %v", err)
+	}
+}
+
+// aggregateEarthquake recomputes the community intensity of one earthquake
+func (w *Worker) aggregateEarthquake(earthquakeID string) error {
+	reports, err := w.repo.Reports(earthquakeID)
+	if err != nil {
+		return err
+	}
+
+	cells := make(map[string]*domain.FeltCell)
+	for _, report := range reports {
+		cell, ok := cells[report.Geohash()]
+		if !ok {
+			minLat, minLon, maxLat, maxLon := utils.GeohashBounds(report.Geohash())
+			cell = &domain.FeltCell{Geohash: report.Geohash(), Lat: (minLat + maxLat) / 2, Lon: (minLon + maxLon) / 2}
+			cells[report.Geohash()] = cell
+		}
+		cell.MMI += report.Mmi()
+		cell.Reports++
+	}
+
+	result := make([]domain.FeltCell, 0, len(cells))
+	for _, cell := range cells {
+		cell.MMI = math.Round(cell.MMI/float64(cell.Reports)*10) / 10
+		result = append(result, *cell)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Geohash < result[j].Geohash })
+
+	if err := w.repo.ReplaceIntensity(earthquakeID, result); err != nil {
+		return err
+	}
+
+	log.Printf("Aggregated %d felt reports of %s into %d cells", len(reports), earthquakeID, len(result))
+	return nil
+}
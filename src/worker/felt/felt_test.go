@@ -0,0 +1,110 @@
+package felt
+
+import (
+	"math"
+	"strconv"
+	"testing"
+	"time"
+
+	_ "bmkg/migrations"
+	"bmkg/src/db"
+	"bmkg/src/domain"
+	"bmkg/src/repository"
+	"bmkg/src/utils"
+
+	"github.com/pocketbase/pocketbase"
+)
+
+func TestAggregateEarthquake(t *testing.T) {
+	app := pocketbase.NewWithConfig(pocketbase.Config{DefaultDataDir: t.TempDir()})
+	if err := app.Bootstrap(); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.RunAllMigrations(); err != nil {
+		t.Fatal(err)
+	}
+
+	earthquakeID, err := repository.NewBMKGRepository(app).SaveGempa(map[string]interface{}{
+		"MagnitudeText": "5.6",
+		"Wilayah":       "Pusat gempa berada di darat 10 km Tenggara Sukabumi",
+		"magnitude":     5.6,
+		"origin_time":   time.Now().UTC(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// reports inside two cells around Sukabumi and Bandung, a few hundred metres apart
+	reports := []struct {
+		lat, lon float64
+		answers  domain.FeltAnswers
+	}{
+		{-6.9210, 106.9270, domain.FeltAnswers{Felt: true, Shaking: "strong"}},   // V
+		{-6.9215, 106.9275, domain.FeltAnswers{Felt: true, Effect: "damage"}},    // VII
+		{-6.9212, 106.9272, domain.FeltAnswers{Felt: true, Shaking: "moderate"}}, // IV
+		{-6.9150, 107.6090, domain.FeltAnswers{}},                                // I
+		{-6.9155, 107.6095, domain.FeltAnswers{Felt: true, Shaking: "weak"}},     // III
+		{-6.9152, 107.6092, domain.FeltAnswers{Felt: true}},                      // II
+		{-6.9151, 107.6091, domain.FeltAnswers{Felt: true, Effect: "swinging"}},  // IV
+	}
+	// mean intensity per cell, rounded to one decimal
+	want := map[string]struct {
+		mmi     float64
+		reports int
+	}{
+		utils.EncodeGeohash(-6.9210, 106.9270, repository.FeltGeohashPrecision): {5.3, 3}, // (5+7+4)/3
+		utils.EncodeGeohash(-6.9150, 107.6090, repository.FeltGeohashPrecision): {2.5, 4}, // (1+3+2+4)/4
+	}
+
+	feltRepo := repository.NewFeltRepository(app)
+	subscribers := repository.NewSubscriberRepository(app)
+	for i, report := range reports {
+		subscriber, err := subscribers.Ensure(strconv.Itoa(1000+i), db.Telegram)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := feltRepo.SaveReport(subscriber, earthquakeID, report.answers, report.lat, report.lon); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	w := NewWorker(feltRepo, repository.NewStateRepository(app))
+	// a second run replaces the cells of the first one
+	for run := 0; run < 2; run++ {
+		if err := w.aggregateEarthquake(earthquakeID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cells, err := feltRepo.Intensity(earthquakeID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cells) != len(want) {
+		t.Fatalf("got %d cells, want %d", len(cells), len(want))
+	}
+
+	cellLat, cellLon := utils.GeohashCellSize(repository.FeltGeohashPrecision)
+	for _, cell := range cells {
+		expected, ok := want[cell.Geohash()]
+		if !ok {
+			t.Errorf("unexpected cell %s", cell.Geohash())
+			continue
+		}
+		if cell.Mmi() != expected.mmi || cell.Reports() != expected.reports {
+			t.Errorf("cell %s: MMI %v from %d reports, want %v from %d", cell.Geohash(), cell.Mmi(), cell.Reports(), expected.mmi, expected.reports)
+		}
+
+		// the centre lies in the middle of the cell, not at one of the reports
+		minLat, minLon, maxLat, maxLon := utils.GeohashBounds(cell.Geohash())
+		if math.Abs(maxLat-minLat-cellLat) > 1e-9 || math.Abs(maxLon-minLon-cellLon) > 1e-9 {
+			t.Fatalf("cell %s bounds are %v x %v, want %v x %v", cell.Geohash(), maxLat-minLat, maxLon-minLon, cellLat, cellLon)
+		}
+		if math.Abs(cell.Lat()-(minLat+maxLat)/2) > 1e-9 || math.Abs(cell.Lon()-(minLon+maxLon)/2) > 1e-9 {
+			t.Errorf("cell %s centre is %v,%v, want %v,%v", cell.Geohash(), cell.Lat(), cell.Lon(), (minLat+maxLat)/2, (minLon+maxLon)/2)
+		}
+		if got := utils.EncodeGeohash(cell.Lat(), cell.Lon(), repository.FeltGeohashPrecision); got != cell.Geohash() {
+			t.Errorf("centre of cell %s encodes to %s", cell.Geohash(), got)
+		}
+	}
+}
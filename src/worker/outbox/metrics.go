@@ -0,0 +1,120 @@
+package outbox
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// latencySamples is how many recent time-to-deliver samples are kept per channel
+const latencySamples = 1000
+
+// ChannelMetrics is a snapshot of the delivery of one channel
+type ChannelMetrics struct {
+	Channel string `json:"channel"`
+	Workers int    `json:"workers"`
+	// Queued deliveries are claimed and waiting for a worker, Pending ones wait in the outbox
+	Queued  int   `json:"queued"`
+	Pending int   `json:"pending"`
+	Sent    int64 `json:"sent"`
+	Retried int64 `json:"retried"`
+	Failed  int64 `json:"failed"`
+	Expired int64 `json:"expired"`
+	// Time from planning to delivery over the recent sent deliveries, in milliseconds
+	DeliverP50Ms int64 `json:"deliver_p50_ms"`
+	DeliverP95Ms int64 `json:"deliver_p95_ms"`
+	DeliverMaxMs int64 `json:"deliver_max_ms"`
+}
+
+type channelMetrics struct {
+	mu        sync.Mutex
+	sent      int64
+	retried   int64
+	failed    int64
+	expired   int64
+	latencies []time.Duration
+	next      int
+}
+
+func newChannelMetrics() *channelMetrics {
+	return &channelMetrics{latencies: make([]time.Duration, 0, latencySamples)}
+}
+
+func (m *channelMetrics) observeSent(timeToDeliver time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sent++
+	if len(m.latencies) < latencySamples {
+		m.latencies = append(m.latencies, timeToDeliver)
+		return
+	}
+	m.latencies[m.next] = timeToDeliver
+	m.next = (m.next + 1) % latencySamples
+}
+
+func (m *channelMetrics) observe(status string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	switch status {
+	case statusPending:
+		m.retried++
+	case statusFailed:
+		m.failed++
+	case statusExpired:
+		m.expired++
+	}
+}
+
+func (m *channelMetrics) snapshot(out *ChannelMetrics) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	out.Sent = m.sent
+	out.Retried = m.retried
+	out.Failed = m.failed
+	out.Expired = m.expired
+
+	if len(m.latencies) == 0 {
+		return
+	}
+
+	sorted := make([]time.Duration, len(m.latencies))
+	copy(sorted, m.latencies)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	out.DeliverP50Ms = sorted[len(sorted)/2].Milliseconds()
+	out.DeliverP95Ms = sorted[len(sorted)*95/100].Milliseconds()
+	out.DeliverMaxMs = sorted[len(sorted)-1].Milliseconds()
+}
+
+// Metrics returns a snapshot of every channel, including the pending count from the outbox
+func (w *Worker) Metrics() ([]ChannelMetrics, error) {
+	pending, err := w.repo.CountPending()
+	if err != nil {
+		return nil, err
+	}
+
+	// Pending rows of unregistered channels belong to the catch-all pool
+	for channel, count := range pending {
+		if _, ok := w.pools[channel]; !ok {
+			pending[otherChannels] += count
+		}
+	}
+
+	metrics := make([]ChannelMetrics, 0, len(w.pools))
+	for _, p := range w.pools {
+		snapshot := ChannelMetrics{
+			Channel: p.channel,
+			Workers: p.config.Workers,
+			Queued:  len(p.queue),
+			Pending: pending[p.channel],
+		}
+		p.metrics.snapshot(&snapshot)
+		metrics = append(metrics, snapshot)
+	}
+
+	sort.Slice(metrics, func(i, j int) bool { return metrics[i].Channel < metrics[j].Channel })
+	return metrics, nil
+}
@@ -0,0 +1,354 @@
+package outbox
+
+import (
+	"bmkg/src/db"
+	"bmkg/src/repository"
+	"bmkg/src/utils/notify"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/pocketbase/pocketbase/tools/types"
+	"log"
+	"math"
+	"time"
+)
+
+const (
+	// queuePerWorker is how many claimed deliveries a pool buffers per worker.
+	queuePerWorker = 25
+	// pollInterval is the time interval (seconds) for looking up due deliveries.
+	pollInterval = 5
+	// baseBackoff is the delay (seconds) before the first retry, doubled after every attempt.
+	baseBackoff = 5
+	// maxBackoff caps the delay (seconds) between two attempts.
+	maxBackoff = 600
+	// maxAttempts is the number of attempts before a delivery is given up.
+	maxAttempts = 8
+)
+
+// Delivery statuses, see the notification_outbox status select
+const (
+	statusPending = "pending"
+	statusSending = "sending"
+	statusSent    = "sent"
+	statusFailed  = "failed"
+	statusExpired = "expired"
+)
+
+// Delivery is one planned notification to a single recipient
+type Delivery struct {
+	Channel      string
+	Recipient    string
+	EarthquakeID string
+	Revision     int
+	Message      notify.Message
+	// MMI and DistanceKm of the recipient decide the delivery order: strongest shaking first
+	MMI        float64
+	DistanceKm float64
+	// ExpiresAt is when the alert becomes useless, zero means it never expires
+	ExpiresAt time.Time
+	// Kind tells apart other messages about the same earthquake, e.g. a safety check-in.
+	// Empty is the alert itself.
+	Kind string
+}
+
+// DedupeKey identifies the delivery. A revision of an earthquake is a new alert,
+// anything else with the same key is the same alert and is sent only once.
+func (d Delivery) DedupeKey() string {
+	key := fmt.Sprintf("%s:%d:%s:%s", d.EarthquakeID, d.Revision, d.Channel, d.Recipient)
+	if d.Kind != "" {
+		key += ":" + d.Kind
+	}
+	return key
+}
+
+// Worker writes every planned delivery to the notification_outbox collection first
+// and delivers it afterwards with retries, so alerts survive channel outages and restarts.
+// Each channel has its own bounded, rate-limited pool so a slow provider never holds
+// back the others.
+type Worker struct {
+	repo       *repository.Outbox
+	ctx        context.Context
+	cancelFunc context.CancelFunc
+	wake       chan struct{}
+	pools      map[string]*pool
+}
+
+// NewWorker creates a new outbox worker
+func NewWorker(repo *repository.Outbox) *Worker {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Worker{
+		repo:       repo,
+		ctx:        ctx,
+		cancelFunc: cancel,
+		wake:       make(chan struct{}, 1),
+		pools:      make(map[string]*pool),
+	}
+}
+
+// Enqueue stores a delivery in the outbox and wakes the dispatcher.
+// A delivery that was already planned is ignored.
+func (w *Worker) Enqueue(delivery Delivery) error {
+	data := map[string]interface{}{
+		"dedupe_key":      delivery.DedupeKey(),
+		"channel":         delivery.Channel,
+		"recipient":       delivery.Recipient,
+		"earthquake":      delivery.EarthquakeID,
+		"payload":         delivery.Message,
+		"status":          statusPending,
+		"attempts":        0,
+		"next_attempt_at": time.Now(),
+		"mmi":             delivery.MMI,
+		"distance_km":     delivery.DistanceKm,
+	}
+	if !delivery.ExpiresAt.IsZero() {
+		data["expires_at"] = delivery.ExpiresAt
+	}
+
+	created, err := w.repo.Enqueue(data)
+	if err != nil {
+		return err
+	}
+
+	if created {
+		w.wakeDispatcher()
+	}
+
+	return nil
+}
+
+func (w *Worker) wakeDispatcher() {
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// StartWorker starts the dispatcher and a pool for every registered channel.
+// Channels must be registered in notify before the worker starts.
+func (w *Worker) StartWorker() {
+	log.Println("Starting outbox worker...")
+
+	w.recoverInterrupted()
+
+	for _, channel := range append(notify.Channels(), otherChannels) {
+		p := newPool(channel)
+		w.pools[channel] = p
+
+		for i := 0; i < p.config.Workers; i++ {
+			go w.deliverLoop(p)
+		}
+	}
+
+	go w.dispatch()
+}
+
+// StopWorker gracefully stops the outbox worker
+func (w *Worker) StopWorker() {
+	log.Println("Stopping outbox worker...")
+	if w.cancelFunc != nil {
+		w.cancelFunc()
+	}
+}
+
+// recoverInterrupted handles deliveries that were being sent when the process stopped.
+// Whether they reached the recipient is unknown, so they are not retried: sending an
+// alert twice is worse than missing one that most likely went out.
+func (w *Worker) recoverInterrupted() {
+	deliveries, err := w.repo.FindByStatus(statusSending)
+	if err != nil {
+		log.Printf("Error loading interrupted notifications: %v", err)
+		return
+	}
+
+	for _, delivery := range deliveries {
+		delivery.SetStatus(db.OutboxFailed)
+		delivery.SetLastError("interrupted while sending, not retried to avoid a duplicate")
+		if err := w.repo.Save(delivery); err != nil {
+			log.Printf("Error saving interrupted notification: %v", err)
+		}
+	}
+}
+
+// dispatch is the only goroutine claiming due deliveries, so each one is handed to
+// exactly one delivery goroutine
+func (w *Worker) dispatch() {
+	ticker := time.NewTicker(pollInterval * time.Second)
+	defer ticker.Stop()
+
+	for {
+		w.claimDue()
+
+		select {
+		case <-ticker.C:
+		case <-w.wake:
+		case <-w.ctx.Done():
+			w.releaseQueued()
+			log.Println("Outbox worker stopped")
+			return
+		}
+	}
+}
+
+// releaseQueued puts the claimed deliveries no worker picked up back into the outbox
+func (w *Worker) releaseQueued() {
+	for _, p := range w.pools {
+		for {
+			select {
+			case delivery := <-p.queue:
+				w.release(delivery)
+				continue
+			default:
+			}
+			break
+		}
+	}
+}
+
+// claimDue fills every pool up to its free capacity, highest priority first
+func (w *Worker) claimDue() {
+	registered := make([]string, 0, len(w.pools))
+	for channel := range w.pools {
+		if channel != otherChannels {
+			registered = append(registered, channel)
+		}
+	}
+
+	now := time.Now()
+	for channel, p := range w.pools {
+		free := p.free()
+		if free == 0 {
+			continue
+		}
+
+		var deliveries []*db.NotificationOutbox
+		var err error
+		if channel == otherChannels {
+			deliveries, err = w.repo.DueExcept(registered, now, free)
+		} else {
+			deliveries, err = w.repo.Due(channel, now, free)
+		}
+		if err != nil {
+			log.Printf("Error loading due %s notifications: %v", channel, err)
+			continue
+		}
+
+		for _, delivery := range deliveries {
+			delivery.SetStatus(db.OutboxSending)
+			if err := w.repo.Save(delivery); err != nil {
+				log.Printf("Error claiming notification: %v", err)
+				continue
+			}
+
+			// Only the dispatcher fills the queue, so the free capacity is still there
+			p.queue <- delivery
+		}
+	}
+}
+
+// deliverLoop is one worker of a pool. It waits for the provider quota before every send
+// and asks the dispatcher for more work once the queue runs low.
+func (w *Worker) deliverLoop(p *pool) {
+	for {
+		select {
+		case delivery := <-p.queue:
+			if len(p.queue) < cap(p.queue)/2 {
+				w.wakeDispatcher()
+			}
+
+			if err := w.waitQuota(p, delivery); err != nil {
+				w.release(delivery)
+				return
+			}
+			w.deliver(p, delivery)
+		case <-w.ctx.Done():
+			return
+		}
+	}
+}
+
+func (w *Worker) waitQuota(p *pool, delivery *db.NotificationOutbox) error {
+	if err := p.global.Wait(w.ctx); err != nil {
+		return err
+	}
+	return p.perRecipient.Wait(w.ctx, delivery.Recipient())
+}
+
+// release puts a claimed but unsent delivery back, e.g. when the worker stops
+func (w *Worker) release(delivery *db.NotificationOutbox) {
+	delivery.SetStatus(db.OutboxPending)
+	if err := w.repo.Save(delivery); err != nil {
+		log.Printf("Error releasing notification: %v", err)
+	}
+}
+
+// deliver makes one attempt and records its outcome. Failed attempts are retried with
+// exponential backoff until maxAttempts; an unknown channel or a permanent failure
+// such as an invalid phone number is never retried.
+func (w *Worker) deliver(p *pool, delivery *db.NotificationOutbox) {
+	now := time.Now()
+
+	if expiresAt := delivery.ExpiresAt(); !expiresAt.IsZero() && now.After(expiresAt.Time()) {
+		delivery.SetStatus(db.OutboxExpired)
+		p.metrics.observe(statusExpired)
+		if err := w.repo.Save(delivery); err != nil {
+			log.Printf("Error saving notification: %v", err)
+		}
+		return
+	}
+
+	var message notify.Message
+	var messageID string
+	err := delivery.UnmarshalJSONField("payload", &message)
+	if err == nil {
+		messageID, err = notify.Send(delivery.Channel(), delivery.Recipient(), message)
+	}
+	duration := time.Since(now)
+
+	attempt := delivery.Attempts() + 1
+	delivery.SetAttempts(attempt)
+
+	attemptData := map[string]interface{}{
+		"outbox":      delivery.Id,
+		"attempt":     attempt,
+		"duration_ms": duration.Milliseconds(),
+	}
+
+	if err == nil {
+		delivery.SetStatus(db.OutboxSent)
+		delivery.SetSentAt(types.NowDateTime())
+		delivery.SetLastError("")
+		delivery.SetProviderMessageId(messageID)
+		attemptData["status"] = statusSent
+		p.metrics.observeSent(time.Since(delivery.Created().Time()))
+	} else {
+		delivery.SetLastError(err.Error())
+		attemptData["status"] = statusFailed
+		attemptData["error"] = err.Error()
+
+		if attempt >= maxAttempts || errors.Is(err, notify.ErrUnknownChannel) || errors.Is(err, notify.ErrPermanent) {
+			delivery.SetStatus(db.OutboxFailed)
+			p.metrics.observe(statusFailed)
+			log.Printf("Giving up %s notification to %s after %d attempts: %v", delivery.Channel(), delivery.Recipient(), attempt, err)
+		} else {
+			next, _ := types.ParseDateTime(time.Now().Add(backoff(attempt)))
+			delivery.SetStatus(db.OutboxPending)
+			delivery.SetNextAttemptAt(next)
+			p.metrics.observe(statusPending)
+		}
+	}
+
+	if err := w.repo.Save(delivery); err != nil {
+		log.Printf("Error saving notification: %v", err)
+	}
+	if err := w.repo.SaveAttempt(attemptData); err != nil {
+		log.Printf("Error saving notification attempt: %v", err)
+	}
+}
+
+// backoff returns the delay before the next attempt after attempt failed ones
+func backoff(attempt int) time.Duration {
+	delay := baseBackoff * math.Pow(2, float64(attempt-1))
+	return time.Duration(math.Min(delay, maxBackoff)) * time.Second
+}
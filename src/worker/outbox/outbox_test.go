@@ -0,0 +1,122 @@
+package outbox
+
+import (
+	"testing"
+	"time"
+
+	_ "bmkg/migrations"
+	"bmkg/src/repository"
+	"bmkg/src/utils/notify"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// newTestApp returns a migrated scratch PocketBase app
+func newTestApp(t *testing.T) core.App {
+	t.Helper()
+
+	app := pocketbase.NewWithConfig(pocketbase.Config{DefaultDataDir: t.TempDir()})
+	if err := app.Bootstrap(); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.RunAllMigrations(); err != nil {
+		t.Fatal(err)
+	}
+	return app
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, 5 * time.Second},
+		{2, 10 * time.Second},
+		{3, 20 * time.Second},
+		{4, 40 * time.Second},
+		{5, 80 * time.Second},
+		{6, 160 * time.Second},
+		{7, 320 * time.Second},
+		// 640s is capped at maxBackoff
+		{8, 600 * time.Second},
+		{20, 600 * time.Second},
+		// large attempts must not overflow past the cap
+		{200, 600 * time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := backoff(tt.attempt); got != tt.want {
+			t.Errorf("backoff(%d) = %s, want %s", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestEnqueueSkipsDuplicateDedupeKey(t *testing.T) {
+	app := newTestApp(t)
+	w := NewWorker(repository.NewOutboxRepository(app))
+
+	delivery := Delivery{
+		Channel:   "wa",
+		Recipient: "+6281234567890",
+		Revision:  1,
+		Message:   notify.Message{Text: "Gempa M5.0"},
+		MMI:       5,
+	}
+
+	// enqueue counts the outbox rows with the dedupe key of d after enqueueing it
+	enqueue := func(d Delivery) int {
+		t.Helper()
+		if err := w.Enqueue(d); err != nil {
+			t.Fatal(err)
+		}
+		total, err := app.CountRecords("notification_outbox", dbx.HashExp{"dedupe_key": d.DedupeKey()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return int(total)
+	}
+
+	if got := enqueue(delivery); got != 1 {
+		t.Fatalf("first Enqueue stored %d deliveries, want 1", got)
+	}
+	select {
+	case <-w.wake:
+	default:
+		t.Error("first Enqueue did not wake the dispatcher")
+	}
+
+	// the same alert planned again, e.g. by a second feed, is ignored
+	again := delivery
+	again.Message = notify.Message{Text: "Gempa M5.0 (ulang)"}
+	if got := enqueue(again); got != 1 {
+		t.Errorf("duplicate Enqueue stored %d deliveries, want 1", got)
+	}
+	select {
+	case <-w.wake:
+		t.Error("duplicate Enqueue woke the dispatcher")
+	default:
+	}
+
+	// a revision, another kind or another recipient is a new delivery
+	revision := delivery
+	revision.Revision = 2
+	checkin := delivery
+	checkin.Kind = "checkin"
+	other := delivery
+	other.Recipient = "+6289876543210"
+	for _, d := range []Delivery{revision, checkin, other} {
+		if got := enqueue(d); got != 1 {
+			t.Errorf("Enqueue(%s) stored %d deliveries, want 1", d.DedupeKey(), got)
+		}
+	}
+
+	total, err := app.CountRecords("notification_outbox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 4 {
+		t.Errorf("outbox has %d deliveries, want 4", total)
+	}
+}
@@ -0,0 +1,66 @@
+package outbox
+
+import (
+	"bmkg/src/db"
+	"bmkg/src/utils"
+	"time"
+)
+
+// ChannelConfig bounds the delivery of one channel to what its provider allows
+type ChannelConfig struct {
+	// Workers is the number of goroutines sending concurrently
+	Workers int
+	// Rate is the global number of messages per second, Burst how many may go at once
+	Rate  float64
+	Burst int
+	// PerRecipient is the minimum interval between two messages to the same recipient
+	PerRecipient time.Duration
+}
+
+// channelConfigs provider quotas per channel. Telegram allows about 30 messages per second
+// overall and 1 per second per chat; the WhatsApp Cloud API one per 6 seconds per user.
+var channelConfigs = map[string]ChannelConfig{
+	"telegram": {Workers: 8, Rate: 30, Burst: 30, PerRecipient: time.Second},
+	"wa":       {Workers: 8, Rate: 50, Burst: 50, PerRecipient: 6 * time.Second},
+	"mqtt":     {Workers: 4, Rate: 200, Burst: 200},
+	"android":  {Workers: 4, Rate: 200, Burst: 200},
+	"email":    {Workers: 4, Rate: 10, Burst: 10},
+	"webhook":  {Workers: 4, Rate: 50, Burst: 50, PerRecipient: 100 * time.Millisecond},
+}
+
+// defaultChannelConfig is used for channels without their own config
+var defaultChannelConfig = ChannelConfig{Workers: 2, Rate: 10, Burst: 10}
+
+// otherChannels is the pool for deliveries of unregistered channels, which only fail
+const otherChannels = ""
+
+// pool delivers the deliveries of one channel
+type pool struct {
+	channel      string
+	config       ChannelConfig
+	queue        chan *db.NotificationOutbox
+	global       *utils.TokenBucket
+	perRecipient *utils.KeyedInterval
+	metrics      *channelMetrics
+}
+
+func newPool(channel string) *pool {
+	config, ok := channelConfigs[channel]
+	if !ok {
+		config = defaultChannelConfig
+	}
+
+	return &pool{
+		channel:      channel,
+		config:       config,
+		queue:        make(chan *db.NotificationOutbox, config.Workers*queuePerWorker),
+		global:       utils.NewTokenBucket(config.Rate, config.Burst),
+		perRecipient: utils.NewKeyedInterval(config.PerRecipient),
+		metrics:      newChannelMetrics(),
+	}
+}
+
+// free returns how many deliveries the pool can take without blocking the dispatcher
+func (p *pool) free() int {
+	return cap(p.queue) - len(p.queue)
+}
@@ -0,0 +1,237 @@
+package safety
+
+import (
+	"bmkg/src/db"
+	"bmkg/src/domain"
+	"bmkg/src/messages"
+	"bmkg/src/repository"
+	"bmkg/src/utils/ngitung"
+	"bmkg/src/utils/notify"
+	"bmkg/src/worker/outbox"
+	"context"
+	"fmt"
+	"github.com/pocketbase/pocketbase/core"
+	"log"
+	"strings"
+	"time"
+)
+
+const (
+	// remindInterval is how often unanswered check-ins are looked up
+	remindInterval = time.Minute
+	// remindAfter is the time without answer before a reminder, and between two reminders
+	remindAfter = 15 * time.Minute
+	// maxReminders is the number of automatic reminders of one check-in
+	maxReminders = 3
+	// remindWindow stops reminding this long after the earthquake
+	remindWindow = 6 * time.Hour
+	// minRemindGap keeps a reminder requested by the owner from repeating a message just sent
+	minRemindGap = 5 * time.Minute
+	// kindCheckin is the outbox Delivery.Kind of the question, reminders append their number
+	kindCheckin = "checkin"
+)
+
+// checkinMMI is the estimated intensity at which a group member is asked to check in
+var checkinMMI = 6.0
+
+// SetCheckinMMI replaces the check-in threshold. Zero keeps the default MMI VI.
+func SetCheckinMMI(mmi float64) {
+	if mmi > 0 {
+		checkinMMI = mmi
+	}
+}
+
+// CheckinMMI returns the estimated intensity at which a check-in is opened
+func CheckinMMI() float64 {
+	return checkinMMI
+}
+
+// checkinData is the data of the checkin message template
+type checkinData struct {
+	Magnitude float64
+	Wilayah   string
+	MMI       float64
+	MMIRoman  string
+	// URL is the check-in page, channels without buttons answer there
+	URL      string
+	Reminder bool
+}
+
+// Worker asks group members whether they are safe after a strong earthquake and reminds
+// the ones that did not answer. The questions go through the outbox like the alerts.
+type Worker struct {
+	app         core.App
+	repo        *repository.Safety
+	earthquakes *repository.BMKG
+	subscribers *repository.Subscriber
+	outbox      *outbox.Worker
+	ctx         context.Context
+	cancelFunc  context.CancelFunc
+}
+
+// NewWorker creates a new instance of Worker
+func NewWorker(app core.App, repo *repository.Safety, earthquakes *repository.BMKG, subscribers *repository.Subscriber, deliveries *outbox.Worker) *Worker {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Worker{
+		app:         app,
+		repo:        repo,
+		earthquakes: earthquakes,
+		subscribers: subscribers,
+		outbox:      deliveries,
+		ctx:         ctx,
+		cancelFunc:  cancel,
+	}
+}
+
+// StartWorker starts the reminder loop
+func (w *Worker) StartWorker() {
+	log.Println("Starting safety check-in worker...")
+	go w.run()
+}
+
+// StopWorker gracefully stops the worker
+func (w *Worker) StopWorker() {
+	log.Println("Stopping safety check-in worker...")
+	if w.cancelFunc != nil {
+		w.cancelFunc()
+	}
+}
+
+// Open asks the subscriber to check in after an earthquake estimated at mmi. Only members
+// of a group are asked, and only once per earthquake: a revision keeps the open check-in.
+func (w *Worker) Open(earthquakeID string, subscriber *db.UserNotify, mmi float64) error {
+	member, err := w.repo.IsMember(subscriber.Id)
+	if err != nil || !member {
+		return err
+	}
+
+	checkin, created, err := w.repo.OpenCheckin(earthquakeID, subscriber.Id, mmi)
+	if err != nil || !created {
+		return err
+	}
+
+	return w.send(checkin, subscriber, kindCheckin)
+}
+
+// Remind asks the subscribers of the unanswered check-ins again. Check-ins whose last
+// message is more recent than minRemindGap are skipped. It returns the number of reminders.
+// These reminders are requested by the owner and do not count toward maxReminders.
+func (w *Worker) Remind(checkins []*db.SafetyCheckin) (int, error) {
+	sent := 0
+	for _, checkin := range checkins {
+		if checkin.GetString("status") != domain.CheckinPending {
+			continue
+		}
+		if time.Since(lastMessage(checkin)) < minRemindGap {
+			continue
+		}
+
+		kind := fmt.Sprintf("%s-manual-%d", kindCheckin, time.Now().Unix())
+		if err := w.remind(checkin, kind); err != nil {
+			return sent, err
+		}
+		if err := w.repo.MarkNudged(checkin); err != nil {
+			return sent, err
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+func (w *Worker) run() {
+	ticker := time.NewTicker(remindInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			w.remindDue()
+		case <-w.ctx.Done():
+			return
+		}
+	}
+}
+
+// remindDue sends the automatic reminders
+func (w *Worker) remindDue() {
+	now := time.Now()
+	checkins, err := w.repo.DueReminders(now.Add(-remindAfter), now.Add(-remindWindow), maxReminders)
+	if err != nil {
+		log.Printf("Error loading due check-ins: %v", err)
+		return
+	}
+
+	for _, checkin := range checkins {
+		// the outbox sends a delivery only once, every reminder has its own kind
+		kind := fmt.Sprintf("%s-reminder-%d", kindCheckin, checkin.Reminders()+1)
+		if err := w.remind(checkin, kind); err != nil {
+			log.Printf("Error reminding check-in %s: %v", checkin.Id, err)
+			continue
+		}
+		if err := w.repo.MarkReminded(checkin); err != nil {
+			log.Printf("Error reminding check-in %s: %v", checkin.Id, err)
+		}
+	}
+}
+
+// remind asks the subscriber of the check-in again
+func (w *Worker) remind(checkin *db.SafetyCheckin, kind string) error {
+	subscriber, err := w.subscribers.Find(checkin.GetString("subscriber"))
+	if err != nil {
+		return err
+	}
+
+	return w.send(checkin, subscriber, kind)
+}
+
+// send plans the check-in question over the channel of the subscriber
+func (w *Worker) send(checkin *db.SafetyCheckin, subscriber *db.UserNotify, kind string) error {
+	earthquake, err := w.earthquakes.GetEarthquake(checkin.GetString("earthquake"))
+	if err != nil {
+		return err
+	}
+
+	channel := subscriber.GetString("type")
+	selector := messages.Selector{Channel: channel, Language: subscriber.GetString("language")}
+	text, err := messages.Render(messages.KeyCheckin, selector, checkinData{
+		Magnitude: earthquake.Magnitude(),
+		Wilayah:   earthquake.Wilayah(),
+		MMI:       checkin.Mmi(),
+		MMIRoman:  ngitung.MMIRoman(checkin.Mmi()),
+		URL:       CheckinURL(w.app, checkin.Token()),
+		Reminder:  kind != kindCheckin,
+	})
+	if err != nil {
+		return err
+	}
+
+	return w.outbox.Enqueue(outbox.Delivery{
+		Channel:      channel,
+		Recipient:    subscriber.Identifier(),
+		EarthquakeID: earthquake.Id,
+		Kind:         kind,
+		Message: notify.Message{
+			Text:         text,
+			Priority:     notify.PriorityHigh,
+			EarthquakeID: earthquake.Id,
+			Language:     selector.Language,
+			CheckinToken: checkin.Token(),
+		},
+		MMI:       checkin.Mmi(),
+		ExpiresAt: checkin.Created().Time().Add(remindWindow),
+	})
+}
+
+// CheckinURL is the page where the check-in of token is answered
+func CheckinURL(app core.App, token string) string {
+	return strings.TrimRight(app.Settings().Meta.AppURL, "/") + "/checkin/" + token
+}
+
+// lastMessage is when the subscriber was last asked to check in
+func lastMessage(checkin *db.SafetyCheckin) time.Time {
+	if !checkin.RemindedAt().IsZero() {
+		return checkin.RemindedAt().Time()
+	}
+	return checkin.Created().Time()
+}
@@ -0,0 +1,152 @@
+package safety
+
+import (
+	"testing"
+	"time"
+
+	_ "bmkg/migrations"
+	"bmkg/src/db"
+	"bmkg/src/repository"
+	"bmkg/src/worker/outbox"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// newTestCheckin opens a check-in for a group member on a migrated scratch app
+func newTestCheckin(t *testing.T) (*Worker, core.App, string) {
+	t.Helper()
+
+	app := pocketbase.NewWithConfig(pocketbase.Config{DefaultDataDir: t.TempDir()})
+	if err := app.Bootstrap(); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.RunAllMigrations(); err != nil {
+		t.Fatal(err)
+	}
+
+	users, err := app.FindCollectionByNameOrId("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	owner := core.NewRecord(users)
+	owner.SetEmail("owner@example.com")
+	owner.SetPassword("password123")
+	if err := app.Save(owner); err != nil {
+		t.Fatal(err)
+	}
+
+	safetyRepo := repository.NewSafetyRepository(app)
+	subscribers := repository.NewSubscriberRepository(app)
+	earthquakes := repository.NewBMKGRepository(app)
+
+	group, err := safetyRepo.CreateGroup(owner.Id, "Kantor")
+	if err != nil {
+		t.Fatal(err)
+	}
+	subscriber, err := subscribers.Ensure("123", db.Telegram)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := safetyRepo.JoinGroup(group.InviteCode(), subscriber, "Budi"); err != nil {
+		t.Fatal(err)
+	}
+
+	earthquakeID, err := earthquakes.SaveGempa(map[string]interface{}{
+		"MagnitudeText": "6.5",
+		"Wilayah":       "Pusat gempa berada di laut 20 km BaratDaya Sukabumi",
+		"magnitude":     6.5,
+		"origin_time":   time.Now().UTC(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	worker := NewWorker(app, safetyRepo, earthquakes, subscribers, outbox.NewWorker(repository.NewOutboxRepository(app)))
+	if err := worker.Open(earthquakeID, subscriber, 6); err != nil {
+		t.Fatal(err)
+	}
+
+	checkin, err := safetyRepo.FindCheckin(earthquakeID, subscriber.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return worker, app, checkin.Id
+}
+
+// backdate moves the check-in and its last message ago into the past
+func backdate(t *testing.T, app core.App, checkinID string, ago time.Duration) {
+	t.Helper()
+
+	at := types.NowDateTime().Add(-ago).String()
+	_, err := app.DB().Update("safety_checkin", dbx.Params{"created": at, "reminded_at": at}, dbx.HashExp{"id": checkinID}).Execute()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func findCheckin(t *testing.T, app core.App, checkinID string) *db.SafetyCheckin {
+	t.Helper()
+
+	checkin := &db.SafetyCheckin{}
+	if err := app.RecordQuery("safety_checkin").AndWhere(dbx.HashExp{"id": checkinID}).One(checkin); err != nil {
+		t.Fatal(err)
+	}
+	return checkin
+}
+
+func deliveries(t *testing.T, app core.App) int {
+	t.Helper()
+
+	count, err := app.CountRecords("notification_outbox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return int(count)
+}
+
+func TestManualRemindersDoNotUseAutomaticOnes(t *testing.T) {
+	worker, app, checkinID := newTestCheckin(t)
+
+	for i := 1; i <= maxReminders+1; i++ {
+		backdate(t, app, checkinID, minRemindGap+time.Minute)
+
+		sent, err := worker.Remind([]*db.SafetyCheckin{findCheckin(t, app, checkinID)})
+		if err != nil || sent != 1 {
+			t.Fatalf("manual reminder %d: sent %d, %v", i, sent, err)
+		}
+		// different second, so every manual reminder is a delivery of its own
+		time.Sleep(time.Second)
+	}
+
+	if reminders := findCheckin(t, app, checkinID).Reminders(); reminders != 0 {
+		t.Errorf("manual reminders counted as automatic: %d", reminders)
+	}
+	if got, want := deliveries(t, app), maxReminders+2; got != want {
+		t.Errorf("got %d deliveries, want %d", got, want)
+	}
+
+	// a manual reminder spaces the automatic ones
+	sent, err := worker.Remind([]*db.SafetyCheckin{findCheckin(t, app, checkinID)})
+	if err != nil || sent != 0 {
+		t.Errorf("reminder within minRemindGap: sent %d, %v", sent, err)
+	}
+	worker.remindDue()
+	if reminders := findCheckin(t, app, checkinID).Reminders(); reminders != 0 {
+		t.Errorf("automatic reminder right after a manual one")
+	}
+
+	// every automatic reminder is still sent
+	for i := 1; i <= maxReminders+1; i++ {
+		backdate(t, app, checkinID, remindAfter+time.Minute)
+		worker.remindDue()
+	}
+	if reminders := findCheckin(t, app, checkinID).Reminders(); reminders != maxReminders {
+		t.Errorf("got %d automatic reminders, want %d", reminders, maxReminders)
+	}
+	if got, want := deliveries(t, app), 2*maxReminders+2; got != want {
+		t.Errorf("got %d deliveries, want %d", got, want)
+	}
+}
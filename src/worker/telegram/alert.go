@@ -0,0 +1,170 @@
+package telegram
+
+import (
+	"bmkg/src/messages"
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"net/http"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// captionMaxLength batas panjang caption foto Telegram
+const captionMaxLength = 1024
+
+// Actions of the inline buttons below an alert, the callback data is "action:earthquake id".
+// The questionnaire of actionFelt appends the answers, see handleFelt.
+const (
+	actionSafe   = "aman"
+	actionFelt   = "terasa"
+	actionDetail = "detail"
+)
+
+// Alert is an earthquake alert for one chat
+type Alert struct {
+	Text string
+	// PhotoURL is the shakemap, sent as a photo with Text as caption
+	PhotoURL string
+	// Epicenter linked on a map, only when HasLocation is set
+	HasLocation bool
+	Lat         float64
+	Lon         float64
+	// EarthquakeID adds the action buttons, Language words them
+	EarthquakeID string
+	Language     string
+}
+
+// SendAlert sends the shakemap with the alert as caption and a link to the epicenter on
+// a map. Every alert is a single Bot API call, so the per-chat pacing of the outbox keeps
+// a chat within the Telegram limit. When Telegram cannot fetch the photo the alert is sent
+// as a text message instead.
+func (b *Bot) SendAlert(chatID int64, alert Alert) error {
+	if err := b.sendAlertMessage(chatID, alert); err != nil {
+		return fmt.Errorf("failed to send alert to %d: %w", chatID, err)
+	}
+	return nil
+}
+
+func (b *Bot) sendAlertMessage(chatID int64, alert Alert) error {
+	keyboard := b.alertKeyboard(alert)
+	text := formatAlert(alert.Text)
+	visible := alert.Text
+
+	if alert.HasLocation {
+		title := b.text(alert.Language, messages.KeyBotEpicenter, nil)
+		text += fmt.Sprintf("\n\n<a href=\"%s\">%s</a>", epicenterURL(alert.Lat, alert.Lon), html.EscapeString(title))
+		visible += "\n\n" + title
+	}
+
+	// a long alert goes as text with the shakemap as link preview instead of a caption
+	if alert.PhotoURL != "" && len([]rune(visible)) <= captionMaxLength {
+		photo := tgbotapi.NewPhoto(chatID, tgbotapi.FileURL(alert.PhotoURL))
+		photo.Caption = text
+		photo.ParseMode = tgbotapi.ModeHTML
+		photo.ReplyMarkup = keyboard
+
+		_, err := b.api.Send(photo)
+		if err == nil || !isBadRequest(err) {
+			return err
+		}
+		// the shakemap is not published yet or cannot be downloaded by Telegram, a
+		// rejected request is not a sent message
+		log.Printf("Error sending shakemap %s to %d, sending text: %v", alert.PhotoURL, chatID, err)
+	} else if alert.PhotoURL != "" {
+		text = fmt.Sprintf("<a href=\"%s\">\u200b</a>", html.EscapeString(alert.PhotoURL)) + text
+	}
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = tgbotapi.ModeHTML
+	msg.ReplyMarkup = keyboard
+	_, err := b.api.Send(msg)
+	return err
+}
+
+// epicenterURL shows the epicenter on a map
+func epicenterURL(lat, lon float64) string {
+	return fmt.Sprintf("https://www.google.com/maps?q=%f,%f", lat, lon)
+}
+
+// alertKeyboard creates the "Saya aman", "Terasa?" and "Detail" buttons, or nil without an earthquake
+func (b *Bot) alertKeyboard(alert Alert) interface{} {
+	if alert.EarthquakeID == "" {
+		return nil
+	}
+
+	button := func(key, action string) tgbotapi.InlineKeyboardButton {
+		return tgbotapi.NewInlineKeyboardButtonData(b.text(alert.Language, key, nil), action+":"+alert.EarthquakeID)
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			button(messages.KeyBotButtonSafe, actionSafe),
+			button(messages.KeyBotButtonFelt, actionFelt),
+			button(messages.KeyBotButtonDetail, actionDetail),
+		),
+	)
+}
+
+// handleCallback handles a press on one of the alert buttons
+func (b *Bot) handleCallback(query *tgbotapi.CallbackQuery) {
+	if query.Message == nil {
+		b.answerCallback(query, "")
+		return
+	}
+
+	chatID := query.Message.Chat.ID
+	language := b.chatLanguage(chatID, query.From)
+	action, earthquakeID, _ := strings.Cut(query.Data, ":")
+
+	switch action {
+	case actionSafe:
+		log.Printf("Chat %d is safe after earthquake %s", chatID, earthquakeID)
+		b.markSafe(chatID, earthquakeID)
+		b.answerCallback(query, b.text(language, messages.KeyBotSafeThanks, nil))
+	case actionFelt:
+		b.answerCallback(query, "")
+		b.handleFelt(query.Message, earthquakeID, language)
+	case actionDetail:
+		b.answerCallback(query, "")
+		b.reply(chatID, b.handleDetail(earthquakeID, language))
+	case actionCheckin:
+		b.handleCheckin(query, earthquakeID, language)
+	default:
+		b.answerCallback(query, "")
+	}
+}
+
+// handleDetail describes one earthquake and returns the reply
+func (b *Bot) handleDetail(earthquakeID string, language string) string {
+	earthquake, err := b.earthquakes.GetEarthquake(earthquakeID)
+	if err != nil {
+		log.Printf("Error loading earthquake: %v", err)
+		return b.text(language, messages.KeyBotNoEarthquake, nil)
+	}
+
+	return b.text(language, messages.KeyBotDetail, earthquake)
+}
+
+// answerCallback stops the loading indicator of the button, text is shown as a notification
+func (b *Bot) answerCallback(query *tgbotapi.CallbackQuery, text string) {
+	if _, err := b.api.Request(tgbotapi.NewCallback(query.ID, text)); err != nil {
+		log.Printf("Error answering callback: %v", err)
+	}
+}
+
+// formatAlert escapes the alert for HTML parse mode and puts the first line in bold
+func formatAlert(text string) string {
+	title, rest, _ := strings.Cut(strings.TrimSpace(text), "\n")
+	formatted := "<b>" + html.EscapeString(title) + "</b>"
+	if rest != "" {
+		formatted += "\n" + html.EscapeString(rest)
+	}
+	return formatted
+}
+
+func isBadRequest(err error) bool {
+	var apiErr *tgbotapi.Error
+	return errors.As(err, &apiErr) && apiErr.Code == http.StatusBadRequest
+}
@@ -0,0 +1,126 @@
+package telegram
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	_ "bmkg/migrations"
+	"bmkg/src/messages"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// apiCall is one request the bot made to the fake Bot API
+type apiCall struct {
+	Method string
+	Params map[string]string
+}
+
+// fakeAPI stands in for api.telegram.org. Methods answer with their entry in results,
+// a message by default, or with a Bot API error when listed in failures.
+type fakeAPI struct {
+	mu       sync.Mutex
+	calls    []apiCall
+	results  map[string]string
+	failures map[string]string
+}
+
+func (f *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	method := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+
+	params := map[string]string{}
+	if err := r.ParseMultipartForm(1 << 20); err != nil {
+		r.ParseForm()
+	}
+	for key, values := range r.Form {
+		params[key] = values[0]
+	}
+
+	f.mu.Lock()
+	f.calls = append(f.calls, apiCall{Method: method, Params: params})
+	result, ok := f.results[method]
+	failure, failed := f.failures[method]
+	f.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case failed:
+		json.NewEncoder(w).Encode(map[string]interface{}{"ok": false, "error_code": 400, "description": failure})
+	case method == "getMe":
+		w.Write([]byte(`{"ok":true,"result":{"id":1,"is_bot":true,"username":"gempa_bot"}}`))
+	case ok:
+		w.Write([]byte(`{"ok":true,"result":` + result + `}`))
+	case strings.HasPrefix(method, "send"):
+		w.Write([]byte(`{"ok":true,"result":{"message_id":1,"chat":{"id":` + params["chat_id"] + `}}}`))
+	default:
+		w.Write([]byte(`{"ok":true,"result":true}`))
+	}
+}
+
+// methodCalls returns the calls of one method in order
+func (f *fakeAPI) methodCalls(method string) []apiCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var calls []apiCall
+	for _, call := range f.calls {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// sentTexts returns the text of every sendMessage call
+func (f *fakeAPI) sentTexts() []string {
+	var texts []string
+	for _, call := range f.methodCalls("sendMessage") {
+		texts = append(texts, call.Params["text"])
+	}
+	return texts
+}
+
+// reset forgets the calls made so far
+func (f *fakeAPI) reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = nil
+}
+
+// newTestBot returns a bot on a migrated scratch PocketBase app, talking to a fake Bot API
+func newTestBot(t *testing.T, config Config) (*Bot, *fakeAPI, core.App) {
+	t.Helper()
+
+	app := pocketbase.NewWithConfig(pocketbase.Config{DefaultDataDir: t.TempDir()})
+	if err := app.Bootstrap(); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.RunAllMigrations(); err != nil {
+		t.Fatal(err)
+	}
+	if err := messages.Load(app); err != nil {
+		t.Fatal(err)
+	}
+
+	fake := &fakeAPI{results: map[string]string{}, failures: map[string]string{}}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	if config.Token == "" {
+		config.Token = "TEST"
+	}
+	api, err := tgbotapi.NewBotAPIWithAPIEndpoint(config.Token, server.URL+"/bot%s/%s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fake.reset()
+
+	return newBot(app, api, config), fake, app
+}
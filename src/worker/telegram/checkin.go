@@ -0,0 +1,130 @@
+package telegram
+
+import (
+	"bmkg/src/domain"
+	"bmkg/src/messages"
+	"bmkg/src/repository"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// actionCheckin answers a safety check-in, the callback data is "checkin:token:status"
+const actionCheckin = "checkin"
+
+// Checkin is a safety check-in question for one chat
+type Checkin struct {
+	Text     string
+	Token    string
+	Language string
+}
+
+// SendCheckin asks the chat whether they are safe, answered with the "Aman" and
+// "Butuh bantuan" buttons
+func (b *Bot) SendCheckin(chatID int64, checkin Checkin) error {
+	button := func(key, status string) tgbotapi.InlineKeyboardButton {
+		return tgbotapi.NewInlineKeyboardButtonData(b.text(checkin.Language, key, nil), actionCheckin+":"+checkin.Token+":"+status)
+	}
+
+	msg := tgbotapi.NewMessage(chatID, formatAlert(checkin.Text))
+	msg.ParseMode = tgbotapi.ModeHTML
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			button(messages.KeyBotButtonCheckinSafe, domain.CheckinSafe),
+			button(messages.KeyBotButtonCheckinNeedHelp, domain.CheckinNeedHelp),
+		),
+	)
+	if _, err := b.api.Send(msg); err != nil {
+		return fmt.Errorf("failed to send check-in to %d: %w", chatID, err)
+	}
+	return nil
+}
+
+// handleCheckin records the answer to a check-in and replaces the question with it
+func (b *Bot) handleCheckin(query *tgbotapi.CallbackQuery, data string, language string) {
+	token, status, _ := strings.Cut(data, ":")
+
+	checkin, err := b.safety.FindCheckinByToken(token)
+	if err != nil {
+		if !errors.Is(err, repository.ErrCheckinNotFound) {
+			log.Printf("Error loading check-in: %v", err)
+		}
+		b.answerCallback(query, b.text(language, messages.KeyBotCheckinUnknown, nil))
+		return
+	}
+	if !domain.IsCheckinAnswer(status) {
+		b.answerCallback(query, "")
+		return
+	}
+
+	if err := b.safety.Respond(checkin, status); err != nil {
+		log.Printf("Error saving check-in: %v", err)
+		b.answerCallback(query, b.text(language, messages.KeyBotError, nil))
+		return
+	}
+
+	b.answerCallback(query, "")
+	edit := tgbotapi.NewEditMessageText(query.Message.Chat.ID, query.Message.MessageID,
+		b.text(language, messages.KeyBotCheckinRecorded, map[string]interface{}{"Status": status}))
+	if _, err := b.api.Send(edit); err != nil {
+		log.Printf("Error editing message: %v", err)
+	}
+}
+
+// markSafe records the "Saya aman" button of an alert on the open check-in of the chat, if any
+func (b *Bot) markSafe(chatID int64, earthquakeID string) {
+	subscriber, err := b.findSubscriber(chatID)
+	if err != nil || subscriber == nil {
+		return
+	}
+
+	checkin, err := b.safety.FindCheckin(earthquakeID, subscriber.Id)
+	if err != nil {
+		log.Printf("Error loading check-in: %v", err)
+		return
+	}
+	if checkin == nil {
+		return
+	}
+
+	if err := b.safety.Respond(checkin, domain.CheckinSafe); err != nil {
+		log.Printf("Error saving check-in: %v", err)
+	}
+}
+
+// handleJoinGroup adds the chat to a check-in group, "/gabung KODE [nama]", and returns the reply
+func (b *Bot) handleJoinGroup(message *tgbotapi.Message, language string) string {
+	code, name, _ := strings.Cut(strings.TrimSpace(message.CommandArguments()), " ")
+	if code == "" {
+		return b.text(language, messages.KeyBotGroupUsage, nil)
+	}
+
+	subscriber, err := b.findSubscriber(message.Chat.ID)
+	if err != nil {
+		log.Printf("Error loading subscriber: %v", err)
+		return b.text(language, messages.KeyBotError, nil)
+	}
+	if subscriber == nil {
+		return b.text(language, messages.KeyBotNoSubscriber, nil)
+	}
+
+	// without a name the group sees the Telegram name
+	name = strings.TrimSpace(name)
+	if name == "" && message.From != nil {
+		name = strings.TrimSpace(message.From.FirstName + " " + message.From.LastName)
+	}
+
+	group, err := b.safety.JoinGroup(code, subscriber, name)
+	if errors.Is(err, repository.ErrGroupNotFound) {
+		return b.text(language, messages.KeyBotGroupNotFound, nil)
+	}
+	if err != nil {
+		log.Printf("Error joining group: %v", err)
+		return b.text(language, messages.KeyBotError, nil)
+	}
+
+	return b.text(language, messages.KeyBotGroupJoined, map[string]interface{}{"Group": group.Name()})
+}
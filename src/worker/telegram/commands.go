@@ -0,0 +1,146 @@
+package telegram
+
+import (
+	"bmkg/src/messages"
+	"database/sql"
+	"errors"
+	"log"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// handleCommand routes the bot commands, see the bot.commands message template
+func (b *Bot) handleCommand(message *tgbotapi.Message, language string) {
+	switch message.Command() {
+	case "start":
+		b.handleHelpMessage(message, language)
+	case "status":
+		b.reply(message.Chat.ID, b.handleStatus(message, language))
+	case "terkini":
+		b.reply(message.Chat.ID, b.handleLatest(language))
+	case "hariini":
+		b.reply(message.Chat.ID, b.handleToday(language))
+	case "hapus":
+		b.reply(message.Chat.ID, b.handleUnsubscribe(message, language))
+	case "preferensi", "minmag", "minmmi", "radius", "sunyi", "zonawaktu", "bahasa":
+		b.reply(message.Chat.ID, b.handlePreferences(message, language))
+	case "lokasi", "hapuslokasi":
+		b.reply(message.Chat.ID, b.handleLocations(message, language))
+	case "gabung":
+		b.reply(message.Chat.ID, b.handleJoinGroup(message, language))
+	default:
+		b.showMainMenu(message, language)
+	}
+}
+
+// registerCommands publishes the command menu with setMyCommands, the default list in
+// DefaultLanguage and one list for each other language
+func (b *Bot) registerCommands() {
+	for _, language := range messages.Languages {
+		config := tgbotapi.NewSetMyCommandsWithScopeAndLanguage(tgbotapi.NewBotCommandScopeDefault(), language, b.commands(language)...)
+		if language == messages.DefaultLanguage {
+			config = tgbotapi.NewSetMyCommands(b.commands(language)...)
+		}
+
+		if _, err := b.api.Request(config); err != nil {
+			log.Printf("Error registering bot commands (%s): %v", language, err)
+		}
+	}
+}
+
+// commands parses the "command - description" lines of the bot.commands template
+func (b *Bot) commands(language string) []tgbotapi.BotCommand {
+	var commands []tgbotapi.BotCommand
+	for _, line := range messages.Lines(b.text(language, messages.KeyBotCommands, nil)) {
+		command, description, ok := strings.Cut(line, " - ")
+		if !ok {
+			continue
+		}
+		commands = append(commands, tgbotapi.BotCommand{
+			Command:     strings.TrimPrefix(strings.TrimSpace(command), "/"),
+			Description: strings.TrimSpace(description),
+		})
+	}
+	return commands
+}
+
+// commandsHelp lists the commands for the help message
+func (b *Bot) commandsHelp(language string) string {
+	var lines []string
+	for _, command := range b.commands(language) {
+		lines = append(lines, "/"+command.Command+" - "+command.Description)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// handleStatus shows the saved locations and preferences of the chat and returns the reply
+func (b *Bot) handleStatus(message *tgbotapi.Message, language string) string {
+	subscriber, err := b.findSubscriber(message.Chat.ID)
+	if err != nil {
+		log.Printf("Error loading subscriber: %v", err)
+		return b.text(language, messages.KeyBotError, nil)
+	}
+	if subscriber == nil {
+		return b.text(language, messages.KeyBotNoSubscriber, nil)
+	}
+
+	locations, err := b.subscribers.Locations(subscriber)
+	if err != nil {
+		log.Printf("Error loading locations: %v", err)
+		return b.text(language, messages.KeyBotError, nil)
+	}
+
+	return b.formatLocations(language, locations) + "\n\n" +
+		b.formatPreferences(language, b.subscribers.Preferences(subscriber))
+}
+
+// handleLatest describes the latest earthquake and returns the reply
+func (b *Bot) handleLatest(language string) string {
+	gempa, err := b.earthquakes.GetLastGempa()
+	if errors.Is(err, sql.ErrNoRows) {
+		return b.text(language, messages.KeyBotNoEarthquake, nil)
+	}
+	if err != nil {
+		log.Printf("Error loading latest earthquake: %v", err)
+		return b.text(language, messages.KeyBotError, nil)
+	}
+
+	return b.text(language, messages.KeyBotLatest, gempa)
+}
+
+// handleToday lists the earthquakes of today (WIB) and returns the reply
+func (b *Bot) handleToday(language string) string {
+	gempa, err := b.earthquakes.GetGempaHariIni()
+	if err != nil {
+		log.Printf("Error loading today's earthquakes: %v", err)
+		return b.text(language, messages.KeyBotError, nil)
+	}
+
+	return b.text(language, messages.KeyBotToday, map[string]interface{}{"Gempa": gempa})
+}
+
+// handleUnsubscribe deletes the subscription and saved locations of the chat after
+// "/hapus ya" and returns the reply
+func (b *Bot) handleUnsubscribe(message *tgbotapi.Message, language string) string {
+	subscriber, err := b.findSubscriber(message.Chat.ID)
+	if err != nil {
+		log.Printf("Error loading subscriber: %v", err)
+		return b.text(language, messages.KeyBotError, nil)
+	}
+	if subscriber == nil {
+		return b.text(language, messages.KeyBotNoSubscriber, nil)
+	}
+
+	if answer := strings.ToLower(strings.TrimSpace(message.CommandArguments())); answer != "ya" && answer != "yes" {
+		return b.text(language, messages.KeyBotUnsubscribeAsk, nil)
+	}
+
+	if err := b.subscribers.Delete(subscriber); err != nil {
+		log.Printf("Error unsubscribing chat %d: %v", message.Chat.ID, err)
+		return b.text(language, messages.KeyBotError, nil)
+	}
+	b.takePendingLocation(message.Chat.ID)
+
+	return b.text(language, messages.KeyBotUnsubscribed, nil)
+}
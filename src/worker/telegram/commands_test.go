@@ -0,0 +1,238 @@
+package telegram
+
+import (
+	"bmkg/src/messages"
+	"bmkg/src/repository"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+const testChatID = 123
+
+// send hands the bot a message of the test chat like getUpdates or the webhook would
+func send(bot *Bot, text string) {
+	message := &tgbotapi.Message{
+		Text: text,
+		Chat: &tgbotapi.Chat{ID: testChatID},
+		From: &tgbotapi.User{ID: testChatID, LanguageCode: "id"},
+	}
+	if strings.HasPrefix(text, "/") {
+		command, _, _ := strings.Cut(text, " ")
+		message.Entities = []tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: len(command)}}
+	}
+
+	bot.handleUpdate(tgbotapi.Update{Message: message})
+}
+
+// subscribe saves a location for the test chat through the share-location flow
+func subscribe(t *testing.T, bot *Bot, fake *fakeAPI, label string) {
+	t.Helper()
+
+	bot.handleUpdate(tgbotapi.Update{Message: &tgbotapi.Message{
+		Chat:     &tgbotapi.Chat{ID: testChatID},
+		From:     &tgbotapi.User{ID: testChatID},
+		Location: &tgbotapi.Location{Latitude: -6.2, Longitude: 106.8},
+	}})
+	send(bot, label)
+
+	if reply := lastReply(t, fake); !strings.Contains(reply, `Lokasi "`+label+`" telah disimpan`) {
+		t.Fatalf("location not saved: %q", reply)
+	}
+	fake.reset()
+}
+
+// lastReply returns the text of the last sendMessage call
+func lastReply(t *testing.T, fake *fakeAPI) string {
+	t.Helper()
+
+	texts := fake.sentTexts()
+	if len(texts) == 0 {
+		t.Fatal("the bot sent no message")
+	}
+	return texts[len(texts)-1]
+}
+
+// expectReply sends a command and checks that the reply contains every part
+func expectReply(t *testing.T, bot *Bot, fake *fakeAPI, command string, parts ...string) {
+	t.Helper()
+
+	fake.reset()
+	send(bot, command)
+	reply := lastReply(t, fake)
+	for _, part := range parts {
+		if !strings.Contains(reply, part) {
+			t.Errorf("%s: reply %q does not contain %q", command, reply, part)
+		}
+	}
+}
+
+func TestStartCommand(t *testing.T) {
+	bot, fake, _ := newTestBot(t, Config{})
+
+	expectReply(t, bot, fake, "/start", "Selamat datang", "/terkini - Gempa terkini", "/hapus - Berhenti berlangganan")
+
+	markup := fake.methodCalls("sendMessage")[0].Params["reply_markup"]
+	if !strings.Contains(markup, `"request_location":true`) || !strings.Contains(markup, "Kirim Lokasi") {
+		t.Errorf("start has no location keyboard: %s", markup)
+	}
+}
+
+func TestCommandsNeedSubscription(t *testing.T) {
+	bot, fake, _ := newTestBot(t, Config{})
+
+	for _, command := range []string{"/lokasi", "/status", "/radius 50", "/bahasa en", "/hapus ya"} {
+		expectReply(t, bot, fake, command, "Kirim lokasi Anda terlebih dahulu")
+	}
+}
+
+func TestLokasiCommand(t *testing.T) {
+	bot, fake, _ := newTestBot(t, Config{})
+	subscribe(t, bot, fake, "Rumah")
+	subscribe(t, bot, fake, "Kantor")
+
+	expectReply(t, bot, fake, "/lokasi", "Lokasi tersimpan:", "- Rumah (", "- Kantor (")
+	expectReply(t, bot, fake, "/hapuslokasi Kantor", "- Rumah (")
+	if reply := lastReply(t, fake); strings.Contains(reply, "- Kantor (") {
+		t.Errorf("removed location still listed: %q", reply)
+	}
+}
+
+func TestStatusCommand(t *testing.T) {
+	bot, fake, _ := newTestBot(t, Config{})
+	subscribe(t, bot, fake, "Rumah")
+
+	expectReply(t, bot, fake, "/status", "- Rumah (", "Pengaturan notifikasi Anda:", "Radius: semua jarak")
+}
+
+func TestRadiusCommand(t *testing.T) {
+	bot, fake, _ := newTestBot(t, Config{})
+	subscribe(t, bot, fake, "Rumah")
+
+	expectReply(t, bot, fake, "/radius 100", "Radius: 100 km dari lokasi tersimpan")
+
+	subscriber, err := bot.findSubscriber(testChatID)
+	if err != nil || subscriber == nil {
+		t.Fatalf("subscriber not found: %v", err)
+	}
+	if radius := bot.subscribers.Preferences(subscriber).RadiusKm; radius == nil || *radius != 100 {
+		t.Errorf("stored radius = %v, want 100", radius)
+	}
+
+	expectReply(t, bot, fake, "/radius jauh", "Tulis angka, misalnya /radius 5")
+	expectReply(t, bot, fake, "/radius off", "Radius: semua jarak")
+}
+
+func TestBahasaCommand(t *testing.T) {
+	bot, fake, _ := newTestBot(t, Config{})
+	subscribe(t, bot, fake, "Rumah")
+
+	// the new language applies to the confirmation and every later reply
+	expectReply(t, bot, fake, "/bahasa en", "Your notification settings:", "Language: en")
+	expectReply(t, bot, fake, "/status", "Saved locations:", "Your notification settings:")
+	expectReply(t, bot, fake, "/bahasa xx", "Write a language code")
+	// no wording exists for Javanese and Sundanese, so they are not offered
+	expectReply(t, bot, fake, "/bahasa jv", "Write a language code: id or en")
+}
+
+func TestHapusCommand(t *testing.T) {
+	bot, fake, _ := newTestBot(t, Config{})
+	subscribe(t, bot, fake, "Rumah")
+
+	expectReply(t, bot, fake, "/hapus", "Kirim /hapus ya untuk melanjutkan")
+	if subscriber, _ := bot.findSubscriber(testChatID); subscriber == nil {
+		t.Fatal("subscription deleted without confirmation")
+	}
+
+	expectReply(t, bot, fake, "/hapus ya", "Data Anda telah dihapus")
+	if subscriber, _ := bot.findSubscriber(testChatID); subscriber != nil {
+		t.Error("subscription still exists after /hapus ya")
+	}
+	expectReply(t, bot, fake, "/lokasi", "Kirim lokasi Anda terlebih dahulu")
+}
+
+func TestTerkiniAndHariiniCommands(t *testing.T) {
+	bot, fake, _ := newTestBot(t, Config{})
+
+	expectReply(t, bot, fake, "/terkini", "Belum ada data gempa.")
+	expectReply(t, bot, fake, "/hariini", "Belum ada gempa tercatat hari ini.")
+
+	saveEarthquake(t, bot.app, "5.2", "Pusat gempa berada di laut 20 km BaratDaya Sukabumi", time.Now())
+	saveEarthquake(t, bot.app, "4.1", "Pusat gempa berada di darat 5 km Timur Laut Palu", time.Now().Add(-48*time.Hour))
+
+	expectReply(t, bot, fake, "/terkini", "Gempa terkini M5.2", "Sukabumi")
+	expectReply(t, bot, fake, "/hariini", "Gempa hari ini (1):", "M5.2", "Sukabumi")
+	if reply := lastReply(t, fake); strings.Contains(reply, "Palu") {
+		t.Errorf("/hariini lists an earthquake of another day: %q", reply)
+	}
+}
+
+// saveEarthquake stores an earthquake like the BMKG worker does
+func saveEarthquake(t *testing.T, app core.App, magnitude, region string, origin time.Time) {
+	t.Helper()
+
+	wib := origin.In(time.FixedZone("WIB", 7*60*60))
+	_, err := repository.NewBMKGRepository(app).SaveGempa(map[string]interface{}{
+		"Tanggal":       wib.Format("02 Jan 2006"),
+		"Jam":           wib.Format("15:04:05") + " WIB",
+		"MagnitudeText": magnitude,
+		"Kedalaman":     "10 km",
+		"Wilayah":       region,
+		"Coordinates":   "-7.20,106.50",
+		"origin_time":   origin.UTC(),
+		"fingerprint":   magnitude + region,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRegisterCommands(t *testing.T) {
+	bot, fake, _ := newTestBot(t, Config{})
+
+	bot.registerCommands()
+
+	calls := fake.methodCalls("setMyCommands")
+	if len(calls) != len(messages.Languages) {
+		t.Fatalf("got %d setMyCommands calls, want one per language", len(calls))
+	}
+
+	want := map[string]string{"": "Gempa terkini", "en": "Latest earthquake"}
+	for _, call := range calls {
+		language := call.Params["language_code"]
+		description, ok := want[language]
+		if !ok {
+			t.Errorf("unexpected language_code %q", language)
+			continue
+		}
+		delete(want, language)
+
+		var commands []tgbotapi.BotCommand
+		if err := json.Unmarshal([]byte(call.Params["commands"]), &commands); err != nil {
+			t.Fatalf("commands of %q: %v", language, err)
+		}
+		if len(commands) != 11 {
+			t.Errorf("%q: got %d commands, want 11", language, len(commands))
+		}
+
+		found := false
+		for _, command := range commands {
+			if strings.HasPrefix(command.Command, "/") || strings.Contains(command.Command, " ") {
+				t.Errorf("%q: invalid command name %q", language, command.Command)
+			}
+			if command.Command == "terkini" {
+				found = command.Description == description
+			}
+		}
+		if !found {
+			t.Errorf("%q: /terkini is not described as %q", language, description)
+		}
+	}
+	if len(want) != 0 {
+		t.Errorf("no commands registered for %v", want)
+	}
+}
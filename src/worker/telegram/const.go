@@ -1,7 +1,17 @@
 package telegram
 
-// const token telegram
 const (
-	// BotToken is the token for the Telegram bot
-	token = "6367770244:AAHIB68tfaNRMf9SeXgh4jUeGm5wEVKW2KE"
+	// offsetStateKey is the worker_state key of the next long polling update offset
+	offsetStateKey = "telegram_update_offset"
+	// pollTimeout is the long polling timeout (seconds) of getUpdates
+	pollTimeout = 60
+	// pollRetryDelay is the wait (seconds) after a failed getUpdates
+	pollRetryDelay = 3
+	// webhookPath is the route prefix of the webhook, followed by a secret path segment
+	webhookPath = "/telegram/webhook/"
+	// secretTokenHeader carries the secret_token given to setWebhook
+	secretTokenHeader = "X-Telegram-Bot-Api-Secret-Token"
 )
+
+// allowedUpdates are the update types the bot handles
+var allowedUpdates = []string{"message", "callback_query"}
@@ -0,0 +1,121 @@
+package telegram
+
+import (
+	"bmkg/src/domain"
+	"bmkg/src/messages"
+	"bmkg/src/utils/ngitung"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Answers of the first question in the callback data
+const (
+	feltYes = "ya"
+	feltNo  = "tidak"
+)
+
+// handleFelt walks through the "did you feel it?" questionnaire. The answers so far travel
+// in the callback data, "terasa:<earthquake>[:ya|tidak[:<shaking>[:<effect>]]]", so the bot
+// keeps no state. The first question is a new message, the next ones replace it.
+func (b *Bot) handleFelt(message *tgbotapi.Message, data string, language string) {
+	answers := strings.Split(data, ":")
+	prefix := actionFelt + ":" + data
+
+	switch {
+	case len(answers) == 1:
+		reply := tgbotapi.NewMessage(message.Chat.ID, b.text(language, messages.KeyBotFeltAsk, nil))
+		reply.ReplyMarkup = b.feltKeyboard(language, messages.KeyBotFeltAnswers, prefix, []string{feltYes, feltNo})
+		if _, err := b.api.Send(reply); err != nil {
+			log.Printf("Error sending message: %v", err)
+		}
+	case len(answers) == 2 && answers[1] == feltYes:
+		b.editFelt(message, b.text(language, messages.KeyBotFeltShaking, nil),
+			b.feltKeyboard(language, messages.KeyBotFeltShakingOptions, prefix, domain.ShakingLevels))
+	case len(answers) == 3 && answers[1] == feltYes:
+		b.editFelt(message, b.text(language, messages.KeyBotFeltEffect, nil),
+			b.feltKeyboard(language, messages.KeyBotFeltEffectOptions, prefix, domain.Effects))
+	default:
+		b.editFelt(message, b.saveFelt(message.Chat.ID, answers, language), nil)
+	}
+}
+
+// saveFelt stores the answers at the saved place closest to the epicenter and returns the reply
+func (b *Bot) saveFelt(chatID int64, answers []string, language string) string {
+	if len(answers) > 4 || answers[1] != feltYes && answers[1] != feltNo {
+		return b.text(language, messages.KeyBotError, nil)
+	}
+
+	report := domain.FeltAnswers{Felt: answers[1] == feltYes}
+	if len(answers) == 4 {
+		report.Shaking, report.Effect = answers[2], answers[3]
+	}
+
+	subscriber, err := b.findSubscriber(chatID)
+	if err != nil {
+		log.Printf("Error loading subscriber: %v", err)
+		return b.text(language, messages.KeyBotError, nil)
+	}
+	if subscriber == nil {
+		return b.text(language, messages.KeyBotNoSubscriber, nil)
+	}
+
+	earthquake, err := b.earthquakes.GetEarthquake(answers[0])
+	if err != nil {
+		log.Printf("Error loading earthquake: %v", err)
+		return b.text(language, messages.KeyBotNoEarthquake, nil)
+	}
+
+	location, err := b.subscribers.NearestLocation(subscriber, earthquake.Lat(), earthquake.Lon())
+	if err != nil {
+		log.Printf("Error loading locations: %v", err)
+		return b.text(language, messages.KeyBotError, nil)
+	}
+	if location == nil {
+		return b.text(language, messages.KeyBotNoSubscriber, nil)
+	}
+	lat, _ := strconv.ParseFloat(location.Lintang(), 64)
+	lon, _ := strconv.ParseFloat(location.Bujur(), 64)
+
+	saved, err := b.felt.SaveReport(subscriber, earthquake.Id, report, lat, lon)
+	if err != nil {
+		if !errors.Is(err, domain.ErrInvalidFeltReport) {
+			log.Printf("Error saving felt report: %v", err)
+		}
+		return b.text(language, messages.KeyBotError, nil)
+	}
+
+	return b.text(language, messages.KeyBotFeltThanks, map[string]interface{}{
+		"Label":    location.Label(),
+		"MMIRoman": ngitung.MMIRoman(saved.Mmi()),
+	})
+}
+
+// feltKeyboard creates one button per answer, labeled by the lines of the options template
+func (b *Bot) feltKeyboard(language, key, prefix string, values []string) *tgbotapi.InlineKeyboardMarkup {
+	labels := messages.Lines(b.text(language, key, nil))
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for i, value := range values {
+		label := value
+		if i < len(labels) {
+			label = labels[i]
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(label, prefix+":"+value)))
+	}
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(rows...)
+	return &keyboard
+}
+
+// editFelt replaces the question with the next one, or with the reply when keyboard is nil
+func (b *Bot) editFelt(message *tgbotapi.Message, text string, keyboard *tgbotapi.InlineKeyboardMarkup) {
+	edit := tgbotapi.NewEditMessageText(message.Chat.ID, message.MessageID, text)
+	edit.ReplyMarkup = keyboard
+	if _, err := b.api.Send(edit); err != nil {
+		log.Printf("Error editing message: %v", err)
+	}
+}
@@ -0,0 +1,143 @@
+package telegram
+
+import (
+	"bmkg/src/db"
+	"bmkg/src/messages"
+	"bmkg/src/repository"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// pendingLocation is a location that waits for its label
+type pendingLocation struct {
+	Lat float64
+	Lon float64
+}
+
+// handleLocationMessage keeps the location and asks the user to name it
+func (b *Bot) handleLocationMessage(message *tgbotapi.Message, language string) {
+	b.pendingMu.Lock()
+	b.pending[message.Chat.ID] = pendingLocation{Lat: message.Location.Latitude, Lon: message.Location.Longitude}
+	b.pendingMu.Unlock()
+
+	reply := tgbotapi.NewMessage(message.Chat.ID, b.text(language, messages.KeyBotLocationAsk, nil))
+	reply.ReplyMarkup = b.getLabelKeyboard(language)
+
+	if _, err := b.api.Send(reply); err != nil {
+		log.Printf("Error sending message: %v", err)
+	}
+}
+
+// takePendingLocation returns and forgets the location waiting for a label of the chat
+func (b *Bot) takePendingLocation(chatID int64) (pendingLocation, bool) {
+	b.pendingMu.Lock()
+	defer b.pendingMu.Unlock()
+
+	location, ok := b.pending[chatID]
+	delete(b.pending, chatID)
+	return location, ok
+}
+
+// handleLocationLabel saves the pending location under the label the user sent
+func (b *Bot) handleLocationLabel(message *tgbotapi.Message, location pendingLocation, language string) {
+	label := strings.TrimSpace(message.Text)
+
+	var text string
+	err := b.registerUserLocation(message.Chat.ID, label, location.Lat, location.Lon)
+	switch {
+	case err == nil:
+		text = b.text(language, messages.KeyBotLocationSaved, map[string]interface{}{"Label": label})
+	case errors.Is(err, repository.ErrLocationLimit):
+		text = b.text(language, messages.KeyBotLocationLimit, map[string]interface{}{"Max": repository.MaxLocationsPerSubscriber})
+	case errors.Is(err, repository.ErrInvalidLocation):
+		text = b.text(language, messages.KeyBotLocationInvalid, nil)
+	default:
+		log.Printf("Error registering user: %v", err)
+		text = b.text(language, messages.KeyBotError, nil)
+	}
+
+	reply := tgbotapi.NewMessage(message.Chat.ID, text)
+	reply.ReplyMarkup = b.getMainMenuKeyboard(language)
+
+	if _, err := b.api.Send(reply); err != nil {
+		log.Printf("Error sending message: %v", err)
+	}
+}
+
+// registerUserLocation saves a labeled location of the chat, subscribing it on first use
+func (b *Bot) registerUserLocation(chatID int64, label string, latitude, longitude float64) error {
+	subscriber, err := b.subscribers.Ensure(strconv.FormatInt(chatID, 10), db.Telegram)
+	if err != nil {
+		return err
+	}
+
+	_, err = b.subscribers.SaveLocation(subscriber, label, latitude, longitude)
+	return err
+}
+
+// handleLocations lists or removes the saved locations of the chat and returns the reply
+func (b *Bot) handleLocations(message *tgbotapi.Message, language string) string {
+	subscriber, err := b.findSubscriber(message.Chat.ID)
+	if err != nil {
+		log.Printf("Error loading subscriber: %v", err)
+		return b.text(language, messages.KeyBotError, nil)
+	}
+	if subscriber == nil {
+		return b.text(language, messages.KeyBotNoSubscriber, nil)
+	}
+
+	if message.Command() == "hapuslokasi" {
+		label := strings.TrimSpace(message.CommandArguments())
+		if label == "" {
+			return b.text(language, messages.KeyBotLocationUsage, nil)
+		}
+
+		removed, err := b.subscribers.RemoveLocation(subscriber, label)
+		if err != nil {
+			log.Printf("Error removing location: %v", err)
+			return b.text(language, messages.KeyBotError, nil)
+		}
+		if !removed {
+			return b.text(language, messages.KeyBotLocationNotFound, map[string]interface{}{"Label": label}) +
+				"\n\n" + b.locationHelp(language)
+		}
+	}
+
+	locations, err := b.subscribers.Locations(subscriber)
+	if err != nil {
+		log.Printf("Error loading locations: %v", err)
+		return b.text(language, messages.KeyBotError, nil)
+	}
+
+	return b.formatLocations(language, locations) + "\n\n" + b.locationHelp(language)
+}
+
+// formatLocations describes the saved locations, the template sees each with Label, Lintang and Bujur
+func (b *Bot) formatLocations(language string, locations []*db.SubscriberLocation) string {
+	var list []map[string]string
+	for _, location := range locations {
+		list = append(list, map[string]string{"Label": location.Label(), "Lintang": location.Lintang(), "Bujur": location.Bujur()})
+	}
+	return b.text(language, messages.KeyBotLocations, map[string]interface{}{"Locations": list})
+}
+
+// locationHelp explains the commands to manage saved locations
+func (b *Bot) locationHelp(language string) string {
+	return b.text(language, messages.KeyBotLocationsHelp, map[string]interface{}{"Max": repository.MaxLocationsPerSubscriber})
+}
+
+// getLabelKeyboard creates the keyboard with the suggested location names
+func (b *Bot) getLabelKeyboard(language string) tgbotapi.ReplyKeyboardMarkup {
+	var rows [][]tgbotapi.KeyboardButton
+	for _, label := range messages.Lines(b.text(language, messages.KeyBotLocationLabels, nil)) {
+		rows = append(rows, tgbotapi.NewKeyboardButtonRow(tgbotapi.NewKeyboardButton(label)))
+	}
+
+	keyboard := tgbotapi.NewReplyKeyboard(rows...)
+	keyboard.OneTimeKeyboard = true
+	return keyboard
+}
@@ -0,0 +1,160 @@
+package telegram
+
+import (
+	"bmkg/src/db"
+	"bmkg/src/domain"
+	"bmkg/src/messages"
+	"bmkg/src/repository"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// timezoneAliases zona waktu Indonesia yang bisa ditulis singkat
+var timezoneAliases = map[string]string{
+	"WIB":  "Asia/Jakarta",
+	"WITA": "Asia/Makassar",
+	"WIT":  "Asia/Jayapura",
+}
+
+// usageError asks the user to write a command correctly, Key is the message template
+type usageError struct {
+	Key  string
+	Data map[string]interface{}
+}
+
+func (e *usageError) Error() string {
+	return e.Key
+}
+
+// handlePreferences shows or changes the preferences of the chat and returns the reply
+func (b *Bot) handlePreferences(message *tgbotapi.Message, language string) string {
+	subscriber, err := b.findSubscriber(message.Chat.ID)
+	if err != nil {
+		log.Printf("Error loading subscriber: %v", err)
+		return b.text(language, messages.KeyBotError, nil)
+	}
+	if subscriber == nil {
+		return b.text(language, messages.KeyBotNoSubscriber, nil)
+	}
+
+	help := b.text(language, messages.KeyBotPreferencesHelp, nil)
+
+	preferences, err := parsePreferenceCommand(message.Command(), strings.TrimSpace(message.CommandArguments()))
+	if err != nil {
+		var usage *usageError
+		if errors.As(err, &usage) {
+			return b.text(language, usage.Key, usage.Data) + "\n\n" + help
+		}
+		return err.Error() + "\n\n" + help
+	}
+
+	if err := b.subscribers.SavePreferences(subscriber, preferences); err != nil {
+		if errors.Is(err, repository.ErrInvalidPreferences) {
+			return b.text(language, messages.KeyBotPreferencesError, map[string]interface{}{"Error": err.Error()}) + "\n\n" + help
+		}
+		log.Printf("Error saving preferences: %v", err)
+		return b.text(language, messages.KeyBotError, nil)
+	}
+
+	// a new language applies to this reply already
+	current := b.subscribers.Preferences(subscriber)
+	if preferences.Language != nil {
+		language = *preferences.Language
+	}
+	return b.formatPreferences(language, current) + "\n\n" + b.text(language, messages.KeyBotPreferencesHelp, nil)
+}
+
+// findSubscriber returns the telegram subscription of a chat, or nil before a location was sent
+func (b *Bot) findSubscriber(chatID int64) (*db.UserNotify, error) {
+	subscribers, err := b.subscribers.FindByIdentifier(strconv.FormatInt(chatID, 10))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, subscriber := range subscribers {
+		if subscriber.GetString("type") == "telegram" {
+			return subscriber, nil
+		}
+	}
+	return nil, nil
+}
+
+// parsePreferenceCommand turns a command and its argument into a preference update
+func parsePreferenceCommand(command, argument string) (domain.NotifyPreferences, error) {
+	var preferences domain.NotifyPreferences
+	off := strings.EqualFold(argument, "off")
+
+	switch command {
+	case "preferensi":
+		return preferences, nil
+	case "minmag", "minmmi", "radius":
+		value := 0.0
+		if !off {
+			parsed, err := strconv.ParseFloat(strings.ReplaceAll(argument, ",", "."), 64)
+			if err != nil {
+				return preferences, &usageError{Key: messages.KeyBotUsageNumber, Data: map[string]interface{}{"Command": command}}
+			}
+			value = parsed
+		}
+		switch command {
+		case "minmag":
+			preferences.MinMagnitude = &value
+		case "minmmi":
+			preferences.MinMMI = &value
+		default:
+			preferences.RadiusKm = &value
+		}
+	case "sunyi":
+		start, end := "", ""
+		if !off {
+			var ok bool
+			start, end, ok = strings.Cut(argument, "-")
+			if !ok {
+				return preferences, &usageError{Key: messages.KeyBotUsageQuiet}
+			}
+			start, end = strings.TrimSpace(start), strings.TrimSpace(end)
+		}
+		preferences.QuietStart, preferences.QuietEnd = &start, &end
+	case "zonawaktu":
+		timezone := argument
+		if alias, ok := timezoneAliases[strings.ToUpper(argument)]; ok {
+			timezone = alias
+		}
+		if timezone == "" {
+			return preferences, &usageError{Key: messages.KeyBotUsageTimezone}
+		}
+		preferences.Timezone = &timezone
+	case "bahasa":
+		language := strings.ToLower(argument)
+		if !messages.IsLanguage(language) {
+			return preferences, &usageError{Key: messages.KeyBotUsageLanguage}
+		}
+		preferences.Language = &language
+	}
+
+	return preferences, nil
+}
+
+// formatPreferences describes the current preferences
+func (b *Bot) formatPreferences(language string, preferences domain.NotifyPreferences) string {
+	return b.text(language, messages.KeyBotPreferences, map[string]interface{}{
+		"MinMagnitude": *preferences.MinMagnitude,
+		"MinMMI":       *preferences.MinMMI,
+		"QuietStart":   *preferences.QuietStart,
+		"QuietEnd":     *preferences.QuietEnd,
+		"Timezone":     *preferences.Timezone,
+		"Language":     *preferences.Language,
+		"RadiusKm":     *preferences.RadiusKm,
+	})
+}
+
+// reply sends a plain text message and logs a failure
+func (b *Bot) reply(chatID int64, text string) {
+	if _, err := b.api.Send(tgbotapi.NewMessage(chatID, text)); err != nil {
+		log.Printf("Error sending message: %v", err)
+	}
+}
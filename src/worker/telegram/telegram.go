@@ -1,24 +1,63 @@
 package telegram
 
 import (
-	"bmkg/src/db"
+	"bmkg/src/messages"
+	"bmkg/src/repository"
+	"errors"
 	"fmt"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
-	"github.com/pocketbase/dbx"
 	"github.com/pocketbase/pocketbase/core"
 	"log"
-	"strconv"
+	"sync"
+	"time"
 )
 
+// ErrNoToken is returned by NewBot without a bot token
+var ErrNoToken = errors.New("telegram bot token is not configured")
+
+// Config of the bot. With WebhookURL set Telegram pushes updates to the PocketBase
+// router, otherwise the bot long-polls getUpdates.
+type Config struct {
+	Token string
+	// WebhookURL is the public base URL of this server, e.g. https://gempa.example.com
+	WebhookURL string
+	// WebhookSecret is sent back by Telegram in the secret token header of every update
+	WebhookSecret string
+}
+
 // Bot represents a Telegram bot instance
 type Bot struct {
-	app core.App
-	api *tgbotapi.BotAPI
+	app         core.App
+	api         *tgbotapi.BotAPI
+	config      Config
+	subscribers *repository.Subscriber
+	earthquakes *repository.BMKG
+	felt        *repository.Felt
+	safety      *repository.Safety
+	state       *repository.State
+
+	// pending holds the shared location of a chat until the user names it
+	pendingMu sync.Mutex
+	pending   map[int64]pendingLocation
+
+	// webhook mode is decided once, by Start or AddWebhookHandler whichever runs first
+	webhookOnce   sync.Once
+	webhookActive bool
+
+	stop     chan struct{}
+	stopOnce sync.Once
 }
 
 // NewBot creates a new Bot instance
-func NewBot(app core.App) (*Bot, error) {
-	api, err := tgbotapi.NewBotAPI(token)
+func NewBot(app core.App, config Config) (*Bot, error) {
+	if config.Token == "" {
+		return nil, ErrNoToken
+	}
+	if config.WebhookURL != "" && config.WebhookSecret == "" {
+		return nil, errors.New("telegram webhook requires a webhook secret")
+	}
+
+	api, err := tgbotapi.NewBotAPI(config.Token)
 	if err != nil {
 		return nil, err
 	}
@@ -26,67 +65,152 @@ func NewBot(app core.App) (*Bot, error) {
 	api.Debug = false
 	log.Printf("Authorized on account %s", api.Self.UserName)
 
+	return newBot(app, api, config), nil
+}
+
+// newBot wires the bot to an authorized API client
+func newBot(app core.App, api *tgbotapi.BotAPI, config Config) *Bot {
 	return &Bot{
-		app: app,
-		api: api,
-	}, nil
+		app:         app,
+		api:         api,
+		config:      config,
+		subscribers: repository.NewSubscriberRepository(app),
+		earthquakes: repository.NewBMKGRepository(app),
+		felt:        repository.NewFeltRepository(app),
+		safety:      repository.NewSafetyRepository(app),
+		state:       repository.NewStateRepository(app),
+		pending:     make(map[int64]pendingLocation),
+		stop:        make(chan struct{}),
+	}
 }
 
-// Start begins listening for and processing updates
+// Start registers the commands and starts receiving updates: through the webhook when
+// configured, otherwise by long polling
 func (b *Bot) Start() {
-	u := tgbotapi.NewUpdate(0)
-	u.Timeout = 60
+	b.registerCommands()
+
+	if b.useWebhook() {
+		return
+	}
 
-	updates := b.api.GetUpdatesChan(u)
+	b.poll()
+}
 
-	for update := range updates {
-		if update.Message == nil {
+// Stop ends long polling, calling it again is a no-op
+func (b *Bot) Stop() {
+	b.stopOnce.Do(func() {
+		close(b.stop)
+	})
+}
+
+// poll long-polls getUpdates. The offset is stored in worker_state after every batch,
+// so a restart continues after the last handled update instead of reprocessing.
+func (b *Bot) poll() {
+	// getUpdates is refused while a webhook is set
+	if _, err := b.api.Request(tgbotapi.DeleteWebhookConfig{}); err != nil {
+		log.Printf("Error deleting Telegram webhook: %v", err)
+	}
+
+	var offset int
+	if _, err := b.state.Load(offsetStateKey, &offset); err != nil {
+		log.Printf("Error loading Telegram update offset: %v", err)
+	}
+
+	for {
+		select {
+		case <-b.stop:
+			return
+		default:
+		}
+
+		config := tgbotapi.NewUpdate(offset)
+		config.Timeout = pollTimeout
+		config.AllowedUpdates = allowedUpdates
+
+		updates, err := b.api.GetUpdates(config)
+		if err != nil {
+			log.Printf("Error getting Telegram updates: %v", err)
+			select {
+			case <-b.stop:
+				return
+			case <-time.After(pollRetryDelay * time.Second):
+			}
+			continue
+		}
+		if len(updates) == 0 {
 			continue
 		}
 
-		// Route message to appropriate handler
-		if update.Message.Location != nil {
-			b.handleLocationMessage(update.Message)
-		} else if update.Message.Text == "Bantuan" {
-			b.handleHelpMessage(update.Message)
-		} else {
-			b.showMainMenu(update.Message)
+		for _, update := range updates {
+			b.handleUpdate(update)
+			offset = update.UpdateID + 1
+		}
+
+		if err := b.state.Save(offsetStateKey, offset); err != nil {
+			log.Printf("Error saving Telegram update offset: %v", err)
 		}
 	}
 }
 
-// handleLocationMessage processes location data and registers user for notifications
-func (b *Bot) handleLocationMessage(message *tgbotapi.Message) {
-	location := message.Location
-	latitude := location.Latitude
-	longitude := location.Longitude
-	chatID := message.Chat.ID
+// handleUpdate routes one update to the appropriate handler
+func (b *Bot) handleUpdate(update tgbotapi.Update) {
+	if update.CallbackQuery != nil {
+		b.handleCallback(update.CallbackQuery)
+		return
+	}
+	if update.Message == nil {
+		return
+	}
 
-	// Register user in database for earthquake notifications
-	err := b.registerUserLocation(chatID, latitude, longitude)
+	// Replies are worded in the language of the chat
+	language := b.chatLanguage(update.Message.Chat.ID, update.Message.From)
+
+	if update.Message.Location != nil {
+		b.handleLocationMessage(update.Message, language)
+	} else if update.Message.Text == b.text(language, messages.KeyBotButtonHelp, nil) {
+		b.handleHelpMessage(update.Message, language)
+	} else if update.Message.IsCommand() {
+		b.handleCommand(update.Message, language)
+	} else if location, ok := b.takePendingLocation(update.Message.Chat.ID); ok {
+		b.handleLocationLabel(update.Message, location, language)
+	} else {
+		b.showMainMenu(update.Message, language)
+	}
+}
 
-	var reply tgbotapi.MessageConfig
+// chatLanguage is the language chosen with /bahasa, otherwise the language of the
+// Telegram app when it is supported. Empty means the default language.
+func (b *Bot) chatLanguage(chatID int64, from *tgbotapi.User) string {
+	subscriber, err := b.findSubscriber(chatID)
 	if err != nil {
-		log.Printf("Error registering user: %v", err)
-		reply = tgbotapi.NewMessage(chatID,
-			"Terjadi kesalahan saat menyimpan lokasi Anda. Silakan coba lagi nanti.")
-	} else {
-		reply = tgbotapi.NewMessage(chatID,
-			"Lokasi Anda telah disimpan! Anda akan menerima notifikasi gempa bumi yang terjadi di sekitar lokasi Anda.")
+		log.Printf("Error loading subscriber: %v", err)
+	}
+	if subscriber != nil && subscriber.GetString("language") != "" {
+		return subscriber.GetString("language")
 	}
 
-	if _, err := b.api.Send(reply); err != nil {
-		log.Printf("Error sending message: %v", err)
+	if from != nil && messages.IsLanguage(from.LanguageCode) {
+		return from.LanguageCode
 	}
+	return ""
+}
+
+// text renders a bot message template, falling back to the key when it cannot be rendered
+func (b *Bot) text(language, key string, data interface{}) string {
+	text, err := messages.Render(key, messages.Selector{Channel: "telegram", Language: language}, data)
+	if err != nil {
+		log.Printf("Error rendering message: %v", err)
+		return key
+	}
+	return text
 }
 
 // handleHelpMessage sends help information to the user
-func (b *Bot) handleHelpMessage(message *tgbotapi.Message) {
+func (b *Bot) handleHelpMessage(message *tgbotapi.Message, language string) {
 	reply := tgbotapi.NewMessage(message.Chat.ID,
-		"Selamat datang di bot ini!\n\n"+
-			"Anda dapat mengirimkan lokasi dengan menekan tombol 'Kirim Lokasi'.")
+		b.text(language, messages.KeyBotWelcome, nil)+"\n\n"+b.commandsHelp(language))
 
-	reply.ReplyMarkup = b.getMainMenuKeyboard()
+	reply.ReplyMarkup = b.getMainMenuKeyboard(language)
 
 	if _, err := b.api.Send(reply); err != nil {
 		log.Printf("Error sending message: %v", err)
@@ -94,11 +218,10 @@ func (b *Bot) handleHelpMessage(message *tgbotapi.Message) {
 }
 
 // showMainMenu displays the main menu options
-func (b *Bot) showMainMenu(message *tgbotapi.Message) {
-	reply := tgbotapi.NewMessage(message.Chat.ID,
-		"Silakan kirim lokasi Anda atau pilih opsi lainnya:")
+func (b *Bot) showMainMenu(message *tgbotapi.Message, language string) {
+	reply := tgbotapi.NewMessage(message.Chat.ID, b.text(language, messages.KeyBotMenu, nil))
 
-	reply.ReplyMarkup = b.getMainMenuKeyboard()
+	reply.ReplyMarkup = b.getMainMenuKeyboard(language)
 
 	if _, err := b.api.Send(reply); err != nil {
 		log.Printf("Error sending message: %v", err)
@@ -106,56 +229,17 @@ func (b *Bot) showMainMenu(message *tgbotapi.Message) {
 }
 
 // getMainMenuKeyboard creates the keyboard for main menu
-func (b *Bot) getMainMenuKeyboard() tgbotapi.ReplyKeyboardMarkup {
+func (b *Bot) getMainMenuKeyboard(language string) tgbotapi.ReplyKeyboardMarkup {
 	return tgbotapi.NewReplyKeyboard(
 		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButtonLocation("Kirim Lokasi"),
+			tgbotapi.NewKeyboardButtonLocation(b.text(language, messages.KeyBotButtonLocation, nil)),
 		),
 		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton("Bantuan"),
+			tgbotapi.NewKeyboardButton(b.text(language, messages.KeyBotButtonHelp, nil)),
 		),
 	)
 }
 
-// registerUserLocation saves the user's location in the database
-func (b *Bot) registerUserLocation(chatID int64, latitude, longitude float64) error {
-	// Implement the logic to save the user's location in the database
-	// This is a placeholder for actual database interaction
-	fmt.Printf("Registering user with chat ID %d at location (%f, %f)\n", chatID, latitude, longitude)
-
-	d, _ := db.NewProxy[db.UserNotify](b.app)
-	//d := &db.UserNotify{}
-
-	err := b.app.RecordQuery("user_notify").
-		AndWhere(dbx.NewExp("identifier={:identifier}", dbx.Params{
-			"identifier": fmt.Sprintf("%d", chatID), // convert int64 to string
-		})).
-		Limit(1).
-		One(d)
-
-	if err != nil {
-		fmt.Printf("Failed to get user notify from db: %v\n", err)
-
-	}
-
-	d.SetIdentifier(strconv.FormatInt(chatID, 10))
-	d.SetLintang(strconv.FormatFloat(latitude, 'f', -1, 64))
-	d.SetBujur(strconv.FormatFloat(longitude, 'f', -1, 64))
-
-	d.SetType(db.Telegram)
-
-	err = b.app.Save(d)
-	if err != nil {
-		return err
-	}
-
-	// Example: Save to database (pseudo-code)
-	// err := b.app.DB().SaveUserLocation(chatID, latitude, longitude)
-	// return err
-
-	return nil
-}
-
 // SendMessage sends a message to a specific chat ID
 func (b *Bot) SendMessage(chatID int64, message string) error {
 	msg := tgbotapi.NewMessage(chatID, message)
@@ -167,8 +251,8 @@ func (b *Bot) SendMessage(chatID int64, message string) error {
 }
 
 // Run initializes and starts the bot
-func Run(app core.App) {
-	bot, err := NewBot(app)
+func Run(app core.App, config Config) {
+	bot, err := NewBot(app, config)
 	if err != nil {
 		log.Panic(err)
 	}
@@ -0,0 +1,85 @@
+package telegram
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
+)
+
+// webhookRoute is the secret route Telegram posts updates to. The last path segment is
+// derived from the token and secret, so it cannot be guessed from the public URL alone.
+func (b *Bot) webhookRoute() string {
+	sum := sha256.Sum256([]byte(b.config.Token + ":" + b.config.WebhookSecret))
+	return webhookPath + hex.EncodeToString(sum[:16])
+}
+
+// setWebhook points Telegram to the webhook route with the secret token
+func (b *Bot) setWebhook() error {
+	allowed, err := json.Marshal(allowedUpdates)
+	if err != nil {
+		return err
+	}
+
+	params := tgbotapi.Params{
+		"url":             strings.TrimRight(b.config.WebhookURL, "/") + b.webhookRoute(),
+		"secret_token":    b.config.WebhookSecret,
+		"allowed_updates": string(allowed),
+	}
+	if _, err := b.api.MakeRequest("setWebhook", params); err != nil {
+		return fmt.Errorf("failed to set webhook: %w", err)
+	}
+
+	log.Printf("Telegram webhook set to %s%s...", strings.TrimRight(b.config.WebhookURL, "/"), webhookPath)
+	return nil
+}
+
+// useWebhook sets the webhook once and reports whether the bot runs in webhook mode.
+// When setWebhook fails the bot long-polls and the route stays unregistered.
+func (b *Bot) useWebhook() bool {
+	b.webhookOnce.Do(func() {
+		if b.config.WebhookURL == "" {
+			return
+		}
+		if err := b.setWebhook(); err != nil {
+			log.Printf("Error setting Telegram webhook, falling back to long polling: %v", err)
+			return
+		}
+		b.webhookActive = true
+	})
+	return b.webhookActive
+}
+
+// AddWebhookHandler registers the webhook route to the router, only in webhook mode
+func (b *Bot) AddWebhookHandler(router *router.Router[*core.RequestEvent]) {
+	if !b.useWebhook() {
+		return
+	}
+
+	router.POST(b.webhookRoute(), b.webhook)
+}
+
+// webhook verifies the secret token header and handles the update
+func (b *Bot) webhook(e *core.RequestEvent) error {
+	token := e.Request.Header.Get(secretTokenHeader)
+	if subtle.ConstantTimeCompare([]byte(token), []byte(b.config.WebhookSecret)) != 1 {
+		return e.UnauthorizedError("Invalid secret token", nil)
+	}
+
+	var update tgbotapi.Update
+	if err := json.NewDecoder(e.Request.Body).Decode(&update); err != nil {
+		return e.BadRequestError("Invalid update", err)
+	}
+
+	b.handleUpdate(update)
+
+	return e.NoContent(http.StatusOK)
+}
@@ -0,0 +1,80 @@
+package telegram
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/apis"
+)
+
+// postWebhook posts an empty update to the webhook route and returns the status code
+func postWebhook(t *testing.T, bot *Bot, secret string) int {
+	t.Helper()
+
+	r, err := apis.NewRouter(bot.app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bot.AddWebhookHandler(r)
+
+	mux, err := r.BuildMux()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	request := httptest.NewRequest(http.MethodPost, bot.webhookRoute(), strings.NewReader(`{"update_id":1}`))
+	request.Header.Set(secretTokenHeader, secret)
+	recorder := httptest.NewRecorder()
+	mux.ServeHTTP(recorder, request)
+
+	return recorder.Code
+}
+
+func TestWebhookRouteOnlyWhenSet(t *testing.T) {
+	config := Config{WebhookURL: "https://gempa.example.com/", WebhookSecret: "s3cret"}
+
+	bot, fake, _ := newTestBot(t, config)
+	if code := postWebhook(t, bot, "s3cret"); code != http.StatusOK {
+		t.Errorf("webhook mode: got status %d, want %d", code, http.StatusOK)
+	}
+	if code := postWebhook(t, bot, "wrong"); code != http.StatusUnauthorized {
+		t.Errorf("wrong secret: got status %d, want %d", code, http.StatusUnauthorized)
+	}
+	// Start and every AddWebhookHandler share the one setWebhook call
+	bot.Start()
+	calls := fake.methodCalls("setWebhook")
+	if len(calls) != 1 {
+		t.Fatalf("got %d setWebhook calls, want 1", len(calls))
+	}
+	if url := calls[0].Params["url"]; url != "https://gempa.example.com"+bot.webhookRoute() {
+		t.Errorf("webhook url = %q", url)
+	}
+
+	// setWebhook failed: the bot long-polls and the route must not exist
+	bot, fake, _ = newTestBot(t, config)
+	fake.failures["setWebhook"] = "Bad Request: bad webhook"
+	if code := postWebhook(t, bot, "s3cret"); code != http.StatusNotFound {
+		t.Errorf("failed setWebhook: got status %d, want %d", code, http.StatusNotFound)
+	}
+
+	// long polling mode
+	bot, _, _ = newTestBot(t, Config{})
+	if code := postWebhook(t, bot, ""); code != http.StatusNotFound {
+		t.Errorf("polling mode: got status %d, want %d", code, http.StatusNotFound)
+	}
+}
+
+func TestStopTwice(t *testing.T) {
+	bot, _, _ := newTestBot(t, Config{})
+
+	bot.Stop()
+	bot.Stop()
+
+	select {
+	case <-bot.stop:
+	default:
+		t.Error("stop channel is not closed")
+	}
+}